@@ -102,6 +102,7 @@ func (invoker *baseClusterInvoker) doSelect(lb cluster.LoadBalance, invocation p
 
 	if sticky && invoker.availablecheck &&
 		invoker.stickyInvoker != nil && invoker.stickyInvoker.IsAvailable() &&
+		!protocol.IsCircuitBreakerOpen(invoker.stickyInvoker.GetURL(), invocation.MethodName()) &&
 		(invoked == nil || !isInvoked(invoker.stickyInvoker, invoked)) {
 		return invoker.stickyInvoker
 	}
@@ -117,6 +118,13 @@ func (invoker *baseClusterInvoker) doSelectInvoker(lb cluster.LoadBalance, invoc
 	if len(invokers) == 0 {
 		return nil
 	}
+	// the invokers whose circuit breakers are open are unavailable
+	closedInvokers := getClosedCircuitInvokers(invokers, invocation)
+	if len(closedInvokers) == 0 {
+		logger.Errorf("the circuit breakers of all invokers of %s are open.", invokers[0].GetURL().ServiceKey())
+		return nil
+	}
+	invokers = closedInvokers
 	go protocol.TryRefreshBlackList()
 	if len(invokers) == 1 {
 		if invokers[0].IsAvailable() {
@@ -160,6 +168,16 @@ func (invoker *baseClusterInvoker) doSelectInvoker(lb cluster.LoadBalance, invoc
 	return nil
 }
 
+// noSelectedInvokerError returns the error when no invoker is selected from @invokers,
+// its cause is ErrCircuitBreakerOpen if the circuit breakers of all invokers are open
+func (invoker *baseClusterInvoker) noSelectedInvokerError(invokers []protocol.Invoker, invocation protocol.Invocation) error {
+	serviceKey := invoker.GetURL().ServiceKey()
+	if len(getClosedCircuitInvokers(invokers, invocation)) == 0 {
+		return perrors.Wrapf(protocol.ErrCircuitBreakerOpen, "the circuit breakers of all invokers of %s are open", serviceKey)
+	}
+	return perrors.Errorf("no available invoker of %s to invoke the method %v", serviceKey, invocation.MethodName())
+}
+
 func isInvoked(selectedInvoker protocol.Invoker, invoked []protocol.Invoker) bool {
 	for _, i := range invoked {
 		if i == selectedInvoker {
@@ -183,6 +201,28 @@ func getLoadBalance(invoker protocol.Invoker, invocation protocol.Invocation) cl
 	return extension.GetLoadbalance(lb)
 }
 
+// getClosedCircuitInvokers excludes the invokers whose circuit breakers are open, @invokers is returned
+// without copying if there is not any
+func getClosedCircuitInvokers(invokers []protocol.Invoker, invocation protocol.Invocation) []protocol.Invoker {
+	var closedInvokers []protocol.Invoker
+	for idx, i := range invokers {
+		if !protocol.IsCircuitBreakerOpen(i.GetURL(), invocation.MethodName()) {
+			if closedInvokers != nil {
+				closedInvokers = append(closedInvokers, i)
+			}
+			continue
+		}
+		if closedInvokers == nil {
+			closedInvokers = make([]protocol.Invoker, idx, len(invokers))
+			copy(closedInvokers, invokers[:idx])
+		}
+	}
+	if closedInvokers == nil {
+		return invokers
+	}
+	return closedInvokers
+}
+
 func getOtherInvokers(invokers []protocol.Invoker, invoker protocol.Invoker) []protocol.Invoker {
 	otherInvokers := make([]protocol.Invoker, 0)
 	for _, i := range invokers {
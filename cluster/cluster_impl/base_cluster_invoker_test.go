@@ -18,6 +18,8 @@
 package cluster_impl
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -27,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/cluster/loadbalance"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -72,3 +75,53 @@ func TestStickyNormalWhenError(t *testing.T) {
 	result1 := base.doSelect(loadbalance.NewRandomLoadBalance(), invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil), invokers, invoked)
 	assert.NotEqual(t, result, result1)
 }
+
+func TestSelectSkipOpenCircuitBreaker(t *testing.T) {
+	defer protocol.CleanAllCircuitBreakers()
+	invokers := []protocol.Invoker{}
+	for i := 0; i < 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf(baseClusterInvokerFormat, i))
+		url.SetParam("circuitbreaker.minimum.calls", "1")
+		invokers = append(invokers, NewMockInvoker(url, 1))
+	}
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+	for _, i := range invokers[:2] {
+		breaker := protocol.GetCircuitBreaker(i.GetURL(), baseClusterInvokerMethodName, nil)
+		assert.True(t, breaker.Allow())
+		breaker.OnResult(0, true)
+		assert.Equal(t, protocol.CircuitOpen, breaker.State())
+	}
+
+	base := &baseClusterInvoker{}
+	base.availablecheck = true
+	for i := 0; i < 10; i++ {
+		result := base.doSelect(loadbalance.NewRandomLoadBalance(), inv, invokers, nil)
+		assert.Equal(t, invokers[2], result)
+	}
+
+	breaker := protocol.GetCircuitBreaker(invokers[2].GetURL(), baseClusterInvokerMethodName, nil)
+	breaker.OnResult(0, true)
+	assert.Nil(t, base.doSelect(loadbalance.NewRandomLoadBalance(), inv, invokers, nil))
+}
+
+func TestInvokeWithAllCircuitBreakersOpen(t *testing.T) {
+	defer protocol.CleanAllCircuitBreakers()
+	invokers := []protocol.Invoker{}
+	for i := 0; i < 2; i++ {
+		url, _ := common.NewURL(fmt.Sprintf(baseClusterInvokerFormat, i))
+		url.SetParam("circuitbreaker.minimum.calls", "1")
+		invokers = append(invokers, NewMockInvoker(url, 1))
+		protocol.GetCircuitBreaker(url, baseClusterInvokerMethodName, nil).OnResult(0, true)
+	}
+	dir := directory.NewStaticDirectory(invokers)
+	inv := invocation.NewRPCInvocation(baseClusterInvokerMethodName, nil, nil)
+
+	for _, clusterInvoker := range []protocol.Invoker{
+		newFailFastClusterInvoker(dir),
+		newFailsafeClusterInvoker(dir),
+		newFailbackClusterInvoker(dir),
+	} {
+		result := clusterInvoker.Invoke(context.Background(), inv)
+		assert.True(t, errors.Is(result.Error(), protocol.ErrCircuitBreakerOpen))
+	}
+}
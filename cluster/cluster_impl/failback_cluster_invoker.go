@@ -77,6 +77,10 @@ func (invoker *failbackClusterInvoker) tryTimerTaskProc(ctx context.Context, ret
 	invoked = append(invoked, retryTask.lastInvoker)
 
 	retryInvoker := invoker.doSelect(retryTask.loadbalance, retryTask.invocation, retryTask.invokers, invoked)
+	if retryInvoker == nil {
+		invoker.checkRetry(retryTask, invoker.noSelectedInvokerError(retryTask.invokers, retryTask.invocation))
+		return
+	}
 	result := retryInvoker.Invoke(ctx, retryTask.invocation)
 	if result.Error() != nil {
 		retryTask.lastInvoker = retryInvoker
@@ -149,6 +153,9 @@ func (invoker *failbackClusterInvoker) Invoke(ctx context.Context, invocation pr
 	loadBalance := extension.GetLoadbalance(lb)
 	invoked := make([]protocol.Invoker, 0, len(invokers))
 	ivk := invoker.doSelect(loadBalance, invocation, invokers, invoked)
+	if ivk == nil {
+		return &protocol.RPCResult{Err: invoker.noSelectedInvokerError(invokers, invocation)}
+	}
 	// DO INVOKE
 	result := ivk.Invoke(ctx, invocation)
 	if result.Error() != nil {
@@ -52,5 +52,8 @@ func (invoker *failfastClusterInvoker) Invoke(ctx context.Context, invocation pr
 	}
 
 	ivk := invoker.doSelect(loadbalance, invocation, invokers, nil)
+	if ivk == nil {
+		return &protocol.RPCResult{Err: invoker.noSelectedInvokerError(invokers, invocation)}
+	}
 	return ivk.Invoke(ctx, invocation)
 }
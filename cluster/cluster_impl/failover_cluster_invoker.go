@@ -62,11 +62,22 @@ func (invoker *failoverClusterInvoker) Invoke(ctx context.Context, invocation pr
 	methodName := invocation.MethodName()
 	retries := getRetries(invokers, methodName)
 	loadBalance := getLoadBalance(invokers[0], invocation)
+	budget := getRetryBudget(invokers[0].GetURL())
+	if budget != nil {
+		budget.deposit()
+	}
+	if hedging := getHedgingConfig(invokers[0].GetURL(), methodName); hedging != nil {
+		return invoker.invokeWithHedging(ctx, invocation, invokers, loadBalance, hedging, budget)
+	}
 
 	for i := 0; i <= retries; i++ {
 		// Reselect before retry to avoid a change of candidate `invokers`.
 		// NOTE: if `invokers` changed, then `invoked` also lose accuracy.
 		if i > 0 {
+			if budget != nil && !budget.tryWithdraw() {
+				logger.Warnf("the retry budget of %s is exhausted, stop retrying the method %s", invokers[0].GetURL().ServiceKey(), methodName)
+				break
+			}
 			if err := invoker.checkWhetherDestroyed(); err != nil {
 				return &protocol.RPCResult{Err: err}
 			}
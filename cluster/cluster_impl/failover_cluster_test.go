@@ -187,3 +187,22 @@ func TestFailoverDestroy(t *testing.T) {
 	clusterInvoker.Destroy()
 	assert.Equal(t, false, clusterInvoker.IsAvailable())
 }
+
+// nolint
+func TestFailoverInvokeRetryBudgetExhausted(t *testing.T) {
+	urlParams := url.Values{}
+	urlParams.Set(constant.RETRIES_KEY, "3")
+	urlParams.Set(constant.RETRY_BUDGET_RATIO_KEY, "0.1")
+	urlParams.Set(constant.RETRY_BUDGET_MIN_KEY, "0")
+	budgetURL, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider", common.WithParams(urlParams))
+	defer retryBudgets.Delete(budgetURL.ServiceKey())
+	budget := getRetryBudget(budgetURL)
+	for budget.tryWithdraw() {
+	}
+
+	// the request deposits 0.1 token which is not enough to retry
+	result := normalInvoke(2, urlParams)
+	assert.Error(t, result.Error())
+	assert.Equal(t, 1, count)
+	count = 0
+}
@@ -69,6 +69,9 @@ func (invoker *failsafeClusterInvoker) Invoke(ctx context.Context, invocation pr
 	var result protocol.Result
 
 	ivk := invoker.doSelect(loadbalance, invocation, invokers, invoked)
+	if ivk == nil {
+		return &protocol.RPCResult{Err: invoker.noSelectedInvokerError(invokers, invocation)}
+	}
 	// DO INVOKE
 	result = ivk.Invoke(ctx, invocation)
 	if result.Error() != nil {
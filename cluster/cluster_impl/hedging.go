@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	defaultHedgingPercentile = 95
+	defaultHedgingMax        = 1
+	// latencySamples is the number of the latest latencies to calculate the percentile
+	latencySamples = 200
+	// minLatencySamples is the number of latencies required before hedging, no hedged request is sent
+	// until the latency percentile is known
+	minLatencySamples = 20
+	// latencyRefreshSamples is the number of new latencies after which the percentile is calculated again
+	latencyRefreshSamples = 20
+)
+
+var latencyTrackers sync.Map // service key + "#" + method name -> *latencyTracker
+
+// latencyTracker keeps the latest latencies of a method to calculate the hedging delay.
+type latencyTracker struct {
+	mutex      sync.Mutex
+	samples    []time.Duration
+	next       int
+	unsorted   int
+	percentile float64
+	cached     time.Duration
+}
+
+func getLatencyTracker(url *common.URL, methodName string) *latencyTracker {
+	key := url.ServiceKey() + "#" + methodName
+	tracker, found := latencyTrackers.Load(key)
+	if !found {
+		tracker, _ = latencyTrackers.LoadOrStore(key, &latencyTracker{samples: make([]time.Duration, 0, latencySamples)})
+	}
+	return tracker.(*latencyTracker)
+}
+
+// record adds the latency of a successful request.
+func (t *latencyTracker) record(latency time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.samples) < latencySamples {
+		t.samples = append(t.samples, latency)
+	} else {
+		t.samples[t.next] = latency
+		t.next = (t.next + 1) % latencySamples
+	}
+	t.unsorted++
+}
+
+// get returns the @percentile latency, false means there are not enough samples.
+func (t *latencyTracker) get(percentile float64) (time.Duration, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if len(t.samples) < minLatencySamples {
+		return 0, false
+	}
+	if t.unsorted >= latencyRefreshSamples || t.percentile != percentile || t.cached == 0 {
+		sorted := make([]time.Duration, len(t.samples))
+		copy(sorted, t.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		idx := int(float64(len(sorted))*percentile/100+0.5) - 1
+		if idx < 0 {
+			idx = 0
+		} else if idx >= len(sorted) {
+			idx = len(sorted) - 1
+		}
+		t.cached, t.percentile, t.unsorted = sorted[idx], percentile, 0
+	}
+	return t.cached, true
+}
+
+// hedgingConfig is the hedging config of a method, nil means hedging is disabled
+type hedgingConfig struct {
+	percentile float64
+	max        int
+}
+
+func getHedgingConfig(url *common.URL, methodName string) *hedgingConfig {
+	enabled, _ := strconv.ParseBool(url.GetMethodParam(methodName, constant.HEDGING_KEY, url.GetParam(constant.HEDGING_KEY, "")))
+	if !enabled {
+		return nil
+	}
+	cfg := &hedgingConfig{percentile: defaultHedgingPercentile, max: defaultHedgingMax}
+	percentile := url.GetMethodParam(methodName, constant.HEDGING_PERCENTILE_KEY, url.GetParam(constant.HEDGING_PERCENTILE_KEY, ""))
+	if p, err := strconv.ParseFloat(percentile, 64); err == nil && p > 0 && p <= 100 {
+		cfg.percentile = p
+	}
+	max := url.GetMethodParam(methodName, constant.HEDGING_MAX_KEY, url.GetParam(constant.HEDGING_MAX_KEY, ""))
+	if m, err := strconv.Atoi(max); err == nil && m >= 0 {
+		cfg.max = m
+	}
+	return cfg
+}
+
+type hedgedResult struct {
+	invoker protocol.Invoker
+	result  protocol.Result
+	elapsed time.Duration
+}
+
+// invokeWithHedging sends the request to an invoker, and sends a hedged request to a different invoker
+// whenever the configured percentile latency elapses or a request fails, until the max number of hedged
+// requests is reached. The first successful result is returned and the others are cancelled.
+// Every hedged request takes a token of @budget if it is not nil.
+func (invoker *failoverClusterInvoker) invokeWithHedging(ctx context.Context, invocation protocol.Invocation,
+	invokers []protocol.Invoker, lb cluster.LoadBalance, cfg *hedgingConfig, budget *retryBudget) protocol.Result {
+	url := invokers[0].GetURL()
+	methodName := invocation.MethodName()
+	tracker := getLatencyTracker(url, methodName)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resultCh := make(chan hedgedResult, cfg.max+1)
+	var invoked []protocol.Invoker
+	send := func() bool {
+		ivk := invoker.doSelect(lb, invocation, invokers, invoked)
+		if ivk == nil || isInvoked(ivk, invoked) {
+			return false
+		}
+		invoked = append(invoked, ivk)
+		// every request has its own invocation to avoid filling the same reply concurrently
+		go func(inv protocol.Invocation) {
+			start := time.Now()
+			result := ivk.Invoke(ctx, inv)
+			resultCh <- hedgedResult{invoker: ivk, result: result, elapsed: time.Since(start)}
+		}(copyInvocation(invocation))
+		return true
+	}
+	hedge := func() bool {
+		if len(invoked) > cfg.max {
+			return false
+		}
+		if budget != nil && !budget.tryWithdraw() {
+			logger.Warnf("the retry budget of %s is exhausted, no hedged request of method %s is sent", url.ServiceKey(), methodName)
+			return false
+		}
+		return send()
+	}
+
+	if !send() {
+		return &protocol.RPCResult{
+			Err: perrors.Errorf("Failed to invoke the method %s of the service %s .No provider is available.",
+				methodName, url.Service()),
+		}
+	}
+	var (
+		timer   *time.Timer
+		timerCh <-chan time.Time
+	)
+	delay, ok := tracker.get(cfg.percentile)
+	if ok && cfg.max > 0 {
+		timer = time.NewTimer(delay)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+
+	var (
+		lastErr   error
+		providers []string
+	)
+	for pending := 1; pending > 0; {
+		select {
+		case res := <-resultCh:
+			pending--
+			if res.result.Error() == nil {
+				tracker.record(res.elapsed)
+				return &protocol.RPCResult{
+					Attrs: res.result.Attachments(),
+					Rest:  setReply(invocation.Reply(), resultValue(res.result)),
+				}
+			}
+			lastErr = res.result.Error()
+			providers = append(providers, res.invoker.GetURL().Key())
+			// a failed request triggers a hedged request at once
+			if hedge() {
+				pending++
+			}
+		case <-timerCh:
+			if hedge() {
+				pending++
+				timer.Reset(delay)
+			} else {
+				timerCh = nil
+			}
+		case <-ctx.Done():
+			return &protocol.RPCResult{Err: ctx.Err()}
+		}
+	}
+	return &protocol.RPCResult{
+		Err: perrors.Wrapf(lastErr, "Failed to invoke the method %v in the service %v with %v hedged requests to the providers %v",
+			methodName, url.Service(), len(invoked)-1, providers),
+	}
+}
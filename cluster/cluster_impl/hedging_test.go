@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type hedgingTestInvoker struct {
+	protocol.BaseInvoker
+	delay time.Duration
+	fail  bool
+	reply string
+	calls int32
+}
+
+func newHedgingTestInvoker(ip string, delay time.Duration, fail bool) *hedgingTestInvoker {
+	url, _ := common.NewURL(fmt.Sprintf("dubbo://%s:20000/com.ikurento.user.UserProvider?hedging=true", ip))
+	return &hedgingTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), delay: delay, fail: fail, reply: ip}
+}
+
+func (ivk *hedgingTestInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	atomic.AddInt32(&ivk.calls, 1)
+	select {
+	case <-time.After(ivk.delay):
+	case <-ctx.Done():
+		return &protocol.RPCResult{Err: ctx.Err()}
+	}
+	if ivk.fail {
+		return &protocol.RPCResult{Err: perrors.New("error")}
+	}
+	*inv.Reply().(*string) = ivk.reply
+	return &protocol.RPCResult{Rest: inv.Reply(), Attrs: map[string]interface{}{"from": ivk.reply}}
+}
+
+// firstLoadBalance always selects the first invoker
+type firstLoadBalance struct{}
+
+func (lb *firstLoadBalance) Select(invokers []protocol.Invoker, _ protocol.Invocation) protocol.Invoker {
+	return invokers[0]
+}
+
+func invokeWithHedging(invokers []protocol.Invoker, cfg *hedgingConfig, budget *retryBudget) (protocol.Result, string) {
+	clusterInvoker := &failoverClusterInvoker{baseClusterInvoker: newBaseClusterInvoker(directory.NewStaticDirectory(invokers))}
+	var reply string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"), invocation.WithReply(&reply))
+	result := clusterInvoker.invokeWithHedging(context.Background(), inv, invokers, &firstLoadBalance{}, cfg, budget)
+	return result, reply
+}
+
+func warmUpLatencyTracker(url *common.URL, latency time.Duration) {
+	tracker := getLatencyTracker(url, "GetUser")
+	for i := 0; i < minLatencySamples; i++ {
+		tracker.record(latency)
+	}
+}
+
+func TestLatencyTracker(t *testing.T) {
+	tracker := &latencyTracker{}
+	for i := 1; i < minLatencySamples; i++ {
+		tracker.record(time.Duration(i) * time.Millisecond)
+	}
+	_, ok := tracker.get(95)
+	assert.False(t, ok)
+
+	for i := minLatencySamples; i <= latencySamples; i++ {
+		tracker.record(time.Duration(i) * time.Millisecond)
+	}
+	latency, ok := tracker.get(95)
+	assert.True(t, ok)
+	assert.Equal(t, 190*time.Millisecond, latency)
+	latency, _ = tracker.get(50)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	// the oldest samples are replaced
+	for i := 0; i < latencySamples; i++ {
+		tracker.record(time.Second)
+	}
+	latency, _ = tracker.get(50)
+	assert.Equal(t, time.Second, latency)
+}
+
+func TestGetHedgingConfig(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?" +
+		"hedging.percentile=90&methods.GetUser.hedging=true&methods.GetUser.hedging.max=2")
+	assert.Nil(t, getHedgingConfig(url, "Save"))
+	assert.Equal(t, &hedgingConfig{percentile: 90, max: 2}, getHedgingConfig(url, "GetUser"))
+}
+
+func TestHedgingAfterPercentileLatency(t *testing.T) {
+	slow := newHedgingTestInvoker("192.168.1.1", time.Second, false)
+	fast := newHedgingTestInvoker("192.168.1.2", 0, false)
+	warmUpLatencyTracker(slow.GetURL(), 20*time.Millisecond)
+	defer latencyTrackers.Delete(slow.GetURL().ServiceKey() + "#GetUser")
+
+	start := time.Now()
+	result, reply := invokeWithHedging([]protocol.Invoker{slow, fast}, &hedgingConfig{percentile: 95, max: 1}, nil)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "192.168.1.2", reply)
+	assert.Equal(t, &reply, result.Result())
+	assert.Equal(t, "192.168.1.2", result.Attachment("from", ""))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slow.calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fast.calls))
+}
+
+func TestHedgingWithoutLatencySamples(t *testing.T) {
+	slow := newHedgingTestInvoker("192.168.1.1", 50*time.Millisecond, false)
+	fast := newHedgingTestInvoker("192.168.1.2", 0, false)
+	defer latencyTrackers.Delete(slow.GetURL().ServiceKey() + "#GetUser")
+
+	// no hedged request is sent until the latency percentile is known
+	result, reply := invokeWithHedging([]protocol.Invoker{slow, fast}, &hedgingConfig{percentile: 95, max: 1}, nil)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "192.168.1.1", reply)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&fast.calls))
+}
+
+func TestHedgingAfterFailure(t *testing.T) {
+	failed := newHedgingTestInvoker("192.168.1.1", 0, true)
+	ok := newHedgingTestInvoker("192.168.1.2", 0, false)
+	defer latencyTrackers.Delete(failed.GetURL().ServiceKey() + "#GetUser")
+
+	result, reply := invokeWithHedging([]protocol.Invoker{failed, ok}, &hedgingConfig{percentile: 95, max: 1}, nil)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "192.168.1.2", reply)
+
+	// the hedged requests are limited by the retry budget
+	budget := newRetryBudget(0.1, 0)
+	for budget.tryWithdraw() {
+	}
+	result, _ = invokeWithHedging([]protocol.Invoker{failed, ok}, &hedgingConfig{percentile: 95, max: 1}, budget)
+	assert.Error(t, result.Error())
+
+	// and the max number of hedged requests
+	failed1 := newHedgingTestInvoker("192.168.1.3", 0, true)
+	result, _ = invokeWithHedging([]protocol.Invoker{failed, failed1, ok}, &hedgingConfig{percentile: 95, max: 1}, nil)
+	assert.Error(t, result.Error())
+}
@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+type mergeableCluster struct{}
+
+func init() {
+	extension.SetCluster(constant.MERGEABLE_CLUSTER_NAME, NewMergeableCluster)
+}
+
+// NewMergeableCluster returns a mergeable cluster instance.
+//
+// Invoke every group of the service concurrently and merge the results by the merger
+// configured by merger param, such as querying the menu items of all groups.
+func NewMergeableCluster() cluster.Cluster {
+	return &mergeableCluster{}
+}
+
+// Join returns a mergeableClusterInvoker instance
+func (cluster *mergeableCluster) Join(directory cluster.Directory) protocol.Invoker {
+	return buildInterceptorChain(newMergeableClusterInvoker(directory))
+}
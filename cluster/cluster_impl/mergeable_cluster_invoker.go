@@ -0,0 +1,312 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type mergeableClusterInvoker struct {
+	baseClusterInvoker
+}
+
+type groupResult struct {
+	index  int
+	result protocol.Result
+}
+
+func newMergeableClusterInvoker(directory cluster.Directory) protocol.Invoker {
+	return &mergeableClusterInvoker{
+		baseClusterInvoker: newBaseClusterInvoker(directory),
+	}
+}
+
+// Invoke calls all the groups concurrently and merges their results if merger is configured,
+// otherwise it calls the first available group only. The providers of a single group are not
+// merged, one of them is selected by the load balance.
+func (invoker *mergeableClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	if err := invoker.checkWhetherDestroyed(); err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+
+	invokers := invoker.directory.List(invocation)
+	if err := invoker.checkInvokers(invokers, invocation); err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+
+	// the directory does not wrap the providers into group invokers if there is only one group
+	if isSingleGroup(invokers) {
+		ivk := invoker.doSelect(getLoadBalance(invokers[0], invocation), invocation, invokers, nil)
+		if ivk == nil {
+			return &protocol.RPCResult{Err: invoker.noSelectedInvokerError(invokers, invocation)}
+		}
+		return ivk.Invoke(ctx, invocation)
+	}
+
+	url := invoker.GetURL()
+	if url.SubURL != nil {
+		url = url.SubURL
+	}
+	methodName := invocation.MethodName()
+	mergerName := strings.TrimSpace(url.GetMethodParam(methodName, constant.MERGER_KEY, url.GetParam(constant.MERGER_KEY, "")))
+	if len(mergerName) == 0 || mergerName == "false" {
+		for _, ivk := range invokers {
+			if ivk.IsAvailable() {
+				return ivk.Invoke(ctx, invocation)
+			}
+		}
+		return invokers[0].Invoke(ctx, invocation)
+	}
+
+	timeout := getMergeTimeout(url, methodName)
+	failfast := url.GetMethodParam(methodName, constant.MERGER_FAIL_POLICY_KEY,
+		url.GetParam(constant.MERGER_FAIL_POLICY_KEY, constant.MERGER_FAILSAFE_POLICY)) == constant.MERGER_FAILFAST_POLICY
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resultCh := make(chan groupResult, len(invokers))
+	for i, ivk := range invokers {
+		// every group has its own invocation to avoid filling the same reply concurrently
+		go func(index int, ivk protocol.Invoker, inv protocol.Invocation) {
+			resultCh <- groupResult{index: index, result: ivk.Invoke(ctx, inv)}
+		}(i, ivk, copyInvocation(invocation))
+	}
+
+	results := make([]protocol.Result, len(invokers))
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+collect:
+	for received := 0; received < len(invokers); received++ {
+		select {
+		case groupRes := <-resultCh:
+			results[groupRes.index] = groupRes.result
+		case <-timer.C:
+			break collect
+		}
+	}
+
+	var (
+		values  []interface{}
+		lastErr error
+	)
+	for i, result := range results {
+		group := invokers[i].GetURL().GetParam(constant.GROUP_KEY, "")
+		var err error
+		if result == nil {
+			err = perrors.Errorf("invoke method %s of group %s timeout after %v", methodName, group, timeout)
+		} else if result.Error() != nil {
+			err = perrors.WithMessagef(result.Error(), "failed to invoke method %s of group %s", methodName, group)
+		}
+		if err != nil {
+			if failfast {
+				return &protocol.RPCResult{Err: err}
+			}
+			logger.Warnf("mergeable cluster ignores the failed group: %v", err)
+			lastErr = err
+			continue
+		}
+		values = append(values, resultValue(result))
+	}
+	if len(values) == 0 {
+		return &protocol.RPCResult{Err: perrors.WithMessagef(lastErr, "failed to invoke all groups of method %s", methodName)}
+	}
+
+	merged := values[0]
+	if len(values) > 1 {
+		var err error
+		if merged, err = mergeResults(mergerName, values); err != nil {
+			return &protocol.RPCResult{Err: err}
+		}
+	}
+	return &protocol.RPCResult{Rest: setReply(invocation.Reply(), merged)}
+}
+
+// isSingleGroup returns whether all @invokers belong to the same group
+func isSingleGroup(invokers []protocol.Invoker) bool {
+	group := invokers[0].GetURL().GetParam(constant.GROUP_KEY, "")
+	for _, ivk := range invokers[1:] {
+		if ivk.GetURL().GetParam(constant.GROUP_KEY, "") != group {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeResults merges @values by the merger named @mergerName.
+// true or default means the default merger of the result type, .Method means merging the results
+// by the method of the result, others are the name of merger extension.
+func mergeResults(mergerName string, values []interface{}) (interface{}, error) {
+	if strings.HasPrefix(mergerName, ".") {
+		return mergeByMethod(strings.TrimPrefix(mergerName, "."), values)
+	}
+
+	var (
+		m  cluster.Merger
+		ok bool
+	)
+	if mergerName == "true" || mergerName == "default" {
+		var typ reflect.Type
+		for _, v := range values {
+			if v != nil {
+				typ = reflect.TypeOf(v)
+				break
+			}
+		}
+		if typ == nil {
+			return nil, nil
+		}
+		if m, ok = merger.GetMergerByType(typ); !ok {
+			return nil, perrors.Errorf("there is no default merger for result of type %s", typ)
+		}
+	} else if m, ok = extension.GetMerger(mergerName); !ok {
+		return nil, perrors.Errorf("merger %s is not existing, make sure you have registered it", mergerName)
+	}
+	return m.Merge(values)
+}
+
+// mergeByMethod calls the method @methodName of the first result with the other results one by one.
+// The return value of the method is used as the merged result if it has the same type,
+// otherwise the method is considered to merge the argument into the first result.
+func mergeByMethod(methodName string, values []interface{}) (interface{}, error) {
+	if values[0] == nil {
+		return nil, perrors.Errorf("can not merge results by method %s of nil result", methodName)
+	}
+	typ := reflect.TypeOf(values[0])
+	merged := reflect.New(typ)
+	merged.Elem().Set(reflect.ValueOf(values[0]))
+	method := merged.MethodByName(methodName)
+	if !method.IsValid() || method.Type().NumIn() != 1 {
+		return nil, perrors.Errorf("can not find merge method %s with one parameter of result type %s", methodName, typ)
+	}
+	for _, v := range values[1:] {
+		if v == nil {
+			continue
+		}
+		arg := reflect.ValueOf(v)
+		if !arg.Type().AssignableTo(method.Type().In(0)) {
+			return nil, perrors.Errorf("can not merge result of type %s by method %s of %s", arg.Type(), methodName, typ)
+		}
+		outs := method.Call([]reflect.Value{arg})
+		if len(outs) > 0 {
+			if err, ok := outs[len(outs)-1].Interface().(error); ok && err != nil {
+				return nil, err
+			}
+			if outs[0].Type().AssignableTo(typ) {
+				merged.Elem().Set(outs[0])
+			}
+		}
+	}
+	return merged.Elem().Interface(), nil
+}
+
+// copyInvocation copies @invocation with a new reply of the same type
+func copyInvocation(invocation protocol.Invocation) protocol.Invocation {
+	attachments := make(map[string]interface{}, len(invocation.Attachments()))
+	for k, v := range invocation.Attachments() {
+		attachments[k] = v
+	}
+	var reply interface{}
+	if invocation.Reply() != nil {
+		if typ := reflect.TypeOf(invocation.Reply()); typ.Kind() == reflect.Ptr {
+			reply = reflect.New(typ.Elem()).Interface()
+		}
+	}
+	inv := invocation_impl.NewRPCInvocationWithOptions(
+		invocation_impl.WithMethodName(invocation.MethodName()),
+		invocation_impl.WithArguments(invocation.Arguments()),
+		invocation_impl.WithParameterTypes(invocation.ParameterTypes()),
+		invocation_impl.WithParameterTypeNames(invocation.ParameterTypeNames()),
+		invocation_impl.WithParameterValues(invocation.ParameterValues()),
+		invocation_impl.WithReply(reply),
+		invocation_impl.WithAttachments(attachments),
+		invocation_impl.WithInvoker(invocation.Invoker()),
+	)
+	for k, v := range invocation.Attributes() {
+		inv.SetAttribute(k, v)
+	}
+	return inv
+}
+
+// resultValue returns the value of the result, the reply pointer is dereferenced
+func resultValue(result protocol.Result) interface{} {
+	res := result.Result()
+	if v := reflect.ValueOf(res); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	}
+	return res
+}
+
+// setReply fills @reply with @merged if possible, and returns the result of the invocation
+func setReply(reply interface{}, merged interface{}) interface{} {
+	if reply == nil {
+		return merged
+	}
+	rv := reflect.ValueOf(reply)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return merged
+	}
+	if merged == nil {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		return reply
+	}
+	mv := reflect.ValueOf(merged)
+	if mv.Type().AssignableTo(rv.Elem().Type()) {
+		rv.Elem().Set(mv)
+		return reply
+	}
+	if mv.Kind() == reflect.Ptr && !mv.IsNil() && mv.Elem().Type().AssignableTo(rv.Elem().Type()) {
+		rv.Elem().Set(mv.Elem())
+		return reply
+	}
+	return merged
+}
+
+// getMergeTimeout returns the timeout of the invocation on every group, the timeout could be
+// duration string such as 3s, or milliseconds.
+func getMergeTimeout(url *common.URL, methodName string) time.Duration {
+	timeout := url.GetMethodParam(methodName, constant.TIMEOUT_KEY, url.GetParam(constant.TIMEOUT_KEY, ""))
+	if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
+		return d
+	}
+	if ms, err := strconv.Atoi(timeout); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return constant.DEFAULT_TIMEOUT * time.Millisecond
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	_ "dubbo.apache.org/dubbo-go/v3/cluster/merger"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type groupTestInvoker struct {
+	protocol.BaseInvoker
+	items []string
+	err   error
+	delay time.Duration
+}
+
+func (invoker *groupTestInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	time.Sleep(invoker.delay)
+	if invoker.err != nil {
+		return &protocol.RPCResult{Err: invoker.err}
+	}
+	reply := inv.Reply().(*[]string)
+	*reply = invoker.items
+	return &protocol.RPCResult{Rest: reply}
+}
+
+func newGroupTestInvoker(group string, items ...string) *groupTestInvoker {
+	url, _ := common.NewURL(fmt.Sprintf("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?group=%s", group))
+	return &groupTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), items: items}
+}
+
+func newMergeableTestInvoker(params map[string]string, invokers ...protocol.Invoker) protocol.Invoker {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	for k, v := range params {
+		url.SetParam(k, v)
+	}
+	dir := directory.NewStaticDirectory(invokers)
+	dir.GetURL().SubURL = url
+	return NewMergeableCluster().Join(dir)
+}
+
+func TestMergeableClusterInvokerMerge(t *testing.T) {
+	clusterInvoker := newMergeableTestInvoker(map[string]string{constant.MERGER_KEY: "true"},
+		newGroupTestInvoker("a", "1", "2"), newGroupTestInvoker("b", "3"))
+
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetItems"), invocation.WithReply(&reply))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	sort.Strings(reply)
+	assert.Equal(t, []string{"1", "2", "3"}, reply)
+}
+
+func TestMergeableClusterInvokerWithoutMerger(t *testing.T) {
+	clusterInvoker := newMergeableTestInvoker(nil, newGroupTestInvoker("a", "1"), newGroupTestInvoker("b", "2"))
+
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetItems"), invocation.WithReply(&reply))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"1"}, reply)
+}
+
+func TestMergeableClusterInvokerSingleGroup(t *testing.T) {
+	providers := []*groupTestInvoker{newGroupTestInvoker("a", "1", "2"), newGroupTestInvoker("a", "1", "2"),
+		newGroupTestInvoker("a", "1", "2")}
+	clusterInvoker := newMergeableTestInvoker(map[string]string{constant.MERGER_KEY: "true"},
+		providers[0], providers[1], providers[2])
+
+	// the providers of the same group are not merged
+	for i := 0; i < 10; i++ {
+		var reply []string
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetItems"), invocation.WithReply(&reply))
+		result := clusterInvoker.Invoke(context.Background(), inv)
+		assert.NoError(t, result.Error())
+		assert.Equal(t, []string{"1", "2"}, reply)
+	}
+}
+
+func TestMergeableClusterInvokerPartialFailure(t *testing.T) {
+	failed := newGroupTestInvoker("b")
+	failed.err = perrors.New("group b is down")
+	slow := newGroupTestInvoker("c", "3")
+	slow.delay = 200 * time.Millisecond
+	params := map[string]string{
+		constant.MERGER_KEY:  "slice",
+		constant.TIMEOUT_KEY: "50ms",
+	}
+
+	clusterInvoker := newMergeableTestInvoker(params, newGroupTestInvoker("a", "1"), failed, slow)
+	var reply []string
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetItems"), invocation.WithReply(&reply))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []string{"1"}, reply)
+
+	params[constant.MERGER_FAIL_POLICY_KEY] = constant.MERGER_FAILFAST_POLICY
+	clusterInvoker = newMergeableTestInvoker(params, newGroupTestInvoker("a", "1"), failed)
+	result = clusterInvoker.Invoke(context.Background(), inv)
+	assert.Error(t, result.Error())
+}
+
+func TestMergeResultsByMethod(t *testing.T) {
+	res, err := mergeResults(".Append", []interface{}{testItems{"1"}, testItems{"2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, testItems{"1", "2"}, res)
+
+	_, err = mergeResults("notExist", []interface{}{testItems{"1"}, testItems{"2"}})
+	assert.Error(t, err)
+}
+
+type testItems []string
+
+func (items *testItems) Append(other testItems) {
+	*items = append(*items, other...)
+}
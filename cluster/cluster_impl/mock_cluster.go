@@ -17,8 +17,14 @@
 
 package cluster_impl
 
+import (
+	"strings"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -37,3 +43,34 @@ func NewMockCluster() cluster.Cluster {
 func (cluster *mockCluster) Join(directory cluster.Directory) protocol.Invoker {
 	return buildInterceptorChain(protocol.NewBaseInvoker(directory.GetURL()))
 }
+
+type mockClusterWrapper struct {
+	cluster cluster.Cluster
+}
+
+// NewMockClusterWrapper returns a cluster which wraps @cluster with the mock cluster invoker.
+//
+// The mock rule is read from the mock key of the consumer url, e.g. mock=force:return null,
+// mock=fail:throw or mock=com.foo.BarMock, and could be changed at runtime by override rules.
+func NewMockClusterWrapper(cluster cluster.Cluster) cluster.Cluster {
+	return &mockClusterWrapper{cluster: cluster}
+}
+
+// Join returns a mockClusterInvoker which delegates to the invoker joined by the wrapped cluster
+func (wrapper *mockClusterWrapper) Join(directory cluster.Directory) protocol.Invoker {
+	return newMockClusterInvoker(directory, wrapper.cluster.Join(directory))
+}
+
+// HasMock checks whether the mock rule is configured by @url for the service or any of its methods.
+// The mock cluster invoker is only needed by the references with mock rules, mock=false could be used
+// to enable switching the mock rule by override rules at runtime.
+func HasMock(url *common.URL) bool {
+	hasMock := false
+	url.RangeParams(func(key, value string) bool {
+		if key == constant.MOCK_KEY || strings.HasPrefix(key, "methods.") && strings.HasSuffix(key, "."+constant.MOCK_KEY) {
+			hasMock = len(value) != 0
+		}
+		return !hasMock
+	})
+	return hasMock
+}
@@ -0,0 +1,279 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+import (
+	hessian2 "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	_ "dubbo.apache.org/dubbo-go/v3/config_center/configurator"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// mockClusterInvoker returns the mock result instead of calling the providers when the mock rule is
+// forced, or when the call of the wrapped invoker fails by a rpc failure rather than an exception of service.
+type mockClusterInvoker struct {
+	protocol.BaseInvoker
+	directory cluster.Directory
+	invoker   protocol.Invoker
+	listener  *registry.BaseConfigurationListener
+}
+
+func newMockClusterInvoker(directory cluster.Directory, invoker protocol.Invoker) protocol.Invoker {
+	mockInvoker := &mockClusterInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(directory.GetURL()),
+		directory:   directory,
+		invoker:     invoker,
+		listener:    &registry.BaseConfigurationListener{},
+	}
+	// override rules of the service could switch the mock rule at runtime
+	mockInvoker.listener.InitWith(
+		mockInvoker.consumerURL().EncodedServiceKey()+constant.CONFIGURATORS_SUFFIX,
+		mockInvoker.listener,
+		extension.GetDefaultConfiguratorFunc(),
+	)
+	return mockInvoker
+}
+
+// Invoke calls the wrapped invoker or the mock according to the mock rule of the invocation method
+func (invoker *mockClusterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	mock := invoker.mockValue(invocation.MethodName())
+	if len(mock) == 0 || mock == "false" {
+		return invoker.invoker.Invoke(ctx, invocation)
+	}
+
+	if mock == constant.MOCK_FORCE_VALUE || strings.HasPrefix(mock, constant.MOCK_FORCE_PREFIX) {
+		logger.Warnf("force-mock: %s is enabled for method %s, url: %s", mock, invocation.MethodName(), invoker.directory.GetURL())
+		return invoker.doMockInvoke(ctx, invocation, mock)
+	}
+
+	result := invoker.invoker.Invoke(ctx, invocation)
+	if isRPCFailure(result.Error()) {
+		logger.Warnf("fail-mock: %s is enabled for method %s, error: %v", mock, invocation.MethodName(), result.Error())
+		return invoker.doMockInvoke(ctx, invocation, mock)
+	}
+	return result
+}
+
+// IsAvailable returns whether the wrapped invoker is available
+func (invoker *mockClusterInvoker) IsAvailable() bool {
+	return invoker.invoker.IsAvailable()
+}
+
+// Destroy destroys the wrapped invoker and stops listening to the override rules
+func (invoker *mockClusterInvoker) Destroy() {
+	invoker.BaseInvoker.Destroy()
+	invoker.listener.RemoveListener(invoker.listener)
+	invoker.invoker.Destroy()
+}
+
+// isRPCFailure reports whether @err is a failure of rpc rather than an exception of service, only the rpc failures
+// fall back to mock. The errors without status are raised by consumer, e.g. no provider, connection failure or
+// timeout, and the errors with status are replied by provider, which are failures only for the codes below.
+func isRPCFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var rpcErr *protocol.RPCError
+	var statusErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &rpcErr) && !errors.As(err, &statusErr) {
+		return true
+	}
+	switch protocol.ErrorCode(err) {
+	case codes.DeadlineExceeded, codes.Unavailable, codes.ResourceExhausted, codes.Internal, codes.Unimplemented:
+		return true
+	}
+	return false
+}
+
+// consumerURL returns the consumer url, the url of registry directory is the registry url.
+func (invoker *mockClusterInvoker) consumerURL() *common.URL {
+	url := invoker.directory.GetURL()
+	if url.SubURL != nil {
+		return url.SubURL
+	}
+	return url
+}
+
+func (invoker *mockClusterInvoker) mockValue(methodName string) string {
+	url := invoker.consumerURL()
+	if len(invoker.listener.Configurators()) > 0 {
+		url = url.Clone()
+		invoker.listener.OverrideUrl(url)
+	}
+	return strings.TrimSpace(url.GetMethodParam(methodName, constant.MOCK_KEY, url.GetParam(constant.MOCK_KEY, "")))
+}
+
+func (invoker *mockClusterInvoker) doMockInvoke(ctx context.Context, invocation protocol.Invocation, mock string) protocol.Result {
+	mock = normalizeMock(mock)
+	switch {
+	case strings.HasPrefix(mock, constant.MOCK_RETURN_PREFIX):
+		value := strings.TrimSpace(strings.TrimPrefix(mock, constant.MOCK_RETURN_PREFIX))
+		res, err := parseMockValue(value, invocation.Reply())
+		if err != nil {
+			return &protocol.RPCResult{Err: err}
+		}
+		return &protocol.RPCResult{Rest: res}
+	case strings.HasPrefix(mock, constant.MOCK_THROW_PREFIX):
+		msg := strings.TrimSpace(strings.TrimPrefix(mock, constant.MOCK_THROW_PREFIX))
+		if len(msg) == 0 {
+			msg = "mocked exception for service degradation of method " + invocation.MethodName()
+		}
+		return &protocol.RPCResult{Err: perrors.New(msg)}
+	}
+
+	name := mock
+	if len(name) == 0 {
+		name = invoker.consumerURL().Service()
+	}
+	svc, ok := extension.GetMock(name)
+	if !ok {
+		return &protocol.RPCResult{Err: perrors.Errorf("can not find the mock implementation %s of service %s",
+			name, invoker.consumerURL().Service())}
+	}
+	return invokeMock(ctx, svc, invocation)
+}
+
+// normalizeMock strips the force and fail prefixes, the empty result means the default mock implementation.
+func normalizeMock(mock string) string {
+	mock = strings.TrimPrefix(mock, constant.MOCK_FORCE_PREFIX)
+	mock = strings.TrimSpace(strings.TrimPrefix(mock, constant.MOCK_FAIL_PREFIX))
+	switch mock {
+	case "true", "default", "fail", "force":
+		return ""
+	}
+	return mock
+}
+
+// parseMockValue parses the return value of mock rule into @reply if it is not nil.
+// null, empty, true, false, numbers, quoted strings and json are supported.
+func parseMockValue(value string, reply interface{}) (interface{}, error) {
+	if len(value) == 0 || value == constant.MOCK_NULL_VALUE {
+		return nil, nil
+	}
+	if value == constant.MOCK_EMPTY_VALUE {
+		if v := reflect.ValueOf(reply); v.Kind() == reflect.Ptr && !v.IsNil() {
+			elem := v.Elem()
+			switch elem.Kind() {
+			case reflect.Slice:
+				elem.Set(reflect.MakeSlice(elem.Type(), 0, 0))
+			case reflect.Map:
+				elem.Set(reflect.MakeMap(elem.Type()))
+			default:
+				elem.Set(reflect.Zero(elem.Type()))
+			}
+		}
+		return reply, nil
+	}
+	if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"' || value[0] == '\'' && value[len(value)-1] == '\'') {
+		value = strconv.Quote(value[1 : len(value)-1])
+	}
+
+	if reply == nil {
+		var res interface{}
+		if err := json.Unmarshal([]byte(value), &res); err != nil {
+			return value, nil
+		}
+		return res, nil
+	}
+	if err := json.Unmarshal([]byte(value), reply); err != nil {
+		if s, ok := reply.(*string); ok {
+			*s = value
+			return reply, nil
+		}
+		return nil, perrors.WithMessagef(err, "can not parse mock return value %s into %T", value, reply)
+	}
+	return reply, nil
+}
+
+// invokeMock calls the method of the mock implementation @svc in the same way as the provider does.
+func invokeMock(ctx context.Context, svc common.RPCService, invocation protocol.Invocation) protocol.Result {
+	methodName := invocation.MethodName()
+	rcvr := reflect.ValueOf(svc)
+	method := rcvr.MethodByName(methodName)
+	if !method.IsValid() && len(methodName) > 0 {
+		method = rcvr.MethodByName(strings.ToUpper(methodName[:1]) + methodName[1:])
+	}
+	if !method.IsValid() {
+		return &protocol.RPCResult{Err: perrors.Errorf("can not find method %s of mock implementation %T", methodName, svc)}
+	}
+
+	methodType := method.Type()
+	in := make([]reflect.Value, 0, methodType.NumIn())
+	if methodType.NumIn() > 0 && methodType.In(0) == contextType {
+		in = append(in, reflect.ValueOf(ctx))
+	}
+	for _, arg := range invocation.Arguments() {
+		if len(in) >= methodType.NumIn() {
+			break
+		}
+		if arg == nil {
+			in = append(in, reflect.Zero(methodType.In(len(in))))
+			continue
+		}
+		in = append(in, reflect.ValueOf(arg))
+	}
+	reply := invocation.Reply()
+	// the method which only returns error takes the reply as the last argument
+	if methodType.NumOut() == 1 && len(in) == methodType.NumIn()-1 {
+		if reply == nil {
+			reply = reflect.New(methodType.In(len(in)).Elem()).Interface()
+		}
+		in = append(in, reflect.ValueOf(reply))
+	}
+	if len(in) != methodType.NumIn() {
+		return &protocol.RPCResult{Err: perrors.Errorf("the arguments of invocation %s do not match mock implementation %T",
+			methodName, svc)}
+	}
+
+	outs := method.Call(in)
+	if err, ok := outs[len(outs)-1].Interface().(error); ok && err != nil {
+		return &protocol.RPCResult{Err: err}
+	}
+	if len(outs) == 1 {
+		return &protocol.RPCResult{Rest: reply}
+	}
+	res := outs[0].Interface()
+	if invocation.Reply() != nil && res != nil {
+		if err := hessian2.ReflectResponse(res, invocation.Reply()); err != nil {
+			return &protocol.RPCResult{Err: err}
+		}
+	}
+	return &protocol.RPCResult{Rest: res}
+}
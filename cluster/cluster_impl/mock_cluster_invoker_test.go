@@ -0,0 +1,243 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/config_center/parser"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const mockTestService = "com.ikurento.user.MockUserProvider"
+
+type mockTestUser struct {
+	ID   string
+	Name string
+}
+
+type mockTestUserProvider struct{}
+
+func (u *mockTestUserProvider) GetUser(_ context.Context, id string) (*mockTestUser, error) {
+	return &mockTestUser{ID: id, Name: "mock"}, nil
+}
+
+func (u *mockTestUserProvider) GetUserName(id string, name *string) error {
+	*name = "mock-" + id
+	return nil
+}
+
+func (u *mockTestUserProvider) Reference() string {
+	return "MockUserProvider"
+}
+
+type mockTestInvoker struct {
+	protocol.BaseInvoker
+	result  protocol.Result
+	invoked int
+}
+
+func (invoker *mockTestInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	invoker.invoked++
+	return invoker.result
+}
+
+func newMockTestClusterInvoker(mock string, result protocol.Result) (protocol.Invoker, *mockTestInvoker) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/"+mockTestService,
+		common.WithParamsValue(constant.MOCK_KEY, mock),
+		common.WithParamsValue(constant.SIDE_KEY, "consumer"))
+	invoker := &mockTestInvoker{BaseInvoker: *protocol.NewBaseInvoker(url), result: result}
+	dir := directory.NewStaticDirectory([]protocol.Invoker{invoker})
+	return NewMockClusterWrapper(NewFailFastCluster()).Join(dir), invoker
+}
+
+func TestMockClusterInvokerWithoutMock(t *testing.T) {
+	clusterInvoker, invoker := newMockTestClusterInvoker("", &protocol.RPCResult{Rest: "ok"})
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "ok", result.Result())
+	assert.Equal(t, 1, invoker.invoked)
+}
+
+func TestMockClusterInvokerForceReturn(t *testing.T) {
+	clusterInvoker, invoker := newMockTestClusterInvoker("force:return null", &protocol.RPCResult{Rest: "ok"})
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.NoError(t, result.Error())
+	assert.Nil(t, result.Result())
+	assert.Equal(t, 0, invoker.invoked)
+
+	user := &mockTestUser{}
+	clusterInvoker, _ = newMockTestClusterInvoker(`force:return {"ID":"1","Name":"json"}`, nil)
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"), invocation.WithReply(user))
+	result = clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "json", user.Name)
+
+	var name string
+	clusterInvoker, _ = newMockTestClusterInvoker("force:return 'hello'", nil)
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUserName"), invocation.WithReply(&name))
+	result = clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "hello", name)
+}
+
+func TestMockClusterInvokerFailThrow(t *testing.T) {
+	clusterInvoker, invoker := newMockTestClusterInvoker("fail:throw degraded", &protocol.RPCResult{Rest: "ok"})
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "ok", result.Result())
+
+	invoker.result = &protocol.RPCResult{Err: perrors.New("provider is down")}
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.EqualError(t, result.Error(), "degraded")
+	assert.Equal(t, 2, invoker.invoked)
+
+	// the timeout replied by provider is a rpc failure
+	invoker.result = &protocol.RPCResult{Err: protocol.NewRPCError(codes.DeadlineExceeded, "timeout")}
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.EqualError(t, result.Error(), "degraded")
+
+	// the exception of service is returned as is
+	bizErr := protocol.WrapRPCError(errors.New("user not found"), codes.Unknown, "user not found")
+	invoker.result = &protocol.RPCResult{Err: bizErr}
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, bizErr, result.Error())
+	invoker.result = &protocol.RPCResult{Err: status.Error(codes.NotFound, "user not found")}
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, codes.NotFound, status.Code(result.Error()))
+	assert.Equal(t, 5, invoker.invoked)
+}
+
+func TestMockClusterInvokerMockImplementation(t *testing.T) {
+	extension.SetMock(mockTestService, &mockTestUserProvider{})
+	extension.SetMock("namedMock", &mockTestUserProvider{})
+
+	clusterInvoker, _ := newMockTestClusterInvoker("true", &protocol.RPCResult{Err: errors.New("provider is down")})
+	user := &mockTestUser{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(user))
+	result := clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "1", user.ID)
+	assert.Equal(t, "mock", user.Name)
+
+	clusterInvoker, _ = newMockTestClusterInvoker("force:namedMock", nil)
+	var name string
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("getUserName"),
+		invocation.WithArguments([]interface{}{"2"}), invocation.WithReply(&name))
+	result = clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "mock-2", name)
+
+	// the bare force uses the default mock implementation without calling the provider
+	clusterInvoker, invoker := newMockTestClusterInvoker("force", &protocol.RPCResult{Rest: "ok"})
+	user = &mockTestUser{}
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"3"}), invocation.WithReply(user))
+	result = clusterInvoker.Invoke(context.Background(), inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "3", user.ID)
+	assert.Equal(t, "mock", user.Name)
+	assert.Equal(t, 0, invoker.invoked)
+
+	clusterInvoker, _ = newMockTestClusterInvoker("force:notExist", nil)
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Error(t, result.Error())
+}
+
+func TestMockClusterInvokerOverride(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{}
+	mockConfiguration, _ := factory.GetDynamicConfiguration(common.NewURLWithOptions())
+	dc := &removedListenerConfiguration{DynamicConfiguration: mockConfiguration}
+	config.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer config.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	clusterInvoker, invoker := newMockTestClusterInvoker("false", &protocol.RPCResult{Rest: "ok"})
+	result := clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, "ok", result.Result())
+
+	rule := &parser.ConfiguratorConfig{
+		ConfigVersion: "2.7.1",
+		Scope:         parser.GeneralType,
+		Key:           mockTestService,
+		Enabled:       true,
+		Configs: []parser.ConfigItem{
+			{
+				Type:       parser.GeneralType,
+				Enabled:    true,
+				Addresses:  []string{constant.ANYHOST_VALUE + ":0"},
+				Side:       "consumer",
+				Parameters: map[string]string{constant.MOCK_KEY: "force:return 1"},
+			},
+		},
+	}
+	value, _ := yaml.Marshal(rule)
+	listener := clusterInvoker.(*mockClusterInvoker).listener
+	listener.Process(&config_center.ConfigChangeEvent{Value: string(value), ConfigType: remoting.EventTypeAdd})
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, float64(1), result.Result())
+	assert.Equal(t, 1, invoker.invoked)
+
+	listener.Process(&config_center.ConfigChangeEvent{ConfigType: remoting.EventTypeDel})
+	result = clusterInvoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Equal(t, "ok", result.Result())
+	assert.Equal(t, 2, invoker.invoked)
+
+	// the override rules are not listened after destroyed
+	clusterInvoker.Destroy()
+	assert.Equal(t, []string{mockTestService + constant.CONFIGURATORS_SUFFIX}, dc.removed)
+}
+
+// removedListenerConfiguration records the keys of the removed listeners
+type removedListenerConfiguration struct {
+	config_center.DynamicConfiguration
+	removed []string
+}
+
+func (c *removedListenerConfiguration) RemoveListener(key string, listener config_center.ConfigurationListener,
+	opts ...config_center.Option) {
+	c.removed = append(c.removed, key)
+	c.DynamicConfiguration.RemoveListener(key, listener, opts...)
+}
+
+func TestHasMock(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/" + mockTestService)
+	assert.False(t, HasMock(url))
+	url.SetParam("methods.GetUser."+constant.MOCK_KEY, "fail:return null")
+	assert.True(t, HasMock(url))
+}
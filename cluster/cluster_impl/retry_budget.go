@@ -0,0 +1,105 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+const (
+	defaultRetryBudgetMinPerSecond = 10
+	// retryBudgetBurstSeconds is the seconds of the minimum retries the bucket could hold at most
+	retryBudgetBurstSeconds = 10
+)
+
+var retryBudgets sync.Map // service key -> *retryBudget
+
+// retryBudget is a token bucket shared by all invocations of a service. Every request deposits ratio tokens,
+// every retry withdraws one token, and the bucket is refilled with the minimum retries per second.
+type retryBudget struct {
+	ratio        float64
+	minPerSecond float64
+	capacity     float64
+
+	mutex      sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRetryBudget(ratio, minPerSecond float64) *retryBudget {
+	capacity := math.Max(minPerSecond, 1) * retryBudgetBurstSeconds
+	return &retryBudget{
+		ratio:        ratio,
+		minPerSecond: minPerSecond,
+		capacity:     capacity,
+		tokens:       capacity,
+		lastRefill:   time.Now(),
+	}
+}
+
+// getRetryBudget gets the retry budget of the service of @url, nil means the retry budget is disabled.
+func getRetryBudget(url *common.URL) *retryBudget {
+	ratio, err := strconv.ParseFloat(url.GetParam(constant.RETRY_BUDGET_RATIO_KEY, ""), 64)
+	if err != nil || ratio <= 0 {
+		return nil
+	}
+	key := url.ServiceKey()
+	budget, found := retryBudgets.Load(key)
+	if !found {
+		minPerSecond, err := strconv.ParseFloat(url.GetParam(constant.RETRY_BUDGET_MIN_KEY, ""), 64)
+		if err != nil || minPerSecond < 0 {
+			minPerSecond = defaultRetryBudgetMinPerSecond
+		}
+		budget, _ = retryBudgets.LoadOrStore(key, newRetryBudget(ratio, minPerSecond))
+	}
+	return budget.(*retryBudget)
+}
+
+// deposit records a request.
+func (b *retryBudget) deposit() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill()
+	b.tokens = math.Min(b.capacity, b.tokens+b.ratio)
+}
+
+// tryWithdraw checks whether a retry is permitted and takes a token if it is.
+func (b *retryBudget) tryWithdraw() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *retryBudget) refill() {
+	now := time.Now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.lastRefill).Seconds()*b.minPerSecond)
+	b.lastRefill = now
+}
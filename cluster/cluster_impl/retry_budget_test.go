@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(0.5, 0)
+	assert.Equal(t, float64(retryBudgetBurstSeconds), budget.capacity)
+	for i := 0; i < retryBudgetBurstSeconds; i++ {
+		assert.True(t, budget.tryWithdraw())
+	}
+	assert.False(t, budget.tryWithdraw())
+
+	// two requests deposit one retry
+	budget.deposit()
+	assert.False(t, budget.tryWithdraw())
+	budget.deposit()
+	assert.True(t, budget.tryWithdraw())
+	assert.False(t, budget.tryWithdraw())
+
+	// the deposits are limited by the capacity
+	for i := 0; i < 100; i++ {
+		budget.deposit()
+	}
+	for i := 0; i < retryBudgetBurstSeconds; i++ {
+		assert.True(t, budget.tryWithdraw())
+	}
+	assert.False(t, budget.tryWithdraw())
+}
+
+func TestRetryBudgetMinPerSecond(t *testing.T) {
+	budget := newRetryBudget(0.1, 100)
+	for budget.tryWithdraw() {
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, budget.tryWithdraw())
+}
+
+func TestGetRetryBudget(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.1.1:20000/com.ikurento.user.UserProvider?group=test")
+	assert.Nil(t, getRetryBudget(url))
+
+	url.SetParam("retry.budget.ratio", "0.2")
+	url.SetParam("retry.budget.min", "1")
+	budget := getRetryBudget(url)
+	assert.NotNil(t, budget)
+	assert.Equal(t, 0.2, budget.ratio)
+	assert.Equal(t, float64(1), budget.minPerSecond)
+
+	// the budget is shared by the invokers of the service
+	url1, _ := common.NewURL("dubbo://192.168.1.2:20000/com.ikurento.user.UserProvider?group=test&retry.budget.ratio=0.2")
+	assert.Same(t, budget, getRetryBudget(url1))
+	retryBudgets.Delete(url.ServiceKey())
+}
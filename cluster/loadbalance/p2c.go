@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// P2C is used to set the load balance extension
+	P2C = "p2c"
+)
+
+// p2cForcePickInterval is the interval to force picking an invoker which is not picked for a long time,
+// so that its statistics get refreshed
+var p2cForcePickInterval = 3 * time.Second
+
+func init() {
+	extension.SetLoadbalance(P2C, NewP2CLoadBalance)
+}
+
+type p2cLoadBalance struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+// NewP2CLoadBalance returns a power of two choices load balance.
+//
+// It samples two invokers randomly and selects the one with the lower load, which is estimated by the EWMA latency,
+// the inflight requests, the server load reported by provider and the EWMA error rate.
+// The statistics are collected by the p2c_consumer filter and the server load is reported by the p2c_provider filter.
+func NewP2CLoadBalance() cluster.LoadBalance {
+	return &p2cLoadBalance{rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+// Select gets invoker based on p2c load balancing strategy
+func (lb *p2cLoadBalance) Select(invokers []protocol.Invoker, invocation protocol.Invocation) protocol.Invoker {
+	count := len(invokers)
+	if count == 0 {
+		return nil
+	}
+	if count == 1 {
+		return invokers[0]
+	}
+
+	lb.mutex.Lock()
+	i := lb.rand.Intn(count)
+	j := lb.rand.Intn(count - 1)
+	lb.mutex.Unlock()
+	if j >= i {
+		j++
+	}
+
+	a, b := invokers[i], invokers[j]
+	statusA := protocol.GetP2CStatus(a.GetURL(), invocation.MethodName())
+	statusB := protocol.GetP2CStatus(b.GetURL(), invocation.MethodName())
+	if p2cScore(a, statusA, invocation) > p2cScore(b, statusB, invocation) {
+		a, b = b, a
+		statusA, statusB = statusB, statusA
+	}
+
+	// the loser gets a chance if it is not picked for a long time, so that its statistics are refreshed
+	if pick := statusB.GetLastPicked(); pick != 0 && time.Since(time.Unix(0, pick)) > p2cForcePickInterval {
+		a, statusA = b, statusB
+	}
+	statusA.Pick()
+	return a
+}
+
+// p2cScore estimates the load of @invoker, the lower the better
+func p2cScore(invoker protocol.Invoker, status *protocol.P2CStatus, invocation protocol.Invocation) float64 {
+	// the requests in flight are waiting for the latency in turn
+	load := (status.GetLatency() + 1) * float64(int64(status.GetInflight())+status.GetServerLoad()+1)
+	// the invoker with high error rate is penalized, the success rate is at least 1%
+	successRate := 1 - status.GetErrorRate()
+	if successRate < 0.01 {
+		successRate = 0.01
+	}
+	// current weight (maybe in warmUp)
+	weight := GetWeight(invoker, invocation)
+	if weight <= 0 {
+		weight = 1
+	}
+	return load / successRate / float64(weight)
+}
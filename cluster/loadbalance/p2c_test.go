@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func newP2CInvokers(prefix string, n int) []protocol.Invoker {
+	var invokers []protocol.Invoker
+	for i := 1; i <= n; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("%s%v://192.168.1.%v:20000/org.apache.demo.HelloService", prefix, i, i))
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func TestP2CSelect(t *testing.T) {
+	loadBalance := NewP2CLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+
+	assert.Nil(t, loadBalance.Select(nil, inv))
+	invokers := newP2CInvokers("p2c", 2)
+	assert.Equal(t, invokers[0], loadBalance.Select(invokers[:1], inv))
+
+	// the second invoker is slow
+	status := protocol.GetP2CStatus(invokers[1].GetURL(), inv.MethodName())
+	status.Begin()
+	status.End(100*time.Millisecond, true, -1)
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, invokers[0], loadBalance.Select(invokers, inv))
+	}
+
+	// the first invoker has many requests in flight
+	status = protocol.GetP2CStatus(invokers[0].GetURL(), inv.MethodName())
+	status.Begin()
+	status.End(10*time.Millisecond, true, 50)
+	assert.Equal(t, invokers[1], loadBalance.Select(invokers, inv))
+}
+
+func TestP2CSelectByErrorRate(t *testing.T) {
+	loadBalance := NewP2CLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+	invokers := newP2CInvokers("p2cerror", 2)
+
+	for i, succeeded := range []bool{false, true} {
+		status := protocol.GetP2CStatus(invokers[i].GetURL(), inv.MethodName())
+		status.Begin()
+		status.End(10*time.Millisecond, succeeded, -1)
+	}
+	assert.Equal(t, invokers[1], loadBalance.Select(invokers, inv))
+}
+
+func TestP2CForcePick(t *testing.T) {
+	interval := p2cForcePickInterval
+	p2cForcePickInterval = 50 * time.Millisecond
+	defer func() {
+		p2cForcePickInterval = interval
+	}()
+
+	loadBalance := NewP2CLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+	invokers := newP2CInvokers("p2cforce", 2)
+
+	slow := protocol.GetP2CStatus(invokers[1].GetURL(), inv.MethodName())
+	slow.Pick()
+	slow.Begin()
+	slow.End(100*time.Millisecond, true, -1)
+	assert.Equal(t, invokers[0], loadBalance.Select(invokers, inv))
+
+	// the slow invoker is picked to refresh its statistics
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, invokers[1], loadBalance.Select(invokers, inv))
+	assert.Equal(t, invokers[0], loadBalance.Select(invokers, inv))
+}
+
+func TestP2CDistribution(t *testing.T) {
+	loadBalance := NewP2CLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+	invokers := newP2CInvokers("p2cdist", 4)
+
+	// no statistics, the invokers are selected evenly
+	counts := make(map[protocol.Invoker]int)
+	loop := 10000
+	for i := 0; i < loop; i++ {
+		counts[loadBalance.Select(invokers, inv)]++
+	}
+	for _, invoker := range invokers {
+		assert.InDelta(t, loop/4, counts[invoker], float64(loop)/20)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// ShortestResponse is used to set the load balance extension
+	ShortestResponse = "shortestresponse"
+)
+
+// slidePeriod is the period of the sliding window to estimate the response time
+var slidePeriod = 30 * time.Second
+
+func init() {
+	extension.SetLoadbalance(ShortestResponse, NewShortestResponseLoadBalance)
+}
+
+type shortestResponseLoadBalance struct {
+	// windows stores the slideWindow of every invoker method
+	windows sync.Map
+}
+
+// NewShortestResponseLoadBalance returns a shortest response load balance.
+//
+// It selects the invoker with the shortest estimated response time, which is the average response time of
+// the succeeded requests in the sliding window multiplied by the number of active requests.
+// If there are several invokers with the same estimated response time, a weighted random one is selected.
+// The statistics are collected by the active filter.
+func NewShortestResponseLoadBalance() cluster.LoadBalance {
+	return &shortestResponseLoadBalance{}
+}
+
+// slideWindow stores the RPCStatus offsets at the beginning of the window
+type slideWindow struct {
+	mutex                  sync.Mutex
+	succeededOffset        int32
+	succeededElapsedOffset int64
+	lastResetTime          time.Time
+}
+
+// estimateResponse returns the average response time of the succeeded requests in the window
+func (w *slideWindow) estimateResponse(status *protocol.RPCStatus) int64 {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if time.Since(w.lastResetTime) > slidePeriod {
+		w.succeededOffset = status.GetSucceeded()
+		w.succeededElapsedOffset = status.GetSucceededElapsed()
+		w.lastResetTime = time.Now()
+	}
+	succeeded := int64(status.GetSucceeded() - w.succeededOffset)
+	if succeeded <= 0 {
+		return 0
+	}
+	return (status.GetSucceededElapsed() - w.succeededElapsedOffset) / succeeded
+}
+
+func (lb *shortestResponseLoadBalance) getSlideWindow(key string) *slideWindow {
+	window, ok := lb.windows.Load(key)
+	if !ok {
+		window, _ = lb.windows.LoadOrStore(key, &slideWindow{lastResetTime: time.Now()})
+	}
+	return window.(*slideWindow)
+}
+
+// Select gets invoker based on shortest response load balancing strategy
+func (lb *shortestResponseLoadBalance) Select(invokers []protocol.Invoker, invocation protocol.Invocation) protocol.Invoker {
+	count := len(invokers)
+	if count == 0 {
+		return nil
+	}
+	if count == 1 {
+		return invokers[0]
+	}
+
+	var (
+		shortestResponse int64                  = -1 // The shortest estimated response time of all invokers
+		shortestCount    int                         // The number of invokers having the same shortest response time
+		shortestIndexes  = make([]int, count)        // The index of invokers having the same shortest response time
+		weights          = make([]int64, count)      // The weight of every invoker
+		totalWeight      int64                       // The sum of the weights of shortest response invokers
+		firstWeight      int64                       // Initial value, used for comparison
+		sameWeight       = true                      // Every shortest response invoker has the same weight value?
+	)
+
+	for i := 0; i < count; i++ {
+		invoker := invokers[i]
+		url := invoker.GetURL()
+		status := protocol.GetMethodStatus(url, invocation.MethodName())
+		window := lb.getSlideWindow(url.Key() + "#" + invocation.MethodName())
+		// the active requests are waiting for the estimated response time in turn
+		estimated := window.estimateResponse(status) * int64(status.GetActive()+1)
+		// current weight (maybe in warmUp)
+		weight := GetWeight(invoker, invocation)
+		weights[i] = weight
+
+		if shortestResponse == -1 || estimated < shortestResponse {
+			shortestResponse = estimated
+			shortestIndexes[0] = i
+			shortestCount = 1
+			totalWeight = weight
+			firstWeight = weight
+			sameWeight = true
+		} else if estimated == shortestResponse {
+			shortestIndexes[shortestCount] = i
+			totalWeight += weight
+			shortestCount++
+			if sameWeight && weight != firstWeight {
+				sameWeight = false
+			}
+		}
+	}
+
+	if shortestCount == 1 {
+		return invokers[shortestIndexes[0]]
+	}
+
+	if !sameWeight && totalWeight > 0 {
+		offsetWeight := rand.Int63n(totalWeight)
+		for i := 0; i < shortestCount; i++ {
+			shortestIndex := shortestIndexes[i]
+			offsetWeight -= weights[shortestIndex]
+			if offsetWeight < 0 {
+				return invokers[shortestIndex]
+			}
+		}
+	}
+
+	return invokers[shortestIndexes[rand.Intn(shortestCount)]]
+}
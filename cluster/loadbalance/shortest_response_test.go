@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestShortestResponseSelect(t *testing.T) {
+	loadBalance := NewShortestResponseLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+
+	assert.Nil(t, loadBalance.Select(nil, inv))
+
+	var invokers []protocol.Invoker
+	for i := 1; i <= 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("shortest%v://192.168.1.%v:20000/org.apache.demo.HelloService", i, i))
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	assert.Equal(t, invokers[0], loadBalance.Select(invokers[:1], inv))
+
+	// the average response time of the second invoker is the shortest
+	for i, elapsed := range []int64{100, 10, 50} {
+		url := invokers[i].GetURL()
+		protocol.BeginCount(url, inv.MethodName())
+		protocol.EndCount(url, inv.MethodName(), elapsed, true)
+	}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, invokers[1], loadBalance.Select(invokers, inv))
+	}
+
+	// the estimated response time is multiplied by the active count, 10*(5+1) > 50
+	for i := 0; i < 5; i++ {
+		protocol.BeginCount(invokers[1].GetURL(), inv.MethodName())
+	}
+	assert.Equal(t, invokers[2], loadBalance.Select(invokers, inv))
+}
+
+func TestShortestResponseByWeight(t *testing.T) {
+	loadBalance := NewShortestResponseLoadBalance()
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("test"))
+
+	var invokers []protocol.Invoker
+	for i := 1; i <= 3; i++ {
+		url, _ := common.NewURL(fmt.Sprintf("weight%v://192.168.1.%v:20000/org.apache.demo.HelloService?weight=%v",
+			i, i, i*100))
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+
+	// no statistics, the invokers are selected by weight
+	counts := make(map[string]int)
+	loop := 10000
+	for i := 0; i < loop; i++ {
+		counts[loadBalance.Select(invokers, inv).GetURL().Protocol]++
+	}
+	assert.InDelta(t, loop/6, counts["weight1"], float64(loop)/20)
+	assert.InDelta(t, loop/3, counts["weight2"], float64(loop)/20)
+	assert.InDelta(t, loop/2, counts["weight3"], float64(loop)/20)
+}
+
+func TestShortestResponseSlideWindow(t *testing.T) {
+	period := slidePeriod
+	slidePeriod = 100 * time.Millisecond
+	defer func() {
+		slidePeriod = period
+	}()
+
+	url, _ := common.NewURL("window://192.168.1.1:20000/org.apache.demo.HelloService")
+	status := protocol.GetMethodStatus(url, "test")
+	window := &slideWindow{lastResetTime: time.Now()}
+
+	for _, elapsed := range []int64{10, 30} {
+		protocol.BeginCount(url, "test")
+		protocol.EndCount(url, "test", elapsed, true)
+	}
+	protocol.BeginCount(url, "test")
+	protocol.EndCount(url, "test", 1000, false)
+	assert.Equal(t, int64(20), window.estimateResponse(status))
+
+	// the requests before the new window are not counted
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int64(0), window.estimateResponse(status))
+	protocol.BeginCount(url, "test")
+	protocol.EndCount(url, "test", 40, true)
+	assert.Equal(t, int64(40), window.estimateResponse(status))
+}
@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(AndMergerName, NewAndMerger)
+	extension.SetMerger(OrMergerName, NewOrMerger)
+}
+
+type boolMerger struct {
+	name  string
+	merge func(a, b bool) bool
+}
+
+// NewAndMerger returns a merger which is true only if the results of all groups are true.
+func NewAndMerger() cluster.Merger {
+	return &boolMerger{name: AndMergerName, merge: func(a, b bool) bool { return a && b }}
+}
+
+// NewOrMerger returns a merger which is true if the result of any group is true.
+func NewOrMerger() cluster.Merger {
+	return &boolMerger{name: OrMergerName, merge: func(a, b bool) bool { return a || b }}
+}
+
+// Merge merges the bools of @results, the merged bool has the same type as the results.
+func (m *boolMerger) Merge(results []interface{}) (interface{}, error) {
+	var merged reflect.Value
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		v := reflect.ValueOf(result)
+		if v.Kind() != reflect.Bool {
+			return nil, perrors.Errorf("%s merger can not merge result of type %T", m.name, result)
+		}
+		if !merged.IsValid() {
+			merged = reflect.New(v.Type()).Elem()
+			merged.Set(v)
+			continue
+		}
+		if merged.Type() != v.Type() {
+			return nil, perrors.Errorf("%s merger can not merge %s with %s", m.name, merged.Type(), v.Type())
+		}
+		merged.SetBool(m.merge(merged.Bool(), v.Bool()))
+	}
+	if !merged.IsValid() {
+		return nil, nil
+	}
+	return merged.Interface(), nil
+}
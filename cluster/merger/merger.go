@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+const (
+	SliceMergerName = "slice"
+	MapMergerName   = "map"
+	SetMergerName   = "set"
+	SumMergerName   = "sum"
+	MaxMergerName   = "max"
+	MinMergerName   = "min"
+	AndMergerName   = "and"
+	OrMergerName    = "or"
+)
+
+var hashSetType = reflect.TypeOf(gxset.HashSet{})
+
+// GetMergerByType returns the default merger of the results with type @typ.
+// Slices are concatenated, maps and sets are united, numbers are summed and bools are and-ed.
+func GetMergerByType(typ reflect.Type) (cluster.Merger, bool) {
+	if typ == nil {
+		return nil, false
+	}
+	var name string
+	switch typ.Kind() {
+	case reflect.Slice:
+		name = SliceMergerName
+	case reflect.Map:
+		name = MapMergerName
+	case reflect.Bool:
+		name = AndMergerName
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		name = SumMergerName
+	case reflect.Struct:
+		if typ == hashSetType {
+			name = SetMergerName
+		}
+	case reflect.Ptr:
+		if typ.Elem() == hashSetType {
+			name = SetMergerName
+		}
+	}
+	if len(name) == 0 {
+		return nil, false
+	}
+	return extension.GetMerger(name)
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+	"testing"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func merge(t *testing.T, name string, results ...interface{}) interface{} {
+	m, ok := extension.GetMerger(name)
+	assert.True(t, ok)
+	res, err := m.Merge(results)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestSliceMerger(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, merge(t, SliceMergerName, []string{"a"}, nil, []string{"b", "c"}))
+	assert.Nil(t, merge(t, SliceMergerName, nil, nil))
+
+	m, _ := extension.GetMerger(SliceMergerName)
+	_, err := m.Merge([]interface{}{[]string{"a"}, []int{1}})
+	assert.Error(t, err)
+	_, err = m.Merge([]interface{}{1})
+	assert.Error(t, err)
+}
+
+func TestMapMerger(t *testing.T) {
+	res := merge(t, MapMergerName, map[string]int{"a": 1, "b": 2}, map[string]int{"b": 3, "c": 4})
+	assert.Equal(t, map[string]int{"a": 1, "b": 3, "c": 4}, res)
+}
+
+func TestSetMerger(t *testing.T) {
+	res := merge(t, SetMergerName, gxset.NewSet("a", "b"), gxset.NewSet("b", "c"))
+	set, ok := res.(*gxset.HashSet)
+	assert.True(t, ok)
+	assert.Equal(t, 3, set.Size())
+	assert.True(t, set.Contains("a", "b", "c"))
+
+	res = merge(t, SetMergerName, *gxset.NewSet("a"), *gxset.NewSet("b"))
+	_, ok = res.(gxset.HashSet)
+	assert.True(t, ok)
+}
+
+func TestNumberMerger(t *testing.T) {
+	assert.Equal(t, 6, merge(t, SumMergerName, 1, 2, 3))
+	assert.Equal(t, int64(3), merge(t, MaxMergerName, int64(1), int64(3), int64(2)))
+	assert.Equal(t, uint8(1), merge(t, MinMergerName, uint8(2), uint8(1)))
+	assert.Equal(t, 1.5, merge(t, SumMergerName, 1.0, 0.5))
+
+	m, _ := extension.GetMerger(SumMergerName)
+	_, err := m.Merge([]interface{}{1, int64(1)})
+	assert.Error(t, err)
+	_, err = m.Merge([]interface{}{"1"})
+	assert.Error(t, err)
+}
+
+func TestBoolMerger(t *testing.T) {
+	assert.Equal(t, false, merge(t, AndMergerName, true, false, true))
+	assert.Equal(t, true, merge(t, OrMergerName, false, true))
+}
+
+func TestGetMergerByType(t *testing.T) {
+	for typ, expected := range map[reflect.Type]cluster.Merger{
+		reflect.TypeOf([]int{}):             NewSliceMerger(),
+		reflect.TypeOf(map[string]string{}): NewMapMerger(),
+		reflect.TypeOf(gxset.NewSet()):      NewSetMerger(),
+		reflect.TypeOf(gxset.HashSet{}):     NewSetMerger(),
+		reflect.TypeOf(float32(1)):          NewSumMerger(),
+		reflect.TypeOf(true):                NewAndMerger(),
+	} {
+		m, ok := GetMergerByType(typ)
+		assert.True(t, ok)
+		assert.IsType(t, expected, m)
+	}
+	_, ok := GetMergerByType(reflect.TypeOf(struct{}{}))
+	assert.False(t, ok)
+}
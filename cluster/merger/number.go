@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(SumMergerName, NewSumMerger)
+	extension.SetMerger(MaxMergerName, NewMaxMerger)
+	extension.SetMerger(MinMergerName, NewMinMerger)
+}
+
+type numberMerger struct {
+	name       string
+	mergeInt   func(a, b int64) int64
+	mergeUint  func(a, b uint64) uint64
+	mergeFloat func(a, b float64) float64
+}
+
+// NewSumMerger returns a merger which sums up the numbers of all groups.
+func NewSumMerger() cluster.Merger {
+	return &numberMerger{
+		name:       SumMergerName,
+		mergeInt:   func(a, b int64) int64 { return a + b },
+		mergeUint:  func(a, b uint64) uint64 { return a + b },
+		mergeFloat: func(a, b float64) float64 { return a + b },
+	}
+}
+
+// NewMaxMerger returns a merger which picks the maximum number of all groups.
+func NewMaxMerger() cluster.Merger {
+	return &numberMerger{
+		name: MaxMergerName,
+		mergeInt: func(a, b int64) int64 {
+			if a < b {
+				return b
+			}
+			return a
+		},
+		mergeUint: func(a, b uint64) uint64 {
+			if a < b {
+				return b
+			}
+			return a
+		},
+		mergeFloat: func(a, b float64) float64 {
+			if a < b {
+				return b
+			}
+			return a
+		},
+	}
+}
+
+// NewMinMerger returns a merger which picks the minimum number of all groups.
+func NewMinMerger() cluster.Merger {
+	return &numberMerger{
+		name: MinMergerName,
+		mergeInt: func(a, b int64) int64 {
+			if b < a {
+				return b
+			}
+			return a
+		},
+		mergeUint: func(a, b uint64) uint64 {
+			if b < a {
+				return b
+			}
+			return a
+		},
+		mergeFloat: func(a, b float64) float64 {
+			if b < a {
+				return b
+			}
+			return a
+		},
+	}
+}
+
+// Merge merges the numbers of @results, the merged number has the same type as the results.
+func (m *numberMerger) Merge(results []interface{}) (interface{}, error) {
+	var merged reflect.Value
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		v := reflect.ValueOf(result)
+		if !merged.IsValid() {
+			if !isNumber(v.Kind()) {
+				return nil, perrors.Errorf("%s merger can not merge result of type %T", m.name, result)
+			}
+			merged = reflect.New(v.Type()).Elem()
+			merged.Set(v)
+			continue
+		}
+		if merged.Type() != v.Type() {
+			return nil, perrors.Errorf("%s merger can not merge %s with %s", m.name, merged.Type(), v.Type())
+		}
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			merged.SetInt(m.mergeInt(merged.Int(), v.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			merged.SetUint(m.mergeUint(merged.Uint(), v.Uint()))
+		default:
+			merged.SetFloat(m.mergeFloat(merged.Float(), v.Float()))
+		}
+	}
+	if !merged.IsValid() {
+		return nil, nil
+	}
+	return merged.Interface(), nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(SetMergerName, NewSetMerger)
+}
+
+type setMerger struct{}
+
+// NewSetMerger returns a merger which unites the gxset.HashSet of all groups.
+func NewSetMerger() cluster.Merger {
+	return &setMerger{}
+}
+
+// Merge unites @results, the merged set has the same form, value or pointer, as the first result.
+func (m *setMerger) Merge(results []interface{}) (interface{}, error) {
+	var (
+		merged *gxset.HashSet
+		isPtr  bool
+	)
+	for _, result := range results {
+		var set *gxset.HashSet
+		switch v := result.(type) {
+		case nil:
+			continue
+		case *gxset.HashSet:
+			set = v
+			if merged == nil {
+				isPtr = true
+			}
+		case gxset.HashSet:
+			set = &v
+		default:
+			return nil, perrors.Errorf("set merger can not merge result of type %T", result)
+		}
+		if merged == nil {
+			merged = gxset.NewSet()
+		}
+		if set != nil {
+			merged.Add(set.Values()...)
+		}
+	}
+	if merged == nil {
+		return nil, nil
+	}
+	if isPtr {
+		return merged, nil
+	}
+	return *merged, nil
+}
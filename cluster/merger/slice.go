@@ -0,0 +1,66 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package merger
+
+import (
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func init() {
+	extension.SetMerger(SliceMergerName, NewSliceMerger)
+}
+
+type sliceMerger struct{}
+
+// NewSliceMerger returns a merger which concatenates the slices of all groups.
+func NewSliceMerger() cluster.Merger {
+	return &sliceMerger{}
+}
+
+// Merge concatenates @results in order, nil results are skipped.
+func (m *sliceMerger) Merge(results []interface{}) (interface{}, error) {
+	var merged reflect.Value
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		v := reflect.ValueOf(result)
+		if v.Kind() != reflect.Slice {
+			return nil, perrors.Errorf("slice merger can not merge result of type %T", result)
+		}
+		if !merged.IsValid() {
+			merged = reflect.MakeSlice(v.Type(), 0, v.Len())
+		} else if merged.Type() != v.Type() {
+			return nil, perrors.Errorf("slice merger can not merge %s with %s", merged.Type(), v.Type())
+		}
+		merged = reflect.AppendSlice(merged, v)
+	}
+	if !merged.IsValid() {
+		return nil, nil
+	}
+	return merged.Interface(), nil
+}
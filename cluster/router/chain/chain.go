@@ -64,6 +64,8 @@ type RouterChain struct {
 	notify chan struct{}
 	// Address cache
 	cache atomic.Value
+	// Guards building the address cache
+	cacheMutex sync.Mutex
 }
 
 func (c *RouterChain) GetNotifyChan() chan struct{} {
@@ -71,10 +73,29 @@ func (c *RouterChain) GetNotifyChan() chan struct{} {
 }
 
 // Route Loop routers in RouterChain and call Route method to determine the target invokers list.
+// The routers implementing router.CacheableRouter route with the address pools in the address cache.
 func (c *RouterChain) Route(url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
-	finalInvokers := c.invokers
-	for _, r := range c.copyRouters() {
-		finalInvokers = r.Route(c.invokers, url, invocation)
+	routers := c.copyRouters()
+	if shouldPool(routers) {
+		c.buildCache()
+	}
+
+	cache := c.loadCache()
+	if cache == nil {
+		finalInvokers := c.invokers
+		for _, r := range routers {
+			finalInvokers = r.Route(finalInvokers, url, invocation)
+		}
+		return finalInvokers
+	}
+
+	finalInvokers := cache.GetInvokers()
+	for _, r := range routers {
+		if cr, ok := r.(router.CacheableRouter); ok {
+			finalInvokers = cr.RouteWithCache(finalInvokers, cache, url, invocation)
+		} else {
+			finalInvokers = r.Route(finalInvokers, url, invocation)
+		}
 	}
 	return finalInvokers
 }
@@ -89,8 +110,10 @@ func (c *RouterChain) AddRouters(routers []router.PriorityRouter) {
 	newRouters = append(newRouters, routers...)
 	sortRouter(newRouters)
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 	c.routers = newRouters
+	c.mutex.Unlock()
+
+	c.buildCache()
 	go func() {
 		c.notify <- struct{}{}
 	}()
@@ -103,6 +126,7 @@ func (c *RouterChain) SetInvokers(invokers []protocol.Invoker) {
 	c.invokers = invokers
 	c.mutex.Unlock()
 
+	c.buildCache()
 	go func() {
 		c.notify <- struct{}{}
 	}()
@@ -129,6 +153,46 @@ func (c *RouterChain) copyInvokers() []protocol.Invoker {
 	return ret
 }
 
+// loadCache loads the address cache, it returns nil if the cache has not been built yet.
+func (c *RouterChain) loadCache() *InvokerCache {
+	v := c.cache.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*InvokerCache)
+}
+
+// buildCache builds the address cache for the latest invokers snapshot with the address pools of the routers
+// implementing router.Poolable.
+func (c *RouterChain) buildCache() {
+	c.cacheMutex.Lock()
+	defer c.cacheMutex.Unlock()
+
+	c.mutex.RLock()
+	invokers := c.invokers
+	c.mutex.RUnlock()
+
+	cache := BuildCache(invokers)
+	for _, r := range c.copyRouters() {
+		if p, ok := r.(router.Poolable); ok {
+			pool, meta := p.Pool(invokers)
+			cache.pools[p.Name()] = pool
+			cache.metadatas[p.Name()] = meta
+		}
+	}
+	c.cache.Store(cache)
+}
+
+// shouldPool checks if any of the routers needs to rebuild its address pool.
+func shouldPool(routers []router.PriorityRouter) bool {
+	for _, r := range routers {
+		if p, ok := r.(router.Poolable); ok && p.ShouldPool() {
+			return true
+		}
+	}
+	return false
+}
+
 func SetVSAndDRConfigByte(vs, dr []byte) {
 	virtualServiceConfigByte = vs
 	destinationRuleConfigByte = dr
@@ -0,0 +1,144 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// loadRetryInterval is the interval to retry loading the condition rules once it fails
+var loadRetryInterval = 10 * time.Second
+
+func init() {
+	extension.SetRouterFactory(constant.ConditionRouterName, NewConditionRouterFactory)
+}
+
+// ConditionRouterFactory is condition router's factory
+type ConditionRouterFactory struct{}
+
+// NewConditionRouterFactory constructs a new PriorityRouterFactory
+func NewConditionRouterFactory() router.PriorityRouterFactory {
+	return &ConditionRouterFactory{}
+}
+
+// NewPriorityRouter construct a new DynamicRouter as PriorityRouter, the uniform router config is ignored
+func (f *ConditionRouterFactory) NewPriorityRouter(_, _ []byte, _ chan struct{}) (router.PriorityRouter, error) {
+	return &DynamicRouter{}, nil
+}
+
+// DynamicRouter routes with the service scope and the application scope condition rules of the consumer, the rules
+// are loaded from config center once the consumer url is known and reloaded once they change. Loading is retried
+// every loadRetryInterval until both scopes are loaded, the application scope is disabled if the consumer url has
+// no application name.
+type DynamicRouter struct {
+	mutex         sync.RWMutex
+	loaded        bool
+	appDisabled   bool
+	failed        bool
+	nextLoad      time.Time
+	serviceRouter *listenableRouter
+	appRouter     *listenableRouter
+	routers       []*listenableRouter
+}
+
+// Route Determine the target invokers list.
+func (d *DynamicRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if url == nil {
+		return invokers
+	}
+	for _, r := range d.load(url) {
+		invokers = r.Route(invokers, url, invocation)
+	}
+	return invokers
+}
+
+// load creates the routers of the scopes which are not loaded yet and returns the loaded routers,
+// the failure is logged once and the loading is not retried before nextLoad
+func (d *DynamicRouter) load(url *common.URL) []*listenableRouter {
+	d.mutex.RLock()
+	if d.loaded || time.Now().Before(d.nextLoad) {
+		routers := d.routers
+		d.mutex.RUnlock()
+		return routers
+	}
+	d.mutex.RUnlock()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.loaded || time.Now().Before(d.nextLoad) {
+		return d.routers
+	}
+	var err error
+	if d.serviceRouter == nil {
+		d.serviceRouter, err = newServiceRouter(url)
+	}
+	if err == nil && d.appRouter == nil && !d.appDisabled {
+		if d.appRouter, err = newAppRouter(url); perrors.Cause(err) == errNoApplication {
+			logger.Infof("condition router of application is disabled for service %s, error: %v", url.ServiceKey(), err)
+			d.appDisabled, err = true, nil
+		}
+	}
+	d.routers = sortedRouters(d.serviceRouter, d.appRouter)
+	if err == nil {
+		d.loaded = true
+		return d.routers
+	}
+	if !d.failed {
+		d.failed = true
+		logger.Warnf("condition router of service %s is not loaded, retry every %s, error: %v",
+			url.ServiceKey(), loadRetryInterval, err)
+	}
+	d.nextLoad = time.Now().Add(loadRetryInterval)
+	return d.routers
+}
+
+// Priority Return Priority in dynamic router
+func (d *DynamicRouter) Priority() int64 {
+	return serviceRouterPriority
+}
+
+// URL Return URL in dynamic router
+func (d *DynamicRouter) URL() *common.URL {
+	return nil
+}
+
+// sortedRouters returns the non nil routers of @serviceRouter and @appRouter, sorted by priority.
+func sortedRouters(serviceRouter, appRouter *listenableRouter) []*listenableRouter {
+	routers := make([]*listenableRouter, 0, 2)
+	if serviceRouter != nil {
+		routers = append(routers, serviceRouter)
+	}
+	if appRouter != nil {
+		routers = append(routers, appRouter)
+	}
+	return routers
+}
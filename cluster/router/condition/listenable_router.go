@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	// serviceRouterPriority is the default priority of service scope condition router, run before application scope
+	serviceRouterPriority = 140
+	// appRouterPriority is the default priority of application scope condition router
+	appRouterPriority = 150
+)
+
+// errNoApplication means the application scope rule can never be loaded for the consumer
+var errNoApplication = perrors.New("application name is empty")
+
+// listenableRouter abstract router which listens to the dynamic configuration
+type listenableRouter struct {
+	ruleKey          string
+	priority         int64
+	mutex            sync.RWMutex
+	routerRule       *RouterRule
+	conditionRouters []*ConditionRouter
+}
+
+// newServiceRouter creates a listenable router for the service scope rule "{interface}:[version]:[group].condition-router"
+func newServiceRouter(url *common.URL) (*listenableRouter, error) {
+	return newListenableRouter(config_center.GetRuleKey(url)+constant.ConditionRouterRuleSuffix, serviceRouterPriority)
+}
+
+// newAppRouter creates a listenable router for the application scope rule "{application}.condition-router"
+func newAppRouter(url *common.URL) (*listenableRouter, error) {
+	appName := url.GetParam(constant.APPLICATION_KEY, "")
+	if len(appName) == 0 {
+		return nil, perrors.WithMessagef(errNoApplication, "url %s", url)
+	}
+	return newListenableRouter(appName+constant.ConditionRouterRuleSuffix, appRouterPriority)
+}
+
+// newListenableRouter creates a router which keeps its rules in sync with the config center key @ruleKey
+func newListenableRouter(ruleKey string, priority int64) (*listenableRouter, error) {
+	l := &listenableRouter{
+		ruleKey:  ruleKey,
+		priority: priority,
+	}
+
+	dynamicConfiguration := config.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		return nil, perrors.Errorf("Get dynamicConfiguration fail, dynamicConfiguration is nil, init config center plugin please")
+	}
+
+	dynamicConfiguration.AddListener(ruleKey, l)
+	value, err := dynamicConfiguration.GetRule(ruleKey, config_center.WithGroup(constant.DUBBO))
+	if err != nil {
+		dynamicConfiguration.RemoveListener(ruleKey, l)
+		return nil, perrors.WithMessagef(err, "get rule fail, config rule{%s}", ruleKey)
+	}
+	if len(value) != 0 {
+		l.Process(&config_center.ConfigChangeEvent{Key: ruleKey, Value: value, ConfigType: remoting.EventTypeAdd})
+	}
+	return l, nil
+}
+
+// Process Process config change event, generate routers and set them to the listenableRouter instance
+func (l *listenableRouter) Process(event *config_center.ConfigChangeEvent) {
+	logger.Infof("Notification of condition rule, change type is:[%s] , raw rule is:[%v]", event.ConfigType, event.Value)
+	if remoting.EventTypeDel == event.ConfigType {
+		l.setRouters(nil, nil)
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok {
+		logger.Errorf("Convert event content fail, raw content:[%v]", event.Value)
+		return
+	}
+
+	routerRule, err := getRule(content)
+	if err != nil {
+		logger.Errorf("Parse condition router rule fail, error:[%v]", err)
+		return
+	}
+	routers, err := generateConditions(routerRule)
+	if err != nil {
+		logger.Errorf("Generate condition routers fail, error:[%v]", err)
+		return
+	}
+	l.setRouters(routerRule, routers)
+}
+
+func (l *listenableRouter) setRouters(rule *RouterRule, routers []*ConditionRouter) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.routerRule = rule
+	l.conditionRouters = routers
+}
+
+// Route Determine the target invokers list.
+func (l *listenableRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	l.mutex.RLock()
+	routers := l.conditionRouters
+	l.mutex.RUnlock()
+
+	for _, r := range routers {
+		if len(invokers) == 0 {
+			break
+		}
+		invokers = r.Route(invokers, url, invocation)
+	}
+	return invokers
+}
+
+// Priority Return Priority in listenable router
+func (l *listenableRouter) Priority() int64 {
+	return l.priority
+}
+
+// URL Return URL in listenable router
+func (l *listenableRouter) URL() *common.URL {
+	return nil
+}
+
+// generateConditions creates a condition router for every condition of the rule
+func generateConditions(rule *RouterRule) ([]*ConditionRouter, error) {
+	if rule == nil || !rule.Valid {
+		return nil, nil
+	}
+	routers := make([]*ConditionRouter, 0, len(rule.Conditions))
+	for _, condition := range rule.Conditions {
+		router, err := NewConditionRouterWithRule(condition)
+		if err != nil {
+			return nil, err
+		}
+		router.Force = rule.Force
+		router.Runtime = rule.Runtime
+		router.enabled = rule.Enabled
+		router.priority = int64(rule.Priority)
+		routers = append(routers, router)
+	}
+	return routers, nil
+}
@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	conditionRule = `scope: application
+key: mock-app
+force: true
+conditions:
+  - "=> host = 10.20.*"
+`
+	updatedConditionRule = `scope: application
+key: mock-app
+force: true
+conditions:
+  - "=> host = 192.168.1.1"
+`
+)
+
+func TestDynamicRouter(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: conditionRule}
+	dc, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer config.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	r, err := NewConditionRouterFactory().NewPriorityRouter(nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(serviceRouterPriority), r.Priority())
+
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	invokers := buildInvokers(t, conditionProviderURL1, conditionProviderURL2, conditionProviderURL3)
+	inv := invocation.NewRPCInvocation("getUser", nil, nil)
+	// no consumer url, no rule
+	assert.Equal(t, 3, len(r.Route(invokers, nil, inv)))
+	// the mock config center returns the same rule for both service and application keys
+	assert.Equal(t, 2, len(r.Route(invokers, consumerURL, inv)))
+
+	dr := r.(*DynamicRouter)
+	assert.Equal(t, 2, len(dr.routers))
+	for _, l := range dr.routers {
+		l.Process(&config_center.ConfigChangeEvent{Value: updatedConditionRule, ConfigType: remoting.EventTypeUpdate})
+	}
+	assert.Equal(t, 1, len(r.Route(invokers, consumerURL, inv)))
+
+	for _, l := range dr.routers {
+		l.Process(&config_center.ConfigChangeEvent{ConfigType: remoting.EventTypeDel})
+	}
+	assert.Equal(t, 3, len(r.Route(invokers, consumerURL, inv)))
+}
+
+func TestNewListenableRouterWithoutConfigCenter(t *testing.T) {
+	config.GetEnvInstance().SetDynamicConfiguration(nil)
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	_, err := newAppRouter(consumerURL)
+	assert.NotNil(t, err)
+	dr := &DynamicRouter{}
+	assert.Equal(t, 0, len(dr.load(consumerURL)))
+	assert.False(t, dr.loaded)
+	assert.True(t, dr.failed)
+	assert.True(t, dr.nextLoad.After(time.Now()))
+}
+
+func TestDynamicRouterRetryLoad(t *testing.T) {
+	config.GetEnvInstance().SetDynamicConfiguration(nil)
+	defer config.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	interval := loadRetryInterval
+	loadRetryInterval = 100 * time.Millisecond
+	defer func() {
+		loadRetryInterval = interval
+	}()
+
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	invokers := buildInvokers(t, conditionProviderURL1, conditionProviderURL2, conditionProviderURL3)
+	inv := invocation.NewRPCInvocation("getUser", nil, nil)
+	r := &DynamicRouter{}
+	// config center is not ready yet, nothing is loaded
+	assert.Equal(t, 3, len(r.Route(invokers, consumerURL, inv)))
+	assert.False(t, r.loaded)
+
+	factory := &config_center.MockDynamicConfigurationFactory{Content: conditionRule}
+	dc, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dc)
+	// the loading is not retried until the retry interval passes
+	assert.Equal(t, 3, len(r.Route(invokers, consumerURL, inv)))
+	time.Sleep(loadRetryInterval)
+	assert.Equal(t, 2, len(r.Route(invokers, consumerURL, inv)))
+	assert.True(t, r.loaded)
+	assert.Equal(t, 2, len(r.routers))
+}
+
+func TestDynamicRouterWithoutApplication(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: conditionRule}
+	dc, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer config.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	consumerURL.DelParam(constant.APPLICATION_KEY)
+	invokers := buildInvokers(t, conditionProviderURL1, conditionProviderURL2, conditionProviderURL3)
+	r := &DynamicRouter{}
+	// the application scope is disabled, the service scope is loaded
+	assert.Equal(t, 2, len(r.Route(invokers, consumerURL, invocation.NewRPCInvocation("getUser", nil, nil))))
+	assert.True(t, r.loaded)
+	assert.True(t, r.appDisabled)
+	assert.Equal(t, 1, len(r.routers))
+}
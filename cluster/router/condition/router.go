@@ -0,0 +1,418 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+import (
+	gxset "github.com/dubbogo/gost/container/set"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// pattern route pattern regex
+	pattern = `([&!=,]*)\s*([^&!=,\s]+)`
+
+	// whenThenSeparator separates the when condition and the then condition of a rule
+	whenThenSeparator = "=>"
+
+	// addressKey matches ip:port of the url
+	addressKey = "address"
+	// hostKey matches ip of the url
+	hostKey = "host"
+	// argumentsKeyPrefix matches the invocation arguments, e.g. arguments[0]
+	argumentsKeyPrefix = "arguments["
+	// attachmentsKeyPrefix matches the invocation attachments, e.g. attachments[dubbo.tag]
+	attachmentsKeyPrefix = "attachments["
+)
+
+var routerPatternReg = regexp.MustCompile(pattern)
+
+// ConditionRouter condition router struct, it routes with a rule like "method = getUser => host = 10.20.*"
+type ConditionRouter struct {
+	Pattern       string
+	url           *common.URL
+	priority      int64
+	Force         bool
+	Runtime       bool
+	enabled       bool
+	WhenCondition map[string]MatchPair
+	ThenCondition map[string]MatchPair
+
+	// cache keeps the route result by method name for the latest invokers snapshot if the rule is not runtime.
+	cacheLock     sync.RWMutex
+	cacheInvokers []protocol.Invoker
+	cache         map[string][]protocol.Invoker
+}
+
+// NewConditionRouterWithRule initializes condition router with a single rule like "host = 10.20.* => host != 10.20.153.11"
+func NewConditionRouterWithRule(rule string) (*ConditionRouter, error) {
+	var (
+		whenRule string
+		thenRule string
+	)
+	rule = strings.Replace(rule, "consumer.", "", -1)
+	rule = strings.Replace(rule, "provider.", "", -1)
+	i := strings.Index(rule, whenThenSeparator)
+	if i < 0 {
+		whenRule = ""
+		thenRule = strings.TrimSpace(rule)
+	} else {
+		whenRule = strings.TrimSpace(rule[:i])
+		thenRule = strings.TrimSpace(rule[i+len(whenThenSeparator):])
+	}
+
+	when, err := parseWhenOrThen(whenRule, "true")
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "failed to parse when condition of rule %s", rule)
+	}
+	then, err := parseWhenOrThen(thenRule, "false")
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "failed to parse then condition of rule %s", rule)
+	}
+	// a "false" or empty then condition means all providers are black listed
+	if len(thenRule) != 0 && thenRule != "false" && len(then) == 0 {
+		return nil, perrors.Errorf("illegal then condition of rule %s", rule)
+	}
+
+	return &ConditionRouter{
+		Pattern:       pattern,
+		WhenCondition: when,
+		ThenCondition: then,
+		enabled:       true,
+		Runtime:       true,
+	}, nil
+}
+
+// NewConditionRouter initializes condition router with a "route://" url carrying the rule in the "rule" parameter
+func NewConditionRouter(url *common.URL) (*ConditionRouter, error) {
+	if url == nil {
+		return nil, perrors.Errorf("Illegal route URL!")
+	}
+	rule := decodeRule(url.GetParam(constant.RULE_KEY, ""))
+	if len(rule) == 0 {
+		return nil, perrors.Errorf("Illegal route rule!")
+	}
+
+	router, err := NewConditionRouterWithRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	router.url = url
+	router.priority = url.GetParamInt(constant.RouterPriorityKey, 0)
+	router.Force = url.GetParamBool(constant.RouterForceKey, false)
+	router.Runtime = url.GetParamBool(constant.RUNTIME_KEY, true)
+	router.enabled = url.GetParamBool(constant.ENABLED_KEY, true)
+	return router, nil
+}
+
+// Priority Return Priority in condition router
+func (c *ConditionRouter) Priority() int64 {
+	return c.priority
+}
+
+// URL Return URL in condition router
+func (c *ConditionRouter) URL() *common.URL {
+	return c.url
+}
+
+// Enabled Return is condition router is enabled
+// true: enabled
+// false: disabled
+func (c *ConditionRouter) Enabled() bool {
+	return c.enabled
+}
+
+// Route Determine the target invokers list.
+func (c *ConditionRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if !c.Enabled() || len(invokers) == 0 {
+		return invokers
+	}
+
+	if c.Runtime {
+		return c.route(invokers, url, invocation)
+	}
+
+	methodName := ""
+	if invocation != nil {
+		methodName = invocation.MethodName()
+	}
+	if result, ok := c.getCache(invokers, methodName); ok {
+		return result
+	}
+	result := c.route(invokers, url, invocation)
+	c.setCache(invokers, methodName, result)
+	return result
+}
+
+func (c *ConditionRouter) route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	isMatchWhen := c.MatchWhen(url, invocation)
+	if !isMatchWhen {
+		return invokers
+	}
+	if len(c.ThenCondition) == 0 {
+		logger.Warnf("The current consumer in the service blacklist. consumer: %v, router: %v", url, c.URL())
+		return []protocol.Invoker{}
+	}
+
+	result := make([]protocol.Invoker, 0, len(invokers))
+	for _, invoker := range invokers {
+		if c.MatchThen(invoker.GetURL(), url) {
+			result = append(result, invoker)
+		}
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+	if c.Force {
+		logger.Warnf("The route result is empty and force execute. consumer: %v, router: %v", url, c.URL())
+		return result
+	}
+	return invokers
+}
+
+// getCache returns the cached result of @methodName if the invokers snapshot doesn't change.
+func (c *ConditionRouter) getCache(invokers []protocol.Invoker, methodName string) ([]protocol.Invoker, bool) {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	if !isSameSnapshot(c.cacheInvokers, invokers) {
+		return nil, false
+	}
+	result, ok := c.cache[methodName]
+	return result, ok
+}
+
+func (c *ConditionRouter) setCache(invokers []protocol.Invoker, methodName string, result []protocol.Invoker) {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	if !isSameSnapshot(c.cacheInvokers, invokers) {
+		c.cacheInvokers = invokers
+		c.cache = make(map[string][]protocol.Invoker, 8)
+	}
+	c.cache[methodName] = result
+}
+
+// isSameSnapshot checks whether the two invoker slices share the same backing array, the router chain replaces the
+// whole slice once addresses change.
+func isSameSnapshot(left, right []protocol.Invoker) bool {
+	return len(left) != 0 && len(left) == len(right) && &left[0] == &right[0]
+}
+
+// MatchWhen MatchWhen
+func (c *ConditionRouter) MatchWhen(url *common.URL, invocation protocol.Invocation) bool {
+	if len(c.WhenCondition) == 0 {
+		return true
+	}
+	return doMatch(url, nil, invocation, c.WhenCondition, true)
+}
+
+// MatchThen MatchThen
+func (c *ConditionRouter) MatchThen(url *common.URL, param *common.URL) bool {
+	if len(c.ThenCondition) == 0 {
+		return false
+	}
+	return doMatch(url, param, nil, c.ThenCondition, false)
+}
+
+func parseWhenOrThen(rule string, ignored string) (map[string]MatchPair, error) {
+	if len(rule) == 0 || rule == ignored {
+		return make(map[string]MatchPair), nil
+	}
+	return parseRule(rule)
+}
+
+// parseRule parses the when or then part of a rule into match pairs keyed by the condition key
+func parseRule(routeRule string) (map[string]MatchPair, error) {
+	condition := make(map[string]MatchPair)
+	if len(routeRule) == 0 {
+		return condition, nil
+	}
+
+	var (
+		pair   MatchPair
+		values *gxset.HashSet
+		exists bool
+	)
+	key := ""
+	for _, matcher := range routerPatternReg.FindAllStringSubmatch(routeRule, -1) {
+		separator := matcher[1]
+		content := matcher[2]
+
+		switch separator {
+		case "":
+			pair = newMatchPair()
+			key = content
+			condition[content] = pair
+		case "&":
+			if pair, exists = condition[content]; !exists {
+				pair = newMatchPair()
+				condition[content] = pair
+			}
+			key = content
+		case "=":
+			if len(key) == 0 {
+				return nil, perrors.Errorf("Illegal route rule \"%s\", The error char '%s' before '%s'", routeRule, separator, content)
+			}
+			values = pair.Matches
+			values.Add(content)
+		case "!=":
+			if len(key) == 0 {
+				return nil, perrors.Errorf("Illegal route rule \"%s\", The error char '%s' before '%s'", routeRule, separator, content)
+			}
+			values = pair.Mismatches
+			values.Add(content)
+		case ",":
+			if values == nil || values.Empty() {
+				return nil, perrors.Errorf("Illegal route rule \"%s\", The error char '%s' before '%s'", routeRule, separator, content)
+			}
+			values.Add(content)
+		default:
+			return nil, perrors.Errorf("Illegal route rule \"%s\", The error char '%s' before '%s'", routeRule, separator, content)
+		}
+	}
+	return condition, nil
+}
+
+// doMatch checks every condition key against the sample url or the invocation
+func doMatch(url *common.URL, param *common.URL, invocation protocol.Invocation, cond map[string]MatchPair, isWhenCondition bool) bool {
+	result := false
+	for key, pair := range cond {
+		sampleValue, ok := sampleValueOf(key, url, invocation, isWhenCondition)
+		if ok {
+			if !pair.isMatch(sampleValue, param) {
+				return false
+			}
+			result = true
+			continue
+		}
+		// the key doesn't exist in the sample, only "!=" conditions are satisfied
+		if !pair.Matches.Empty() {
+			return false
+		}
+		result = true
+	}
+	return result
+}
+
+// sampleValueOf gets the value of the condition key from the url or, for the when condition, the invocation
+func sampleValueOf(key string, url *common.URL, invocation protocol.Invocation, isWhenCondition bool) (string, bool) {
+	switch {
+	case key == constant.METHOD_KEY && invocation != nil:
+		return invocation.MethodName(), true
+	case isWhenCondition && strings.HasPrefix(key, argumentsKeyPrefix) && strings.HasSuffix(key, "]"):
+		if invocation == nil {
+			return "", false
+		}
+		index, err := strconv.Atoi(key[len(argumentsKeyPrefix) : len(key)-1])
+		if err != nil || index < 0 || index >= len(invocation.Arguments()) {
+			return "", false
+		}
+		return fmt.Sprint(invocation.Arguments()[index]), true
+	case isWhenCondition && strings.HasPrefix(key, attachmentsKeyPrefix) && strings.HasSuffix(key, "]"):
+		if invocation == nil {
+			return "", false
+		}
+		v := invocation.Attachment(key[len(attachmentsKeyPrefix) : len(key)-1])
+		if v == nil {
+			return "", false
+		}
+		if s, ok := v.([]string); ok && len(s) != 0 {
+			return s[0], true
+		}
+		return fmt.Sprint(v), true
+	}
+
+	if url == nil {
+		return "", false
+	}
+	var value string
+	switch key {
+	case addressKey:
+		value = url.Location
+		if len(value) == 0 && len(url.Ip) != 0 {
+			value = url.Ip + ":" + url.Port
+		}
+		return value, len(value) != 0
+	case hostKey:
+		value = url.Ip
+	}
+	if len(value) == 0 {
+		value = url.GetRawParam(key)
+	}
+	if len(value) == 0 {
+		value = url.GetParam(constant.PREFIX_DEFAULT_KEY+key, "")
+	}
+	return value, len(value) != 0
+}
+
+// MatchPair Match key pair, condition process
+type MatchPair struct {
+	Matches    *gxset.HashSet
+	Mismatches *gxset.HashSet
+}
+
+func newMatchPair() MatchPair {
+	return MatchPair{
+		Matches:    gxset.NewSet(),
+		Mismatches: gxset.NewSet(),
+	}
+}
+
+func (pair MatchPair) isMatch(value string, param *common.URL) bool {
+	if !pair.Mismatches.Empty() && hasMatched(pair.Mismatches, value, param) {
+		return false
+	}
+	if !pair.Matches.Empty() {
+		return hasMatched(pair.Matches, value, param)
+	}
+	return !pair.Mismatches.Empty()
+}
+
+// hasMatched checks whether any pattern of @patterns matches @value
+func hasMatched(patterns *gxset.HashSet, value string, param *common.URL) bool {
+	for _, p := range patterns.Values() {
+		if isMatchGlobPattern(p.(string), value, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeRule decodes the url encoded rule, it returns the origin rule if it is not encoded
+func decodeRule(rule string) string {
+	decoded, err := url.QueryUnescape(rule)
+	if err != nil {
+		return rule
+	}
+	return decoded
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"strconv"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// RouterRule is the condition router rule which is written by dubbo admin into config center, e.g.
+//
+//	scope: application
+//	key: mock-app
+//	priority: 1
+//	force: true
+//	runtime: true
+//	conditions:
+//	  - "method = getUser => host = 10.20.153.10"
+//	  - "=> host != 10.20.*"
+type RouterRule struct {
+	router.BaseRouterRule `yaml:",inline"`
+	Conditions            []string
+}
+
+// getRule parses the raw yaml rule into RouterRule, a rule is enabled and runtime unless it says no.
+func getRule(rawRule string) (*RouterRule, error) {
+	rule := &RouterRule{
+		BaseRouterRule: router.BaseRouterRule{
+			Enabled: true,
+			Runtime: true,
+		},
+	}
+	if err := yaml.Unmarshal([]byte(rawRule), rule); err != nil {
+		return nil, perrors.Wrapf(err, "failed to parse condition router rule: %s", rawRule)
+	}
+	rule.RawRule = rawRule
+	rule.Dynamic = true
+	rule.Valid = len(rule.Conditions) != 0 &&
+		(rule.Scope == constant.RouterApplicationScope || rule.Scope == constant.RouterServiceScope)
+	return rule, nil
+}
+
+// isMatchGlobPattern checks whether @value matches @pattern. The pattern supports:
+//
+//	$key      refers to the parameter @key of @param
+//	*         matches any value
+//	10.20.*   wildcard at the beginning, the end or in the middle
+//	100~200   an inclusive numeric range
+func isMatchGlobPattern(pattern string, value string, param *common.URL) bool {
+	if param != nil && strings.HasPrefix(pattern, "$") {
+		pattern = param.GetRawParam(pattern[1:])
+	}
+	if pattern == "*" {
+		return true
+	}
+	if len(pattern) == 0 || len(value) == 0 {
+		return len(pattern) == 0 && len(value) == 0
+	}
+	if inRange, ok := matchRange(pattern, value); ok {
+		return inRange
+	}
+
+	i := strings.LastIndex(pattern, "*")
+	switch {
+	case i == -1:
+		// doesn't find "*"
+		return value == pattern
+	case i == len(pattern)-1:
+		// "*" is at the end
+		return strings.HasPrefix(value, pattern[:i])
+	case i == 0:
+		// "*" is at the beginning
+		return strings.HasSuffix(value, pattern[i+1:])
+	default:
+		// "*" is in the middle
+		prefix, suffix := pattern[:i], pattern[i+1:]
+		return len(value) >= len(prefix)+len(suffix) && strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix)
+	}
+}
+
+// matchRange checks whether @value is in the range @pattern like "100~200", the second returned value is false
+// if @pattern is not a numeric range.
+func matchRange(pattern string, value string) (bool, bool) {
+	i := strings.Index(pattern, "~")
+	if i <= 0 || i == len(pattern)-1 {
+		return false, false
+	}
+	low, err := strconv.ParseFloat(strings.TrimSpace(pattern[:i]), 64)
+	if err != nil {
+		return false, false
+	}
+	high, err := strconv.ParseFloat(strings.TrimSpace(pattern[i+1:]), 64)
+	if err != nil {
+		return false, false
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, true
+	}
+	return v >= low && v <= high, true
+}
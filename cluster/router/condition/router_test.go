@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const (
+	conditionConsumerURL  = "consumer://192.168.2.1/com.foo.BarService?application=mock-app"
+	conditionProviderURL1 = "dubbo://10.20.3.3:20880/com.foo.BarService?weight=100"
+	conditionProviderURL2 = "dubbo://10.20.3.4:20880/com.foo.BarService?weight=200"
+	conditionProviderURL3 = "dubbo://192.168.1.1:20880/com.foo.BarService?weight=300"
+)
+
+func buildInvokers(t *testing.T, urls ...string) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, len(urls))
+	for _, u := range urls {
+		url, err := common.NewURL(u)
+		assert.Nil(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func TestParseRule(t *testing.T) {
+	cond, err := parseRule("host = 10.20.153.10,10.20.153.11 & method != get*")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cond))
+	assert.True(t, cond["host"].Matches.Contains("10.20.153.10"))
+	assert.True(t, cond["host"].Matches.Contains("10.20.153.11"))
+	assert.True(t, cond["method"].Mismatches.Contains("get*"))
+
+	_, err = parseRule("= 10.20.153.10")
+	assert.NotNil(t, err)
+
+	_, err = parseRule("host , 10.20.153.10")
+	assert.NotNil(t, err)
+}
+
+func TestIsMatchGlobPattern(t *testing.T) {
+	assert.True(t, isMatchGlobPattern("*", "value", nil))
+	assert.True(t, isMatchGlobPattern("", "", nil))
+	assert.False(t, isMatchGlobPattern("", "value", nil))
+	assert.True(t, isMatchGlobPattern("value", "value", nil))
+	assert.True(t, isMatchGlobPattern("v*", "value", nil))
+	assert.True(t, isMatchGlobPattern("*e", "value", nil))
+	assert.True(t, isMatchGlobPattern("v*e", "value", nil))
+	assert.False(t, isMatchGlobPattern("v*x", "value", nil))
+	assert.True(t, isMatchGlobPattern("100~200", "150", nil))
+	assert.False(t, isMatchGlobPattern("100~200", "250", nil))
+	assert.False(t, isMatchGlobPattern("100~200", "abc", nil))
+
+	url, _ := common.NewURL(conditionConsumerURL)
+	assert.True(t, isMatchGlobPattern("$application", "mock-app", url))
+}
+
+func TestConditionRouterRoute(t *testing.T) {
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	invokers := buildInvokers(t, conditionProviderURL1, conditionProviderURL2, conditionProviderURL3)
+	inv := invocation.NewRPCInvocation("getUser", []interface{}{"tom", 18}, map[string]interface{}{"dubbo.tag": "gray"})
+
+	tests := []struct {
+		name     string
+		rule     string
+		force    bool
+		expected int
+	}{
+		{"black list", "host = 192.168.2.1 => ", false, 0},
+		{"when not matched", "host = 192.168.2.2 => host = 10.20.3.3", false, 3},
+		{"wildcard", "=> host = 10.20.*", false, 2},
+		{"mismatch", "=> host != 10.20.*", false, 1},
+		{"address", "=> address = 10.20.3.3:20880", false, 1},
+		{"method", "method = getUser => host = 10.20.3.4", false, 1},
+		{"method not matched", "method = setUser => host = 10.20.3.4", false, 3},
+		{"arguments", "arguments[1] = 10~20 => host = 192.168.1.1", false, 1},
+		{"attachments", "attachments[dubbo.tag] = gray => weight = 100,200", false, 2},
+		{"parameter reference", "application = mock-app => host = $host", false, 3},
+		{"empty result without force", "=> host = 1.1.1.1", false, 3},
+		{"empty result with force", "=> host = 1.1.1.1", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewConditionRouterWithRule(tt.rule)
+			assert.Nil(t, err)
+			r.Force = tt.force
+			assert.Equal(t, tt.expected, len(r.Route(invokers, consumerURL, inv)))
+		})
+	}
+}
+
+func TestNewConditionRouter(t *testing.T) {
+	url, err := common.NewURL("route://0.0.0.0/com.foo.BarService?rule=" +
+		"host%3D192.168.2.1%3D%3Ehost%3D10.20.3.4&force=true&priority=3&runtime=false")
+	assert.Nil(t, err)
+	r, err := NewConditionRouter(url)
+	assert.Nil(t, err)
+	assert.True(t, r.Force)
+	assert.False(t, r.Runtime)
+	assert.Equal(t, int64(3), r.Priority())
+	assert.Equal(t, url, r.URL())
+
+	consumerURL, _ := common.NewURL(conditionConsumerURL)
+	invokers := buildInvokers(t, conditionProviderURL1, conditionProviderURL2, conditionProviderURL3)
+	inv := invocation.NewRPCInvocation("getUser", nil, nil)
+	result := r.Route(invokers, consumerURL, inv)
+	assert.Equal(t, 1, len(result))
+	// the result of non runtime rule is cached for the same invokers snapshot
+	assert.Equal(t, result, r.Route(invokers, consumerURL, inv))
+	assert.Equal(t, 1, len(r.cache))
+
+	_, err = NewConditionRouter(nil)
+	assert.NotNil(t, err)
+	url, _ = common.NewURL("route://0.0.0.0/com.foo.BarService")
+	_, err = NewConditionRouter(url)
+	assert.NotNil(t, err)
+}
+
+func TestGetRule(t *testing.T) {
+	rule, err := getRule(`
+scope: application
+key: mock-app
+priority: 1
+force: true
+conditions:
+  - "method = getUser => host = 10.20.153.10"
+  - "=> host != 10.20.*"
+`)
+	assert.Nil(t, err)
+	assert.True(t, rule.Valid)
+	assert.True(t, rule.Enabled)
+	assert.True(t, rule.Runtime)
+	assert.True(t, rule.Force)
+	assert.Equal(t, 1, rule.Priority)
+	assert.Equal(t, "mock-app", rule.Key)
+	assert.Equal(t, 2, len(rule.Conditions))
+
+	rule, err = getRule("scope: unknown\nconditions:\n  - \"=> host = 1.1.1.1\"")
+	assert.Nil(t, err)
+	assert.False(t, rule.Valid)
+
+	_, err = getRule("conditions: {")
+	assert.NotNil(t, err)
+}
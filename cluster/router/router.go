@@ -63,6 +63,16 @@ type Poolable interface {
 	Name() string
 }
 
+// CacheableRouter is a router which routes with the address pools it has built from the address cache, instead of
+// scanning the invokers on every call.
+type CacheableRouter interface {
+	PriorityRouter
+	Poolable
+
+	// RouteWithCache determines the target invokers list with the address cache built on the same invokers snapshot.
+	RouteWithCache([]protocol.Invoker, Cache, *common.URL, protocol.Invocation) []protocol.Invoker
+}
+
 // AddrPool is an address pool, backed by a snapshot of address list, divided into categories.
 type AddrPool map[string]*roaring.Bitmap
 
@@ -0,0 +1,338 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tag
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/RoaringBitmap/roaring"
+	"go.uber.org/atomic"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	name = "tag-router"
+	// tagRouterPriority makes tag router run before condition routers
+	tagRouterPriority = 100
+
+	// staticPrefix prefixes the pool of the addresses tagged by their url
+	staticPrefix = "static."
+	// dynamicPrefix prefixes the pool of the addresses tagged by the dynamic rule
+	dynamicPrefix = "dynamic."
+	// dynamicAllKey is the key of the pool of all addresses tagged by the dynamic rule
+	dynamicAllKey = "dynamic"
+)
+
+// tagRouter routes to the providers which have the tag of the invocation. A provider is tagged either by its url
+// parameter "dubbo.tag" or by the dynamic tag rule "{provider application}.tag-router" in config center.
+type tagRouter struct {
+	mutex       sync.RWMutex
+	application string
+	rule        *RouterRule
+	ruleChanged atomic.Bool
+}
+
+// newTagRouter creates a tag router, the dynamic rule is subscribed once the provider application is known
+func newTagRouter() *tagRouter {
+	return &tagRouter{}
+}
+
+// addrMetadata keeps the tag rule which the address pool is built with
+type addrMetadata struct {
+	rule *RouterRule
+}
+
+// Source indicates where the metadata comes from.
+func (m *addrMetadata) Source() string {
+	return name
+}
+
+// Route determines the target invokers list by scanning the invokers.
+func (c *tagRouter) Route(invokers []protocol.Invoker, url *common.URL, invocation protocol.Invocation) []protocol.Invoker {
+	if len(invokers) == 0 {
+		return invokers
+	}
+	c.subscribe(invokers)
+	pool := buildPool(invokers, c.getRule())
+	return c.route(invokers, pool, c.getRule(), url, invocation)
+}
+
+// RouteWithCache determines the target invokers list with the address pool built on the invokers snapshot, it falls
+// back to Route if the invokers have been filtered by the routers before.
+func (c *tagRouter) RouteWithCache(invokers []protocol.Invoker, cache router.Cache, url *common.URL,
+	invocation protocol.Invocation) []protocol.Invoker {
+	pool := cache.FindAddrPool(c)
+	meta, ok := cache.FindAddrMeta(c).(*addrMetadata)
+	if pool == nil || !ok || !isSameSnapshot(cache.GetInvokers(), invokers) {
+		return c.Route(invokers, url, invocation)
+	}
+	if len(invokers) == 0 {
+		return invokers
+	}
+	return c.route(invokers, pool, meta.rule, url, invocation)
+}
+
+// Pool builds the address pools of the static tags and the dynamic tags from the invokers.
+func (c *tagRouter) Pool(invokers []protocol.Invoker) (router.AddrPool, router.AddrMetadata) {
+	c.subscribe(invokers)
+	c.ruleChanged.Store(false)
+	rule := c.getRule()
+	return buildPool(invokers, rule), &addrMetadata{rule: rule}
+}
+
+// ShouldPool returns true once the dynamic tag rule changes.
+func (c *tagRouter) ShouldPool() bool {
+	return c.ruleChanged.Load()
+}
+
+// Name returns the name of the tag router
+func (c *tagRouter) Name() string {
+	return name
+}
+
+// Priority returns the priority of the tag router
+func (c *tagRouter) Priority() int64 {
+	return tagRouterPriority
+}
+
+// URL returns the url of the tag router
+func (c *tagRouter) URL() *common.URL {
+	return nil
+}
+
+// Process updates the dynamic tag rule once it changes in config center
+func (c *tagRouter) Process(event *config_center.ConfigChangeEvent) {
+	logger.Infof("Notification of tag rule, change type is:[%s] , raw rule is:[%v]", event.ConfigType, event.Value)
+	if remoting.EventTypeDel == event.ConfigType {
+		c.setRule(nil)
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok {
+		logger.Errorf("Convert event content fail, raw content:[%v]", event.Value)
+		return
+	}
+	rule, err := getRule(content)
+	if err != nil {
+		logger.Errorf("Parse tag router rule fail, error:[%v]", err)
+		return
+	}
+	c.setRule(rule)
+}
+
+func (c *tagRouter) getRule() *RouterRule {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.rule
+}
+
+func (c *tagRouter) setRule(rule *RouterRule) {
+	c.mutex.Lock()
+	c.rule = rule
+	c.mutex.Unlock()
+	c.ruleChanged.Store(true)
+}
+
+// subscribe listens to the tag rule of the provider application of the invokers, the tag rule belongs to the
+// provider application.
+func (c *tagRouter) subscribe(invokers []protocol.Invoker) {
+	if len(invokers) == 0 {
+		return
+	}
+	providerURL := invokers[0].GetURL()
+	application := providerURL.GetParam(constant.RemoteApplicationKey, providerURL.GetParam(constant.APPLICATION_KEY, ""))
+	if len(application) == 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	if c.application == application {
+		c.mutex.Unlock()
+		return
+	}
+	oldApplication := c.application
+	c.application = application
+	c.mutex.Unlock()
+
+	dynamicConfiguration := config.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		logger.Warnf("Get dynamicConfiguration fail, dynamic tag rule of %s is disabled", application)
+		return
+	}
+	if len(oldApplication) != 0 {
+		dynamicConfiguration.RemoveListener(oldApplication+constant.TagRouterRuleSuffix, c)
+	}
+	key := application + constant.TagRouterRuleSuffix
+	dynamicConfiguration.AddListener(key, c)
+	value, err := dynamicConfiguration.GetRule(key, config_center.WithGroup(constant.DUBBO))
+	if err != nil {
+		logger.Errorf("Get tag rule fail, config rule{%s}, error:[%v]", key, err)
+		return
+	}
+	if len(value) != 0 {
+		c.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
+	}
+}
+
+// route filters @invokers with the address pool, Java's TagRouter semantics are kept:
+//  1. With a tag, the providers bound to the tag by the dynamic rule are preferred, then the providers tagged by
+//     url. Without force, the providers which are neither bound by the dynamic rule nor tagged by url are the
+//     fallback.
+//  2. Without a tag, only the untagged providers are chosen.
+func (c *tagRouter) route(invokers []protocol.Invoker, pool router.AddrPool, rule *RouterRule, url *common.URL,
+	invocation protocol.Invocation) []protocol.Invoker {
+	tag := getTag(url, invocation)
+	all := roaring.NewBitmap()
+	all.AddRange(0, uint64(len(invokers)))
+	untagged := findPool(pool, staticPrefix)
+
+	// the dynamic rule is invalid or disabled, route with the static tags only
+	if rule == nil || !rule.Valid || !rule.Enabled {
+		if len(tag) == 0 {
+			return pick(invokers, untagged)
+		}
+		result := findPool(pool, staticPrefix+tag)
+		if result.IsEmpty() && !isForceUseTag(url, invocation) {
+			return pick(invokers, untagged)
+		}
+		return pick(invokers, result)
+	}
+
+	dynamicAll := findPool(pool, dynamicAllKey)
+	if len(tag) != 0 {
+		var result *roaring.Bitmap
+		if rule.hasTag(tag) && len(rule.getTagNameToAddresses(tag)) != 0 {
+			result = findPool(pool, dynamicPrefix+tag)
+			if !result.IsEmpty() || rule.Force {
+				return pick(invokers, result)
+			}
+		} else {
+			result = findPool(pool, staticPrefix+tag)
+		}
+		if !result.IsEmpty() || isForceUseTag(url, invocation) {
+			return pick(invokers, result)
+		}
+		// fall back to the providers which are neither bound to a tag by the dynamic rule nor tagged by url
+		return pick(invokers, roaring.AndNot(untagged, dynamicAll))
+	}
+
+	// without a tag, neither the providers bound by the dynamic rule nor the ones tagged with a dynamic tag are chosen
+	result := roaring.AndNot(all, dynamicAll)
+	if result.IsEmpty() {
+		return []protocol.Invoker{}
+	}
+	for _, tagName := range rule.getTagNames() {
+		result.AndNot(findPool(pool, staticPrefix+tagName))
+	}
+	return pick(invokers, result)
+}
+
+// buildPool builds the address pools: "static.{tag}" for the url tags ("static." for untagged providers),
+// "dynamic.{tag}" for the tags of the dynamic rule and "dynamic" for all addresses bound by the dynamic rule.
+func buildPool(invokers []protocol.Invoker, rule *RouterRule) router.AddrPool {
+	pool := make(router.AddrPool, 8)
+	for i, invoker := range invokers {
+		url := invoker.GetURL()
+		addToPool(pool, staticPrefix+url.GetParam(constant.Tagkey, ""), i)
+		if rule == nil {
+			continue
+		}
+		for _, tagName := range tagNamesOf(rule, url) {
+			addToPool(pool, dynamicPrefix+tagName, i)
+			addToPool(pool, dynamicAllKey, i)
+		}
+	}
+	return pool
+}
+
+// tagNamesOf finds the tags of the rule which the provider url is bound to by "ip:port" or "ip"
+func tagNamesOf(rule *RouterRule, url *common.URL) []string {
+	if tagNames, ok := rule.addressToTagNames[url.Location]; ok {
+		return tagNames
+	}
+	return rule.addressToTagNames[url.Ip]
+}
+
+func addToPool(pool router.AddrPool, key string, index int) {
+	if _, ok := pool[key]; !ok {
+		pool[key] = roaring.NewBitmap()
+	}
+	pool[key].AddInt(index)
+}
+
+// findPool returns a copy of the pool @key, which is safe to modify
+func findPool(pool router.AddrPool, key string) *roaring.Bitmap {
+	if bitmap, ok := pool[key]; ok {
+		return bitmap.Clone()
+	}
+	return roaring.NewBitmap()
+}
+
+// pick picks the invokers whose indexes are in @bitmap
+func pick(invokers []protocol.Invoker, bitmap *roaring.Bitmap) []protocol.Invoker {
+	result := make([]protocol.Invoker, 0, bitmap.GetCardinality())
+	bitmap.Iterate(func(x uint32) bool {
+		if int(x) < len(invokers) {
+			result = append(result, invokers[x])
+		}
+		return true
+	})
+	return result
+}
+
+// isSameSnapshot checks whether the two invoker slices share the same backing array
+func isSameSnapshot(left, right []protocol.Invoker) bool {
+	return len(left) != 0 && len(left) == len(right) && &left[0] == &right[0]
+}
+
+// getTag gets the tag from the invocation attachment "dubbo.tag", or from the consumer url
+func getTag(url *common.URL, invocation protocol.Invocation) string {
+	if invocation != nil {
+		if tag := invocation.AttachmentsByKey(constant.Tagkey, ""); len(tag) != 0 {
+			return tag
+		}
+	}
+	if url == nil {
+		return ""
+	}
+	return url.GetParam(constant.Tagkey, "")
+}
+
+// isForceUseTag checks whether the providers must have the tag, by the invocation attachment "dubbo.force.tag"
+// or the consumer url.
+func isForceUseTag(url *common.URL, invocation protocol.Invocation) bool {
+	if invocation != nil {
+		if force := invocation.AttachmentsByKey(constant.ForceUseTag, ""); len(force) != 0 {
+			return strings.EqualFold(force, "true")
+		}
+	}
+	return url != nil && url.GetParamBool(constant.ForceUseTag, false)
+}
@@ -0,0 +1,189 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tag
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router/chain"
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const (
+	tagConsumerURL  = "consumer://192.168.2.1/com.foo.BarService?application=consumer-app"
+	tagProviderURL1 = "dubbo://192.168.1.1:20880/com.foo.BarService?application=provider-app"
+	tagProviderURL2 = "dubbo://192.168.1.2:20880/com.foo.BarService?application=provider-app&dubbo.tag=gray"
+	tagProviderURL3 = "dubbo://192.168.1.3:20880/com.foo.BarService?application=provider-app&dubbo.tag=blue"
+
+	tagRule = `force: false
+enabled: true
+key: provider-app
+tags:
+  - name: canary
+    addresses: [192.168.1.1:20880]
+  - name: empty
+    addresses: [192.168.9.9:20880]
+`
+	tagGrayRule = `force: false
+enabled: true
+key: provider-app
+tags:
+  - name: canary
+    addresses: [192.168.1.2:20880]
+  - name: empty
+    addresses: [192.168.9.9:20880]
+`
+)
+
+func buildInvokers(t *testing.T) []protocol.Invoker {
+	invokers := make([]protocol.Invoker, 0, 3)
+	for _, u := range []string{tagProviderURL1, tagProviderURL2, tagProviderURL3} {
+		url, err := common.NewURL(u)
+		assert.Nil(t, err)
+		invokers = append(invokers, protocol.NewBaseInvoker(url))
+	}
+	return invokers
+}
+
+func invocationWithTag(tag string, force string) protocol.Invocation {
+	attachments := map[string]interface{}{}
+	if len(tag) != 0 {
+		attachments[constant.Tagkey] = tag
+	}
+	if len(force) != 0 {
+		attachments[constant.ForceUseTag] = force
+	}
+	return invocation.NewRPCInvocation("getUser", nil, attachments)
+}
+
+func TestTagRouterRouteWithStaticTag(t *testing.T) {
+	config.GetEnvInstance().SetDynamicConfiguration(nil)
+	consumerURL, _ := common.NewURL(tagConsumerURL)
+	invokers := buildInvokers(t)
+	r := newTagRouter()
+
+	result := r.Route(invokers, consumerURL, invocationWithTag("gray", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[1], result[0])
+
+	// untagged providers are chosen without a tag
+	result = r.Route(invokers, consumerURL, invocationWithTag("", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+
+	// fall back to untagged providers unless forced
+	result = r.Route(invokers, consumerURL, invocationWithTag("red", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+	result = r.Route(invokers, consumerURL, invocationWithTag("red", "true"))
+	assert.Equal(t, 0, len(result))
+}
+
+func TestTagRouterRouteWithDynamicRule(t *testing.T) {
+	config.GetEnvInstance().SetDynamicConfiguration(nil)
+	consumerURL, _ := common.NewURL(tagConsumerURL)
+	invokers := buildInvokers(t)
+	r := newTagRouter()
+	r.Process(&config_center.ConfigChangeEvent{Value: tagRule, ConfigType: remoting.EventTypeAdd})
+	assert.True(t, r.ShouldPool())
+
+	result := r.Route(invokers, consumerURL, invocationWithTag("canary", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+
+	// static tag still works if the dynamic rule doesn't declare it
+	result = r.Route(invokers, consumerURL, invocationWithTag("blue", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[2], result[0])
+
+	// the dynamic tag has no alive provider, fall back to the providers neither bound by the dynamic rule nor
+	// tagged by url, the only untagged provider is bound to canary
+	result = r.Route(invokers, consumerURL, invocationWithTag("empty", ""))
+	assert.Equal(t, 0, len(result))
+
+	// providers bound by the dynamic rule are excluded without a tag
+	result = r.Route(invokers, consumerURL, invocationWithTag("", ""))
+	assert.Equal(t, 2, len(result))
+
+	r.Process(&config_center.ConfigChangeEvent{ConfigType: remoting.EventTypeDel})
+	result = r.Route(invokers, consumerURL, invocationWithTag("canary", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+}
+
+func TestTagRouterFallback(t *testing.T) {
+	config.GetEnvInstance().SetDynamicConfiguration(nil)
+	consumerURL, _ := common.NewURL(tagConsumerURL)
+	invokers := buildInvokers(t)
+	r := newTagRouter()
+	r.Process(&config_center.ConfigChangeEvent{Value: tagGrayRule, ConfigType: remoting.EventTypeAdd})
+
+	// the statically tagged provider 192.168.1.3 is not a fallback provider
+	result := r.Route(invokers, consumerURL, invocationWithTag("empty", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+	result = r.Route(invokers, consumerURL, invocationWithTag("red", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+	result = r.Route(invokers, consumerURL, invocationWithTag("red", "true"))
+	assert.Equal(t, 0, len(result))
+}
+
+func TestTagRouterPool(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: tagRule}
+	dc, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dc)
+	defer config.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	invokers := buildInvokers(t)
+	r := newTagRouter()
+	pool, meta := r.Pool(invokers)
+	assert.Equal(t, "provider-app", r.application)
+	assert.NotNil(t, meta.(*addrMetadata).rule)
+	assert.Equal(t, uint64(1), pool[staticPrefix].GetCardinality())
+	assert.Equal(t, uint64(1), pool[staticPrefix+"gray"].GetCardinality())
+	assert.Equal(t, uint64(1), pool[dynamicPrefix+"canary"].GetCardinality())
+	assert.Equal(t, uint64(1), pool[dynamicAllKey].GetCardinality())
+	assert.False(t, r.ShouldPool())
+
+	cache := chain.BuildCache(invokers)
+	cache.SetAddrPool(r.Name(), pool)
+	cache.SetAddrMeta(r.Name(), meta)
+	consumerURL, _ := common.NewURL(tagConsumerURL)
+	result := r.RouteWithCache(invokers, cache, consumerURL, invocationWithTag("canary", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[0], result[0])
+
+	// the invokers have been filtered by other routers, the pool doesn't fit any more
+	result = r.RouteWithCache(invokers[1:], cache, consumerURL, invocationWithTag("gray", ""))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, invokers[1], result[0])
+}
@@ -0,0 +1,110 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tag
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router"
+)
+
+// RouterRule is the tag router rule of a provider application which is written by dubbo admin into config center, e.g.
+//
+//	force: false
+//	enabled: true
+//	key: demo-provider
+//	tags:
+//	  - name: gray
+//	    addresses: [192.168.1.1:20880, 192.168.1.2:20880]
+//	  - name: blue
+//	    addresses: [192.168.1.3:20880]
+type RouterRule struct {
+	router.BaseRouterRule `yaml:",inline"`
+	Tags                  []Tag
+
+	addressToTagNames  map[string][]string
+	tagNameToAddresses map[string][]string
+}
+
+// Tag binds a tag name to the addresses of provider instances
+type Tag struct {
+	Name      string   `yaml:"name"`
+	Addresses []string `yaml:"addresses"`
+}
+
+// getRule parses the raw yaml rule into RouterRule, a rule is enabled and runtime unless it says no.
+func getRule(rawRule string) (*RouterRule, error) {
+	rule := &RouterRule{
+		BaseRouterRule: router.BaseRouterRule{
+			Enabled: true,
+			Runtime: true,
+		},
+	}
+	if err := yaml.Unmarshal([]byte(rawRule), rule); err != nil {
+		return nil, perrors.Wrapf(err, "failed to parse tag router rule: %s", rawRule)
+	}
+	rule.RawRule = rawRule
+	rule.Dynamic = true
+	rule.Valid = len(rule.Tags) != 0
+	rule.parseTags()
+	return rule, nil
+}
+
+// parseTags builds the index between tag names and addresses
+func (t *RouterRule) parseTags() {
+	t.addressToTagNames = make(map[string][]string, 2*len(t.Tags))
+	t.tagNameToAddresses = make(map[string][]string, len(t.Tags))
+	for _, tag := range t.Tags {
+		for _, address := range tag.Addresses {
+			t.addressToTagNames[address] = append(t.addressToTagNames[address], tag.Name)
+		}
+		t.tagNameToAddresses[tag.Name] = tag.Addresses
+	}
+}
+
+// getAddresses returns all addresses which are bound to a tag
+func (t *RouterRule) getAddresses() []string {
+	addresses := make([]string, 0, len(t.addressToTagNames))
+	for address := range t.addressToTagNames {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// getTagNames returns all tag names of the rule
+func (t *RouterRule) getTagNames() []string {
+	tagNames := make([]string, 0, len(t.tagNameToAddresses))
+	for tagName := range t.tagNameToAddresses {
+		tagNames = append(tagNames, tagName)
+	}
+	return tagNames
+}
+
+// hasTag checks whether the rule declares @tag
+func (t *RouterRule) hasTag(tag string) bool {
+	_, ok := t.tagNameToAddresses[tag]
+	return ok
+}
+
+// getTagNameToAddresses returns the addresses which are bound to @tag
+func (t *RouterRule) getTagNameToAddresses(tag string) []string {
+	return t.tagNameToAddresses[tag]
+}
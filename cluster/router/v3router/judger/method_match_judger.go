@@ -17,6 +17,10 @@
 
 package judger
 
+import (
+	"reflect"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -57,9 +61,10 @@ func (mmj *MethodMatchJudger) Judge(invocation protocol.Invocation) bool {
 				if !newListStringMatchJudger(v.StrValue).Judge(value.String()) {
 					return false
 				}
-			case "float", "int":
+			case "float", "float32", "float64", "int", "int8", "int16", "int32", "int64",
+				"uint", "uint8", "uint16", "uint32", "uint64":
 				// todo now numbers Must not be zero, else it will ignore this match
-				if !newListDoubleMatchJudger(v.NumValue).Judge(value.Float()) {
+				if !newListDoubleMatchJudger(v.NumValue).Judge(numberOf(value)) {
 					return false
 				}
 			case "bool":
@@ -81,6 +86,18 @@ func (mmj *MethodMatchJudger) Judge(invocation protocol.Invocation) bool {
 	return true
 }
 
+// numberOf converts the value of any int, uint or float kind to float64
+func numberOf(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	default:
+		return value.Float()
+	}
+}
+
 // nolint
 func NewMethodMatchJudger(matchConf *config.DubboMethodMatch) *MethodMatchJudger {
 	return &MethodMatchJudger{
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package judger
+
+import (
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestMethodMatchJudger_JudgeNumber(t *testing.T) {
+	newJudger := func(argType string) *MethodMatchJudger {
+		return NewMethodMatchJudger(&config.DubboMethodMatch{
+			Args: []*config.DubboMethodArg{{
+				Index:    1,
+				Type:     argType,
+				NumValue: &config.ListDoubleMatch{Oneof: []*config.DoubleMatch{{Exact: 7}}},
+			}},
+		})
+	}
+	for _, arg := range []interface{}{int(7), int8(7), int64(7), uint(7), uint16(7), uint64(7), float32(7), float64(7)} {
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithParameterValues([]reflect.Value{reflect.ValueOf(arg)}))
+		argType := reflect.TypeOf(arg).String()
+		assert.True(t, newJudger(argType).Judge(inv), argType)
+	}
+
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithParameterValues([]reflect.Value{reflect.ValueOf(uint32(8))}))
+	assert.False(t, newJudger("uint32").Judge(inv))
+}
@@ -21,4 +21,5 @@ package constant
 const (
 	FAILOVER_CLUSTER_NAME  = "failover"
 	ZONEAWARE_CLUSTER_NAME = "zoneAware"
+	MERGEABLE_CLUSTER_NAME = "mergeable"
 )
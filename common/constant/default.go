@@ -24,6 +24,7 @@ const (
 	OVERRIDE_PROTOCOL = "override"
 	EMPTY_PROTOCOL    = "empty"
 	ROUTER_PROTOCOL   = "router"
+	INJVM_PROTOCOL    = "injvm"
 )
 
 const (
@@ -51,8 +52,8 @@ const (
 const (
 	DEFAULT_KEY               = "default"
 	PREFIX_DEFAULT_KEY        = "default."
-	DEFAULT_SERVICE_FILTERS   = "echo,token,accesslog,tps,generic_service,execute,pshutdown"
-	DEFAULT_REFERENCE_FILTERS = "cshutdown"
+	DEFAULT_SERVICE_FILTERS   = "echo,token,accesslog,tps,ptimeout,generic_service,validation,execute,pshutdown"
+	DEFAULT_REFERENCE_FILTERS = "cshutdown,validation"
 	GENERIC_REFERENCE_FILTERS = "generic"
 	GENERIC                   = "$invoke"
 	ECHO                      = "$echo"
@@ -62,6 +63,7 @@ const (
 	ANY_VALUE           = "*"
 	ANYHOST_VALUE       = "0.0.0.0"
 	LOCAL_HOST_VALUE    = "192.168.1.1"
+	LOCALHOST_VALUE     = "127.0.0.1"
 	REMOVE_VALUE_PREFIX = "-"
 )
 
@@ -53,6 +53,8 @@ const (
 	SSL_ENABLED_KEY = "ssl-enabled"
 	// PARAMS_TYPE_Key key used in pass through invoker factory, to define param type
 	PARAMS_TYPE_Key = "parameter-type-names"
+	// INJVM_COPY_KEY defines whether injvm invocation deep copies the arguments and the result
+	INJVM_COPY_KEY = "injvm.copy"
 )
 
 const (
@@ -100,6 +102,35 @@ const (
 	DUBBOGO_CTX_KEY = DubboCtxKey("dubbogo-ctx")
 )
 
+const (
+	MOCK_KEY           = "mock"
+	MOCK_FORCE_VALUE   = "force"
+	MOCK_FORCE_PREFIX  = "force:"
+	MOCK_FAIL_PREFIX   = "fail:"
+	MOCK_RETURN_PREFIX = "return"
+	MOCK_THROW_PREFIX  = "throw"
+	MOCK_NULL_VALUE    = "null"
+	MOCK_EMPTY_VALUE   = "empty"
+)
+
+// the tls keys start with "." so that the local file paths are not registered to the registry
+const (
+	TLS_CERT_FILE_KEY     = ".tls.cert.file"
+	TLS_KEY_FILE_KEY      = ".tls.key.file"
+	TLS_CA_FILE_KEY       = ".tls.ca.file"
+	TLS_CLIENT_AUTH_KEY   = ".tls.client.auth"
+	TLS_SERVER_NAME_KEY   = ".tls.server.name"
+	TLS_CIPHER_SUITES_KEY = ".tls.cipher.suites"
+	TLS_MIN_VERSION_KEY   = ".tls.min.version"
+)
+
+const (
+	MERGER_KEY             = "merger"
+	MERGER_FAIL_POLICY_KEY = "merger.fail"
+	MERGER_FAILSAFE_POLICY = "failsafe"
+	MERGER_FAILFAST_POLICY = "failfast"
+)
+
 const (
 	REGISTRY_KEY         = "registry"
 	REGISTRY_PROTOCOL    = "registry"
@@ -222,8 +253,14 @@ const (
 	// TagRouterRuleSuffix Specify tag router suffix
 	TagRouterRuleSuffix  = ".tag-router"
 	RemoteApplicationKey = "remote.application"
+	// ConditionRouterName Specify the name of ConditionRouter
+	ConditionRouterName = "condition"
 	// ConditionRouterRuleSuffix Specify condition router suffix
 	ConditionRouterRuleSuffix = ".condition-router"
+	// RouterForceKey defines if the route result may be empty
+	RouterForceKey = "force"
+	// RouterPriorityKey defines the priority of a router
+	RouterPriorityKey = "priority"
 
 	// RouterScope Scope key in router module
 	RouterScope = "scope"
@@ -242,6 +279,52 @@ const (
 	AttachmentKey = DubboCtxKey("attachment")
 )
 
+const (
+	// name of consumer p2c filter, which collects the statistics for p2c load balance
+	CONSUMER_P2C_FILTER = "p2c_consumer"
+	// name of provider p2c filter, which reports the server load to consumer
+	PROVIDER_P2C_FILTER = "p2c_provider"
+	// key of the server load in response attachments
+	P2C_SERVER_LOAD_KEY = "dubbo.p2c.load"
+)
+
+const (
+	// name of consumer otel filter, which creates the client spans of OpenTelemetry
+	CONSUMER_OTEL_FILTER = "otel_consumer"
+	// name of provider otel filter, which creates the server spans of OpenTelemetry
+	PROVIDER_OTEL_FILTER = "otel_provider"
+	// keys of W3C trace context and baggage in attachments
+	TRACEPARENT_KEY = "traceparent"
+	TRACESTATE_KEY  = "tracestate"
+	BAGGAGE_KEY     = "baggage"
+)
+
+const (
+	// name of provider timeout filter
+	PROVIDER_TIMEOUT_FILTER = "ptimeout"
+	// key of the remaining timeout in milliseconds of the invocation in attachments
+	TIMEOUT_ATTACHMENT_KEY = "_TO"
+)
+
+// PropagatedAttachmentKeys are the attachments which are carried by all protocols,
+// including the trace context and the remaining timeout
+var PropagatedAttachmentKeys = []string{TRACEPARENT_KEY, TRACESTATE_KEY, BAGGAGE_KEY, TIMEOUT_ATTACHMENT_KEY}
+
+const (
+	// name of adaptive concurrency limit filter
+	ADAPTIVE_LIMIT_FILTER = "adaptive_limit"
+	// key of the concurrency limiter, gradient2 by default
+	ADAPTIVE_LIMITER_KEY = "adaptive.limiter"
+	// key of the initial concurrency limit
+	ADAPTIVE_LIMIT_INITIAL_KEY = "adaptive.limit.initial"
+	// key of the minimum concurrency limit
+	ADAPTIVE_LIMIT_MIN_KEY = "adaptive.limit.min"
+	// key of the maximum concurrency limit
+	ADAPTIVE_LIMIT_MAX_KEY = "adaptive.limit.max"
+	// key of the rejected execution handler
+	ADAPTIVE_REJECTED_EXECUTION_HANDLER_KEY = "adaptive.limit.rejected.handler"
+)
+
 const (
 	// name of consumer sign filter
 	CONSUMER_SIGN_FILTER = "sign"
@@ -273,6 +356,144 @@ const (
 	ACCESS_KEY_ID_KEY = ".accessKeyId"
 	// key of secret access key
 	SECRET_ACCESS_KEY_KEY = ".secretAccessKey"
+	// name of the storage which loads the access keys from a local file
+	FILE_ACCESS_KEY_STORAGE = "filestorage"
+	// name of the storage which loads the access keys from the config center
+	CONFIG_CENTER_ACCESS_KEY_STORAGE = "configcenterstorage"
+	// key of the path of access key file
+	ACCESS_KEY_FILE_KEY = "accessKey.file"
+	// key of the group of access keys in the config center
+	ACCESS_KEY_GROUP_KEY = "accessKey.group"
+	// AccessKeyRuleSuffix is the suffix of the key of access keys in the config center
+	AccessKeyRuleSuffix = ".accesskeys"
+)
+
+const (
+	// name of jwt authenticator
+	JWT_AUTHENTICATOR = "jwt"
+	// key of the name of attachment which carries the jwt token
+	JWT_ATTACHMENT_KEY = "jwt.attachment"
+	// name of the default attachment which carries the jwt token
+	DEFAULT_JWT_ATTACHMENT = "authorization"
+	// key of the static jwt token of consumer
+	JWT_TOKEN_KEY = "jwt.token"
+	// key of the expected issuer of jwt token
+	JWT_ISSUER_KEY = "jwt.issuer"
+	// key of the expected audience of jwt token
+	JWT_AUDIENCE_KEY = "jwt.audience"
+	// key of the path of JWKS file
+	JWT_JWKS_FILE_KEY = "jwt.jwks.file"
+	// key of the path of PEM file
+	JWT_PEM_FILE_KEY = "jwt.pem.file"
+	// key of the allowed clock skew when checking the expiry of jwt token
+	JWT_CLOCK_SKEW_KEY = "jwt.clock.skew"
+	// key of the required scopes, comma separated
+	JWT_SCOPES_KEY = "jwt.scopes"
+	// JWTClaimsKey is the key of verified jwt claims in context
+	JWTClaimsKey = DubboCtxKey("jwt.claims")
+	// AuthApplicationKey is the key of the consumer application verified by the provider authenticator in context
+	AuthApplicationKey = DubboCtxKey("auth.application")
+	// RemoteAddrKey is the key of the peer address of the connection in context, it's set by the server transport
+	RemoteAddrKey = DubboCtxKey("remote.addr")
+)
+
+const (
+	// name of rbac filter
+	RBAC_FILTER = "rbac"
+	// key of the path of rbac rule file
+	RBAC_RULE_FILE_KEY = "rbac.file"
+	// key of the group of rbac rule in the config center
+	RBAC_RULE_GROUP_KEY = "rbac.group"
+	// RBACRuleSuffix is the suffix of the key of rbac rule in the config center
+	RBACRuleSuffix = ".rbac"
+)
+
+const (
+	// name of cache filter
+	CACHE_FILTER = "cache"
+	// key of the name of cache factory
+	CACHE_KEY = "cache"
+	// key of the max number of results in lru cache
+	CACHE_SIZE_KEY = "cache.size"
+	// key of the seconds the results live in expiring cache
+	CACHE_SECONDS_KEY = "cache.seconds"
+	// key of the max bytes of results in sized cache
+	CACHE_BYTES_KEY = "cache.bytes"
+)
+
+const (
+	// name of validation filter
+	VALIDATION_FILTER = "validation"
+	// key of whether to validate the arguments
+	VALIDATION_KEY = "validation"
+)
+
+const (
+	// name of circuit breaker filter
+	CIRCUIT_BREAKER_FILTER = "circuitbreaker"
+	// key of the scope of circuit breaker, invoker or method
+	CIRCUIT_BREAKER_SCOPE_KEY = "circuitbreaker.scope"
+	// key of the type of sliding window, count or time
+	CIRCUIT_BREAKER_WINDOW_TYPE_KEY = "circuitbreaker.window.type"
+	// key of the size of sliding window, the number of calls for count window or the seconds for time window
+	CIRCUIT_BREAKER_WINDOW_SIZE_KEY = "circuitbreaker.window.size"
+	// key of the minimum number of calls in the window before the rates are calculated
+	CIRCUIT_BREAKER_MINIMUM_CALLS_KEY = "circuitbreaker.minimum.calls"
+	// key of the error rate threshold in percentage
+	CIRCUIT_BREAKER_ERROR_RATE_KEY = "circuitbreaker.error.rate"
+	// key of the slow call rate threshold in percentage
+	CIRCUIT_BREAKER_SLOW_RATE_KEY = "circuitbreaker.slow.rate"
+	// key of the duration over which a call is slow
+	CIRCUIT_BREAKER_SLOW_DURATION_KEY = "circuitbreaker.slow.duration"
+	// key of the duration the breaker stays open before half-open
+	CIRCUIT_BREAKER_OPEN_DURATION_KEY = "circuitbreaker.open.duration"
+	// key of the number of calls permitted in half-open state
+	CIRCUIT_BREAKER_HALF_OPEN_CALLS_KEY = "circuitbreaker.halfopen.calls"
+)
+
+const (
+	// key of the ratio of retries to requests permitted by the retry budget of a service, 0 disables the budget
+	RETRY_BUDGET_RATIO_KEY = "retry.budget.ratio"
+	// key of the minimum retries per second permitted by the retry budget of a service
+	RETRY_BUDGET_MIN_KEY = "retry.budget.min"
+	// key of whether to send hedged requests in failover cluster
+	HEDGING_KEY = "hedging"
+	// key of the latency percentile after which a hedged request is sent
+	HEDGING_PERCENTILE_KEY = "hedging.percentile"
+	// key of the max number of hedged requests of an invocation
+	HEDGING_MAX_KEY = "hedging.max"
+)
+
+const (
+	// key of the name of compressor which compresses the message bodies sent to the peer
+	COMPRESSION_KEY = "compression"
+	// key of the min size in bytes of the message bodies to compress
+	COMPRESSION_THRESHOLD_KEY = "compression.threshold"
+	// key of the names of compressors accepted by the peer in attachments
+	ACCEPT_COMPRESSION_KEY = "accept-compression"
+	// the default min size in bytes of the message bodies to compress
+	DEFAULT_COMPRESSION_THRESHOLD = 1024
+
+	GZIP_COMPRESSION   = "gzip"
+	ZSTD_COMPRESSION   = "zstd"
+	SNAPPY_COMPRESSION = "snappy"
+)
+
+const (
+	// key of the io.Reader which is sent in chunks after the request, it is an attachment or attribute of
+	// invocation at consumer side, and an attribute of invocation at provider side
+	STREAM_KEY = "stream"
+	// StreamKey is the key of the io.Reader of request stream in the context of provider
+	StreamKey = DubboCtxKey(STREAM_KEY)
+	// key of the protocol.StreamState in the attributes of invocation which opens a streaming call
+	STREAM_STATE_KEY = "stream.state"
+)
+
+const (
+	// keys of the response attachments which carry the code, message and details of protocol.RPCError
+	RPC_ERROR_CODE_KEY    = "rpc.error.code"
+	RPC_ERROR_MESSAGE_KEY = "rpc.error.message"
+	RPC_ERROR_DETAILS_KEY = "rpc.error.details"
 )
 
 // metadata report
@@ -19,11 +19,16 @@ package constant
 
 const (
 	S_Hessian2 byte = 2
+	S_FastJson byte = 6
 	S_Proto    byte = 21
+	S_Msgpack  byte = 27
 )
 
 const (
 	HESSIAN2_SERIALIZATION = "hessian2"
 	PROTOBUF_SERIALIZATION = "protobuf"
 	MSGPACK_SERIALIZATION  = "msgpack"
+	FASTJSON_SERIALIZATION = "fastjson"
+	// PROTOBUF_JSON_SERIALIZATION is the serialization of id S_Proto, the protobuf messages are written in json
+	PROTOBUF_JSON_SERIALIZATION = "protobuf-json"
 )
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"sort"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting/compression"
+)
+
+var compressors = make(map[string]func() compression.Compressor)
+
+// SetCompressor sets the compressor extension with @name
+// For example: gzip/zstd/snappy
+func SetCompressor(name string, fcn func() compression.Compressor) {
+	compressors[name] = fcn
+}
+
+// GetCompressor finds the compressor extension with @name
+func GetCompressor(name string) (compression.Compressor, bool) {
+	if compressors[name] == nil {
+		return nil, false
+	}
+	return compressors[name](), true
+}
+
+// GetCompressorNames gets the sorted names of all compressor extensions
+func GetCompressorNames() []string {
+	names := make([]string, 0, len(compressors))
+	for name := range compressors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
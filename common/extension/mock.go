@@ -0,0 +1,47 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+var (
+	mocks     = make(map[string]common.RPCService)
+	mocksLock sync.RWMutex
+)
+
+// SetMock sets the fallback implementation @svc of the mock cluster with @name.
+// The name is the interface name by default, or the value configured by mock=<name>.
+func SetMock(name string, svc common.RPCService) {
+	mocksLock.Lock()
+	defer mocksLock.Unlock()
+	mocks[name] = svc
+}
+
+// GetMock finds the fallback implementation with @name
+func GetMock(name string) (common.RPCService, bool) {
+	mocksLock.RLock()
+	defer mocksLock.RUnlock()
+	svc, ok := mocks[name]
+	return svc, ok
+}
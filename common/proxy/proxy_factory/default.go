@@ -134,9 +134,10 @@ func (pi *ProxyInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 		}
 	}
 
-	// prepare replyv
+	// prepare replyv, it's the last argument unless all arguments are carried by the invocation, e.g. the streaming
+	// calls of triple which pass the stream as the last argument
 	var replyv reflect.Value
-	if method.ReplyType() == nil && len(method.ArgsType()) > 0 {
+	if method.ReplyType() == nil && len(method.ArgsType()) > 0 && len(in) < method.Method().Type.NumIn() {
 		replyv = reflect.New(method.ArgsType()[len(method.ArgsType())-1].Elem())
 		in = append(in, replyv)
 	}
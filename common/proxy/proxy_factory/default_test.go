@@ -18,6 +18,7 @@
 package proxy_factory
 
 import (
+	"context"
 	"fmt"
 	"testing"
 )
@@ -29,6 +30,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 func TestGetProxy(t *testing.T) {
@@ -58,3 +60,41 @@ func TestGetInvoker(t *testing.T) {
 	invoker := proxyFactory.GetInvoker(url)
 	assert.True(t, invoker.IsAvailable())
 }
+
+type EchoSender interface {
+	Send(string)
+}
+
+type echoSender struct {
+	sent []string
+}
+
+func (es *echoSender) Send(msg string) {
+	es.sent = append(es.sent, msg)
+}
+
+type TestStreamProvider struct{}
+
+func (p *TestStreamProvider) Echo(msg string, sender EchoSender) error {
+	sender.Send(msg)
+	sender.Send(msg)
+	return nil
+}
+
+func (p *TestStreamProvider) Reference() string {
+	return "TestStreamProvider"
+}
+
+func TestProxyInvokerWithStreamArgument(t *testing.T) {
+	url, err := common.NewURL("tri://127.0.0.1:20000/com.test.TestStreamProvider?interface=com.test.TestStreamProvider")
+	assert.NoError(t, err)
+	_, err = common.ServiceMap.Register("com.test.TestStreamProvider", url.Protocol, "", "", &TestStreamProvider{})
+	assert.NoError(t, err)
+	defer common.ServiceMap.UnRegister("com.test.TestStreamProvider", url.Protocol, url.ServiceKey())
+
+	sender := &echoSender{}
+	invoker := NewDefaultProxyFactory().GetInvoker(url)
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocation("Echo", []interface{}{"hello", sender}, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, []string{"hello", "hello"}, sender.sent)
+}
@@ -40,6 +40,13 @@ type MethodConfig struct {
 	ExecuteLimitRejectedHandler string `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Sticky                      bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
 	RequestTimeout              string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
+	Mock                        string `yaml:"mock"  json:"mock,omitempty" property:"mock"`
+	JWTScopes                   string `yaml:"jwt.scopes" json:"jwt.scopes,omitempty" property:"jwt.scopes"`
+	Cache                       string `yaml:"cache" json:"cache,omitempty" property:"cache"`
+	CacheSize                   string `yaml:"cache.size" json:"cache.size,omitempty" property:"cache.size"`
+	CacheSeconds                string `yaml:"cache.seconds" json:"cache.seconds,omitempty" property:"cache.seconds"`
+	CacheBytes                  string `yaml:"cache.bytes" json:"cache.bytes,omitempty" property:"cache.bytes"`
+	Validation                  string `yaml:"validation" json:"validation,omitempty" property:"validation"`
 }
 
 // nolint
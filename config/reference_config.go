@@ -30,6 +30,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster_impl"
 	"dubbo.apache.org/dubbo-go/v3/cluster/directory"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
@@ -61,10 +62,14 @@ type ReferenceConfig struct {
 	Params         map[string]string `yaml:"params"  json:"params,omitempty" property:"params"`
 	invoker        protocol.Invoker
 	urls           []*common.URL
-	Generic        bool   `yaml:"generic"  json:"generic,omitempty" property:"generic"`
-	Sticky         bool   `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
-	RequestTimeout string `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
-	ForceTag       bool   `yaml:"force.tag"  json:"force.tag,omitempty" property:"force.tag"`
+	Generic        bool       `yaml:"generic"  json:"generic,omitempty" property:"generic"`
+	Sticky         bool       `yaml:"sticky"   json:"sticky,omitempty" property:"sticky"`
+	RequestTimeout string     `yaml:"timeout"  json:"timeout,omitempty" property:"timeout"`
+	ForceTag       bool       `yaml:"force.tag"  json:"force.tag,omitempty" property:"force.tag"`
+	Injvm          bool       `yaml:"injvm"  json:"injvm,omitempty" property:"injvm"`
+	Mock           string     `yaml:"mock"  json:"mock,omitempty" property:"mock"`
+	Validation     string     `yaml:"validation"  json:"validation,omitempty" property:"validation"`
+	TLS            *TlsConfig `yaml:"tls"  json:"tls,omitempty" property:"tls"`
 }
 
 // nolint
@@ -101,6 +106,12 @@ func (c *ReferenceConfig) Refer(_ interface{}) {
 		cfgURL.AddParam(constant.ForceUseTag, "true")
 	}
 	c.postProcessConfig(cfgURL)
+	if c.Injvm || c.Protocol == constant.INJVM_PROTOCOL {
+		// refer the service exported in the same process, the reference filters still work
+		c.invoker = extension.GetProtocol(protocolwrapper.FILTER).Refer(c.getInjvmURL(cfgURL))
+		c.createProxy(cfgURL)
+		return
+	}
 	if c.URL != "" {
 		// 1. user specified URL, could be peer-to-peer address, or register center's address.
 		urlStrings := gxstrings.RegSplit(c.URL, "\\s*[;]+\\s*")
@@ -153,6 +164,9 @@ func (c *ReferenceConfig) Refer(_ interface{}) {
 			}
 
 			cluster := extension.GetCluster(hitClu)
+			if cluster_impl.HasMock(cfgURL) {
+				cluster = cluster_impl.NewMockClusterWrapper(cluster)
+			}
 			// If 'zone-aware' policy select, the invoker wrap sequence would be:
 			// ZoneAwareClusterInvoker(StaticDirectory) ->
 			// FailoverClusterInvoker(RegistryDirectory, routing happens here) -> Invoker
@@ -191,11 +205,20 @@ func (c *ReferenceConfig) Refer(_ interface{}) {
 		}
 
 		cluster := extension.GetCluster(hitClu)
+		// the invokers of registries have been wrapped by the mock cluster invoker already
+		if regURL == nil && cluster_impl.HasMock(cfgURL) {
+			cluster = cluster_impl.NewMockClusterWrapper(cluster)
+		}
 		// If 'zone-aware' policy select, the invoker wrap sequence would be:
 		// ZoneAwareClusterInvoker(StaticDirectory) ->
 		// FailoverClusterInvoker(RegistryDirectory, routing happens here) -> Invoker
 		c.invoker = cluster.Join(directory.NewStaticDirectory(invokers))
 	}
+	c.createProxy(cfgURL)
+}
+
+// createProxy publishes the consumer metadata and creates the proxy of the invoker
+func (c *ReferenceConfig) createProxy(cfgURL *common.URL) {
 	// publish consumer metadata
 	publishConsumerDefinition(cfgURL)
 	// create proxy
@@ -250,6 +273,17 @@ func (c *ReferenceConfig) getURLMap() url.Values {
 	// getty invoke async or sync
 	urlMap.Set(constant.ASYNC_KEY, strconv.FormatBool(c.Async))
 	urlMap.Set(constant.STICKY_KEY, strconv.FormatBool(c.Sticky))
+	if len(c.Mock) != 0 {
+		urlMap.Set(constant.MOCK_KEY, c.Mock)
+	}
+	if len(c.Validation) != 0 {
+		urlMap.Set(constant.VALIDATION_KEY, c.Validation)
+	}
+	if c.TLS != nil {
+		for k, v := range c.TLS.urlParams() {
+			urlMap[k] = v
+		}
+	}
 
 	// application info
 	urlMap.Set(constant.APPLICATION_KEY, consumerConfig.ApplicationConfig.Name)
@@ -274,11 +308,31 @@ func (c *ReferenceConfig) getURLMap() url.Values {
 		if len(v.RequestTimeout) != 0 {
 			urlMap.Set("methods."+v.Name+"."+constant.TIMEOUT_KEY, v.RequestTimeout)
 		}
+		if len(v.Mock) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.MOCK_KEY, v.Mock)
+		}
+		if len(v.Cache) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.CACHE_KEY, v.Cache)
+			urlMap.Set("methods."+v.Name+"."+constant.CACHE_SIZE_KEY, v.CacheSize)
+			urlMap.Set("methods."+v.Name+"."+constant.CACHE_SECONDS_KEY, v.CacheSeconds)
+			urlMap.Set("methods."+v.Name+"."+constant.CACHE_BYTES_KEY, v.CacheBytes)
+		}
+		if len(v.Validation) != 0 {
+			urlMap.Set("methods."+v.Name+"."+constant.VALIDATION_KEY, v.Validation)
+		}
 	}
 
 	return urlMap
 }
 
+// getInjvmURL gets the url which refers the service exported by injvm protocol
+func (c *ReferenceConfig) getInjvmURL(cfgURL *common.URL) *common.URL {
+	injvmURL := cfgURL.Clone()
+	injvmURL.Protocol = constant.INJVM_PROTOCOL
+	injvmURL.Ip = constant.LOCALHOST_VALUE
+	return injvmURL
+}
+
 // GenericLoad ...
 func (c *ReferenceConfig) GenericLoad(id string) {
 	genericService := NewGenericService(c.id)
@@ -19,6 +19,7 @@ package config
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -328,6 +329,24 @@ func TestSticky(t *testing.T) {
 	assert.Equal(t, "true", method1StickKey)
 }
 
+func TestReferValidation(t *testing.T) {
+	doInitConsumer()
+	extension.SetProtocol("dubbo", GetProtocol)
+	extension.SetProtocol("registry", GetProtocol)
+	mockFilter()
+	reference := consumerConfig.References["MockService"]
+	reference.URL = "dubbo://127.0.0.1:20000;registry://127.0.0.2:20000"
+	reference.Validation = "true"
+	reference.Methods[1].Validation = "false"
+
+	reference.Refer(nil)
+	url := reference.invoker.GetURL()
+	assert.Contains(t, strings.Split(url.GetParam(constant.REFERENCE_FILTER_KEY, ""), ","), constant.VALIDATION_FILTER)
+	assert.Equal(t, "true", url.GetMethodParam(reference.Methods[0].Name, constant.VALIDATION_KEY, url.GetParam(constant.VALIDATION_KEY, "")))
+	assert.Equal(t, "false", url.GetMethodParam(reference.Methods[1].Name, constant.VALIDATION_KEY, url.GetParam(constant.VALIDATION_KEY, "")))
+	consumerConfig = nil
+}
+
 func GetProtocol() protocol.Protocol {
 	if regProtocol != nil {
 		return regProtocol
@@ -388,6 +407,9 @@ func mockFilter() {
 	extension.SetFilter(constant.CONSUMER_SHUTDOWN_FILTER, func() filter.Filter {
 		return consumerFiler
 	})
+	extension.SetFilter(constant.VALIDATION_FILTER, func() filter.Filter {
+		return consumerFiler
+	})
 }
 
 type mockShutdownFilter struct {
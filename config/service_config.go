@@ -39,6 +39,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
 )
@@ -70,8 +71,11 @@ type ServiceConfig struct {
 	ExecuteLimitRejectedHandler string            `yaml:"execute.limit.rejected.handler" json:"execute.limit.rejected.handler,omitempty" property:"execute.limit.rejected.handler"`
 	Auth                        string            `yaml:"auth" json:"auth,omitempty" property:"auth"`
 	ParamSign                   string            `yaml:"param.sign" json:"param.sign,omitempty" property:"param.sign"`
+	Validation                  string            `yaml:"validation" json:"validation,omitempty" property:"validation"`
+	Timeout                     string            `yaml:"timeout" json:"timeout,omitempty" property:"timeout"`
 	Tag                         string            `yaml:"tag" json:"tag,omitempty" property:"tag"`
 	GrpcMaxMessageSize          int               `default:"4" yaml:"max_message_size" json:"max_message_size,omitempty"`
+	Injvm                       bool              `yaml:"injvm" json:"injvm,omitempty" property:"injvm"`
 
 	Protocols     map[string]*ProtocolConfig
 	unexported    *atomic.Bool
@@ -160,6 +164,13 @@ func (c *ServiceConfig) Export() error {
 
 	regUrls := loadRegistries(c.Registry, providerConfig.Registries, common.PROVIDER)
 	urlMap := c.getUrlMap()
+	proxyFactory := extension.GetProxyFactory(providerConfig.ProxyFactory)
+	if c.Injvm {
+		if err := c.exportInjvm(urlMap, proxyFactory); err != nil {
+			return err
+		}
+	}
+
 	protocolConfigs := loadProtocol(c.Protocol, c.Protocols)
 	if len(protocolConfigs) == 0 {
 		logger.Warnf("The service %v's '%v' protocols don't has right protocolConfigs", c.InterfaceName, c.Protocol)
@@ -168,7 +179,6 @@ func (c *ServiceConfig) Export() error {
 
 	ports := getRandomPort(protocolConfigs)
 	nextPort := ports.Front()
-	proxyFactory := extension.GetProxyFactory(providerConfig.ProxyFactory)
 	for _, proto := range protocolConfigs {
 		// registry the service reflect
 		methods, err := common.ServiceMap.Register(c.InterfaceName, proto.Name, c.Group, c.Version, c.rpcService)
@@ -198,6 +208,11 @@ func (c *ServiceConfig) Export() error {
 		if len(c.Tag) > 0 {
 			ivkURL.AddParam(constant.Tagkey, c.Tag)
 		}
+		if proto.TLS != nil {
+			for k, v := range proto.TLS.urlParams() {
+				ivkURL.SetParam(k, v[0])
+			}
+		}
 
 		// post process the URL to be exported
 		c.postProcessConfig(ivkURL)
@@ -244,6 +259,36 @@ func (c *ServiceConfig) Export() error {
 	return nil
 }
 
+// exportInjvm exports the service into injvm protocol, so the references in the same process can call it directly
+func (c *ServiceConfig) exportInjvm(urlMap url.Values, proxyFactory proxy.ProxyFactory) error {
+	methods, err := common.ServiceMap.Register(c.InterfaceName, constant.INJVM_PROTOCOL, c.Group, c.Version, c.rpcService)
+	if err != nil {
+		formatErr := perrors.Errorf("The service %v export the protocol %v error! Error message is %v.",
+			c.InterfaceName, constant.INJVM_PROTOCOL, err.Error())
+		logger.Errorf(formatErr.Error())
+		return formatErr
+	}
+
+	injvmURL := common.NewURLWithOptions(
+		common.WithPath(c.InterfaceName),
+		common.WithProtocol(constant.INJVM_PROTOCOL),
+		common.WithIp(constant.LOCALHOST_VALUE),
+		common.WithPort("0"),
+		common.WithParams(urlMap),
+		common.WithParamsValue(constant.BEAN_NAME_KEY, c.id),
+		common.WithMethods(strings.Split(methods, ",")),
+		common.WithToken(c.Token),
+	)
+	exporter := extension.GetProtocol(protocolwrapper.FILTER).Export(proxyFactory.GetInvoker(injvmURL))
+	if exporter == nil {
+		return perrors.New(fmt.Sprintf("Injvm protocol new exporter error, url is {%v}", injvmURL))
+	}
+	c.exportersLock.Lock()
+	c.exporters = append(c.exporters, exporter)
+	c.exportersLock.Unlock()
+	return nil
+}
+
 // Unexport will call unexport of all exporters service config exported
 func (c *ServiceConfig) Unexport() {
 	if !c.exported.Load() {
@@ -320,6 +365,12 @@ func (c *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.SERVICE_AUTH_KEY, c.Auth)
 	urlMap.Set(constant.PARAMETER_SIGNATURE_ENABLE_KEY, c.ParamSign)
 
+	// validation filter
+	urlMap.Set(constant.VALIDATION_KEY, c.Validation)
+
+	// provider timeout filter
+	urlMap.Set(constant.TIMEOUT_KEY, c.Timeout)
+
 	// whether to export or not
 	urlMap.Set(constant.EXPORT_KEY, strconv.FormatBool(c.export))
 
@@ -335,6 +386,17 @@ func (c *ServiceConfig) getUrlMap() url.Values {
 
 		urlMap.Set(constant.EXECUTE_LIMIT_KEY, v.ExecuteLimit)
 		urlMap.Set(constant.EXECUTE_REJECTED_EXECUTION_HANDLER_KEY, v.ExecuteLimitRejectedHandler)
+
+		urlMap.Set(prefix+constant.JWT_SCOPES_KEY, v.JWTScopes)
+
+		urlMap.Set(prefix+constant.CACHE_KEY, v.Cache)
+		urlMap.Set(prefix+constant.CACHE_SIZE_KEY, v.CacheSize)
+		urlMap.Set(prefix+constant.CACHE_SECONDS_KEY, v.CacheSeconds)
+		urlMap.Set(prefix+constant.CACHE_BYTES_KEY, v.CacheBytes)
+
+		urlMap.Set(prefix+constant.VALIDATION_KEY, v.Validation)
+
+		urlMap.Set(prefix+constant.TIMEOUT_KEY, v.RequestTimeout)
 	}
 
 	return urlMap
@@ -0,0 +1,364 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/apache/dubbo-getty"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// tlsReloadInterval is the minimum interval to check whether the certificate files are rotated
+var tlsReloadInterval = 10 * time.Second
+
+// TlsConfig is the tls configuration of protocol and reference.
+// The certificate, private key and CA are reloaded when the files are rotated on disk.
+// It is supported by the getty based protocols, grpc and dubbo3.
+type TlsConfig struct {
+	CertFile string `yaml:"cert_file" json:"cert_file,omitempty" property:"cert_file"`
+	KeyFile  string `yaml:"key_file" json:"key_file,omitempty" property:"key_file"`
+	// CAFile is used to verify the certificates of the peer, the system roots are used by client if it is empty
+	CAFile string `yaml:"ca_file" json:"ca_file,omitempty" property:"ca_file"`
+	// ClientAuth is the policy of server for client authentication:
+	// none(default), request, require, verify_if_given or require_and_verify
+	ClientAuth string `yaml:"client_auth" json:"client_auth,omitempty" property:"client_auth"`
+	// ServerName is used by client to verify the hostname of server and sent as SNI,
+	// it is required by client if CAFile is set
+	ServerName   string   `yaml:"server_name" json:"server_name,omitempty" property:"server_name"`
+	CipherSuites []string `yaml:"cipher_suites" json:"cipher_suites,omitempty" property:"cipher_suites"`
+	// MinVersion is the minimum tls version: 1.0, 1.1, 1.2(default) or 1.3
+	MinVersion string `yaml:"min_version" json:"min_version,omitempty" property:"min_version"`
+}
+
+// urlParams returns the url params of the tls config, ssl is enabled by the tls config
+func (c *TlsConfig) urlParams() url.Values {
+	params := url.Values{}
+	params.Set(constant.SSL_ENABLED_KEY, "true")
+	for k, v := range map[string]string{
+		constant.TLS_CERT_FILE_KEY:     c.CertFile,
+		constant.TLS_KEY_FILE_KEY:      c.KeyFile,
+		constant.TLS_CA_FILE_KEY:       c.CAFile,
+		constant.TLS_CLIENT_AUTH_KEY:   c.ClientAuth,
+		constant.TLS_SERVER_NAME_KEY:   c.ServerName,
+		constant.TLS_CIPHER_SUITES_KEY: strings.Join(c.CipherSuites, ","),
+		constant.TLS_MIN_VERSION_KEY:   c.MinVersion,
+	} {
+		if len(v) > 0 {
+			params.Set(k, v)
+		}
+	}
+	return params
+}
+
+// GetServerTlsConfigBuilderByURL returns the server tls config builder of @url,
+// the global server tls config builder is returned if there is no tls config in @url.
+func GetServerTlsConfigBuilderByURL(url *common.URL) getty.TlsConfigBuilder {
+	if !HasTlsConfig(url) {
+		return GetServerTlsConfigBuilder()
+	}
+	return &urlTlsConfigBuilder{server: true, reloader: newTlsReloader(url)}
+}
+
+// GetClientTlsConfigBuilderByURL returns the client tls config builder of @url,
+// the global client tls config builder is returned if there is no tls config in @url.
+func GetClientTlsConfigBuilderByURL(url *common.URL) getty.TlsConfigBuilder {
+	if !HasTlsConfig(url) {
+		return GetClientTlsConfigBuilder()
+	}
+	return &urlTlsConfigBuilder{reloader: newTlsReloader(url)}
+}
+
+// HasTlsConfig checks whether there is tls config in @url
+func HasTlsConfig(url *common.URL) bool {
+	for _, key := range []string{constant.TLS_CERT_FILE_KEY, constant.TLS_CA_FILE_KEY, constant.TLS_SERVER_NAME_KEY} {
+		if len(url.GetParam(key, "")) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// urlTlsConfigBuilder builds the tls config from the tls params of url
+type urlTlsConfigBuilder struct {
+	server   bool
+	reloader *tlsReloader
+}
+
+// BuildTlsConfig builds a tls config which always uses the latest certificates on disk
+func (b *urlTlsConfigBuilder) BuildTlsConfig() (*tls.Config, error) {
+	r := b.reloader
+	if !b.server && r.caFile != "" && r.serverName == "" {
+		// the certificate of server is verified by VerifyConnection, which does not know the dialed host
+		return nil, perrors.Errorf("the %s of client tls config is required when %s is set",
+			constant.TLS_SERVER_NAME_KEY, constant.TLS_CA_FILE_KEY)
+	}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	if b.server {
+		if r.certFile == "" {
+			return nil, perrors.Errorf("the %s of server tls config is empty", constant.TLS_CERT_FILE_KEY)
+		}
+		return &tls.Config{
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return r.serverConfig()
+			},
+		}, nil
+	}
+	return r.clientConfig(), nil
+}
+
+// tlsFiles are the certificates loaded from disk
+type tlsFiles struct {
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// tlsReloader loads the certificates and reloads them when the modification time of the files changes
+type tlsReloader struct {
+	certFile     string
+	keyFile      string
+	caFile       string
+	serverName   string
+	clientAuth   tls.ClientAuthType
+	cipherSuites []uint16
+	minVersion   uint16
+	err          error
+
+	mutex     sync.Mutex
+	checkedAt time.Time
+	modTimes  []time.Time
+	files     *tlsFiles
+}
+
+func newTlsReloader(url *common.URL) *tlsReloader {
+	r := &tlsReloader{
+		certFile:   url.GetParam(constant.TLS_CERT_FILE_KEY, ""),
+		keyFile:    url.GetParam(constant.TLS_KEY_FILE_KEY, ""),
+		caFile:     url.GetParam(constant.TLS_CA_FILE_KEY, ""),
+		serverName: url.GetParam(constant.TLS_SERVER_NAME_KEY, ""),
+	}
+	if r.clientAuth, r.err = parseClientAuth(url.GetParam(constant.TLS_CLIENT_AUTH_KEY, "")); r.err != nil {
+		return r
+	}
+	if r.minVersion, r.err = parseTlsVersion(url.GetParam(constant.TLS_MIN_VERSION_KEY, "")); r.err != nil {
+		return r
+	}
+	r.cipherSuites, r.err = parseCipherSuites(url.GetParam(constant.TLS_CIPHER_SUITES_KEY, ""))
+	return r
+}
+
+// load returns the certificates, the files are checked at most once per tlsReloadInterval
+func (r *tlsReloader) load() (*tlsFiles, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.files != nil && time.Since(r.checkedAt) < tlsReloadInterval {
+		return r.files, nil
+	}
+	r.checkedAt = time.Now()
+
+	paths := []string{r.certFile, r.keyFile, r.caFile}
+	modTimes := make([]time.Time, len(paths))
+	for i, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			if r.files != nil {
+				// keep the certificates in use while the files are being rotated
+				return r.files, nil
+			}
+			return nil, perrors.WithStack(err)
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if r.files != nil && equalTimes(modTimes, r.modTimes) {
+		return r.files, nil
+	}
+
+	files := &tlsFiles{}
+	if r.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return r.keepFiles(perrors.Wrapf(err, "tls.LoadX509KeyPair(%s, %s)", r.certFile, r.keyFile))
+		}
+		files.cert = &cert
+	}
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return r.keepFiles(perrors.WithStack(err))
+		}
+		files.pool = x509.NewCertPool()
+		if !files.pool.AppendCertsFromPEM(pem) {
+			return r.keepFiles(perrors.Errorf("failed to parse the certificates in %s", r.caFile))
+		}
+	}
+	r.files = files
+	r.modTimes = modTimes
+	return files, nil
+}
+
+// keepFiles returns the certificates in use if there are any, the partially written files are retried next time
+func (r *tlsReloader) keepFiles(err error) (*tlsFiles, error) {
+	if r.files != nil {
+		return r.files, nil
+	}
+	return nil, err
+}
+
+func (r *tlsReloader) baseConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:   r.minVersion,
+		CipherSuites: r.cipherSuites,
+	}
+}
+
+func (r *tlsReloader) serverConfig() (*tls.Config, error) {
+	files, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	config := r.baseConfig()
+	config.Certificates = []tls.Certificate{*files.cert}
+	config.ClientAuth = r.clientAuth
+	config.ClientCAs = files.pool
+	return config, nil
+}
+
+func (r *tlsReloader) clientConfig() *tls.Config {
+	config := r.baseConfig()
+	config.ServerName = r.serverName
+	config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		files, err := r.load()
+		if err != nil {
+			return nil, err
+		}
+		if files.cert == nil {
+			return &tls.Certificate{}, nil
+		}
+		return files.cert, nil
+	}
+	if r.caFile == "" {
+		return config
+	}
+	// the server certificate is verified against the latest CA by VerifyConnection instead of RootCAs
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		files, err := r.load()
+		if err != nil {
+			return err
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return perrors.New("no certificate is provided by server")
+		}
+		opts := x509.VerifyOptions{
+			DNSName:       r.serverName,
+			Roots:         files.pool,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err = cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return config
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseClientAuth(auth string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(auth) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, perrors.Errorf("unknown tls client auth %s", auth)
+}
+
+func parseTlsVersion(version string) (uint16, error) {
+	switch version {
+	case "":
+		return tls.VersionTLS12, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, perrors.Errorf("unknown tls version %s", version)
+}
+
+func parseCipherSuites(names string) ([]uint16, error) {
+	if names == "" {
+		return nil, nil
+	}
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0)
+	for _, name := range strings.Split(names, ",") {
+		id, ok := suites[strings.TrimSpace(name)]
+		if !ok {
+			return nil, perrors.Errorf("unknown tls cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
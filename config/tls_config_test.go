@@ -0,0 +1,267 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "dubbo-go test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	writePem(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", der)
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes the certificate and key named @name signed by the ca into @dir
+func (ca *testCA) issue(t *testing.T, dir, name, commonName string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+	writePem(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+	writePem(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDer)
+}
+
+func writePem(t *testing.T, path, typ string, der []byte) {
+	assert.Nil(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+}
+
+func newTlsURL(t *testing.T, tlsConfig *TlsConfig) *common.URL {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.Nil(t, err)
+	for k, v := range tlsConfig.urlParams() {
+		url.SetParam(k, v[0])
+	}
+	return url
+}
+
+// handshake returns the common name of server certificate
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) (string, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	go func() {
+		server := tls.Server(serverConn, serverConfig)
+		if err := server.Handshake(); err == nil {
+			_, _ = server.Write([]byte{1})
+		}
+		server.Close()
+	}()
+	client := tls.Client(clientConn, clientConfig)
+	// the client certificate is verified after the client finishes the handshake in tls 1.3
+	if _, err := client.Read(make([]byte, 1)); err != nil {
+		return "", err
+	}
+	return client.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestTlsConfigURLParams(t *testing.T) {
+	params := (&TlsConfig{
+		CertFile:     "/cert.pem",
+		ClientAuth:   "require_and_verify",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}).urlParams()
+	assert.Equal(t, "true", params.Get(constant.SSL_ENABLED_KEY))
+	assert.Equal(t, "/cert.pem", params.Get(constant.TLS_CERT_FILE_KEY))
+	assert.Equal(t, "require_and_verify", params.Get(constant.TLS_CLIENT_AUTH_KEY))
+	assert.Equal(t, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		params.Get(constant.TLS_CIPHER_SUITES_KEY))
+	_, ok := params[constant.TLS_KEY_FILE_KEY]
+	assert.False(t, ok)
+}
+
+func TestTlsConfigBuilderByURL(t *testing.T) {
+	url, err := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.Nil(t, err)
+	assert.False(t, HasTlsConfig(url))
+	assert.Nil(t, GetServerTlsConfigBuilderByURL(url))
+
+	url = newTlsURL(t, &TlsConfig{CertFile: "/not/exist.pem", KeyFile: "/not/exist.key"})
+	assert.True(t, HasTlsConfig(url))
+	_, err = GetServerTlsConfigBuilderByURL(url).BuildTlsConfig()
+	assert.NotNil(t, err)
+
+	url = newTlsURL(t, &TlsConfig{ServerName: "localhost", MinVersion: "1.4"})
+	_, err = GetClientTlsConfigBuilderByURL(url).BuildTlsConfig()
+	assert.NotNil(t, err)
+
+	url = newTlsURL(t, &TlsConfig{ServerName: "localhost", ClientAuth: "always"})
+	_, err = GetClientTlsConfigBuilderByURL(url).BuildTlsConfig()
+	assert.NotNil(t, err)
+
+	url = newTlsURL(t, &TlsConfig{ServerName: "localhost", CipherSuites: []string{"TLS_UNKNOWN"}})
+	_, err = GetClientTlsConfigBuilderByURL(url).BuildTlsConfig()
+	assert.NotNil(t, err)
+
+	// the hostname of server can not be verified against the CA without server name
+	url = newTlsURL(t, &TlsConfig{CAFile: "/ca.pem"})
+	_, err = GetClientTlsConfigBuilderByURL(url).BuildTlsConfig()
+	assert.Contains(t, err.Error(), constant.TLS_SERVER_NAME_KEY)
+}
+
+func TestMutualTls(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	ca.issue(t, dir, "server", "server")
+	ca.issue(t, dir, "client", "client")
+
+	serverBuilder := GetServerTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CertFile:   filepath.Join(dir, "server.pem"),
+		KeyFile:    filepath.Join(dir, "server.key"),
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ClientAuth: "require_and_verify",
+	}))
+	serverConfig, err := serverBuilder.BuildTlsConfig()
+	assert.Nil(t, err)
+
+	clientConfig, err := GetClientTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CertFile:   filepath.Join(dir, "client.pem"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ServerName: "localhost",
+	})).BuildTlsConfig()
+	assert.Nil(t, err)
+	name, err := handshake(t, serverConfig, clientConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", name)
+
+	// the client without certificate is rejected
+	clientConfig, err = GetClientTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ServerName: "localhost",
+	})).BuildTlsConfig()
+	assert.Nil(t, err)
+	_, err = handshake(t, serverConfig, clientConfig)
+	assert.NotNil(t, err)
+
+	// the server is verified by server name
+	clientConfig, err = GetClientTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CertFile:   filepath.Join(dir, "client.pem"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ServerName: "other.host",
+	})).BuildTlsConfig()
+	assert.Nil(t, err)
+	_, err = handshake(t, serverConfig, clientConfig)
+	assert.NotNil(t, err)
+}
+
+func TestTlsCertificateReload(t *testing.T) {
+	interval := tlsReloadInterval
+	tlsReloadInterval = 0
+	defer func() {
+		tlsReloadInterval = interval
+	}()
+
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	ca.issue(t, dir, "server", "server-v1")
+
+	serverConfig, err := GetServerTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	})).BuildTlsConfig()
+	assert.Nil(t, err)
+	clientConfig, err := GetClientTlsConfigBuilderByURL(newTlsURL(t, &TlsConfig{
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ServerName: "localhost",
+	})).BuildTlsConfig()
+	assert.Nil(t, err)
+
+	name, err := handshake(t, serverConfig, clientConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "server-v1", name)
+
+	// rotate the server certificate
+	ca.issue(t, dir, "server", "server-v2")
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "server.pem"), later, later))
+	name, err = handshake(t, serverConfig, clientConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "server-v2", name)
+
+	// rotate the ca and the server certificate, the client trusts the new ca
+	ca = newTestCA(t, dir)
+	ca.issue(t, dir, "server", "server-v3")
+	later = later.Add(time.Minute)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "server.pem"), later, later))
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "ca.pem"), later, later))
+	name, err = handshake(t, serverConfig, clientConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "server-v3", name)
+
+	// the broken files are ignored and the certificates in use are kept
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "server.pem"), []byte("broken"), 0o600))
+	later = later.Add(time.Minute)
+	assert.Nil(t, os.Chtimes(filepath.Join(dir, "server.pem"), later, later))
+	name, err = handshake(t, serverConfig, clientConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, "server-v3", name)
+}
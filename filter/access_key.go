@@ -37,3 +37,12 @@ type AccessKeyPair struct {
 type AccessKeyStorage interface {
 	GetAccessKeyPair(protocol.Invocation, *common.URL) *AccessKeyPair
 }
+
+// MultiAccessKeyStorage is the AccessKeyStorage which keeps multiple active AccessKeyPairs of a consumer,
+// so that the requests signed by the old and the new key are both accepted during the rotation of keys.
+// GetAccessKeyPair should return the first one of the active AccessKeyPairs, which is used to sign the requests.
+type MultiAccessKeyStorage interface {
+	AccessKeyStorage
+	// GetAccessKeyPairs returns all active AccessKeyPairs
+	GetAccessKeyPairs(protocol.Invocation, *common.URL) []*AccessKeyPair
+}
@@ -17,6 +17,10 @@
 
 package filter
 
+import (
+	"context"
+)
+
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
@@ -32,3 +36,12 @@ type Authenticator interface {
 	// Authenticate verifies the signature of the request
 	Authenticate(protocol.Invocation, *common.URL) error
 }
+
+// ContextAuthenticator is the Authenticator which passes the identity of the requester to the service,
+// the provider invokes the service with the context returned by AuthenticateContext.
+type ContextAuthenticator interface {
+	Authenticator
+
+	// AuthenticateContext verifies the request and returns the context carrying the identity of the requester
+	AuthenticateContext(context.Context, protocol.Invocation, *common.URL) (context.Context, error)
+}
@@ -0,0 +1,46 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// Cache stores the results of invocations
+type Cache interface {
+	// Get returns the value cached with @key
+	Get(key string) (interface{}, bool)
+	// Put caches @value with @key
+	Put(key string, value interface{})
+}
+
+// CacheFactory creates the Cache of the invoked method
+/*
+ * please register your implementation by invoking SetCacheFactory
+ * The usage, for example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   methods:
+ *     - name: "GetUser"
+ *       cache: "the name of cache factory"
+ */
+type CacheFactory interface {
+	// GetCache returns the Cache of the method invoked by @invocation
+	GetCache(*common.URL, protocol.Invocation) Cache
+}
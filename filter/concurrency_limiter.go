@@ -0,0 +1,43 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter
+
+import (
+	"time"
+)
+
+// ConcurrencyLimiter adjusts the concurrency limit by the samples of the finished requests.
+/*
+ * please register your implementation by invoking SetConcurrencyLimiter
+ * "UserProvider":
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   filter: "adaptive_limit"
+ *   params:
+ *     adaptive.limiter: "vegas" # the name of implementation, gradient2 by default
+ */
+type ConcurrencyLimiter interface {
+	// Limit returns the current concurrency limit
+	Limit() int64
+	// OnSample updates the limit by the round trip time of a finished request, the number of requests
+	// in flight when it started, and whether it was dropped, like timeout
+	OnSample(rtt time.Duration, inflight int64, dropped bool)
+}
+
+// ConcurrencyLimiterCreator creates a ConcurrencyLimiter with the initial, minimum and maximum limit
+type ConcurrencyLimiterCreator func(initial, min, max int64) ConcurrencyLimiter
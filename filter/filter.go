@@ -33,3 +33,12 @@ type Filter interface {
 	// OnResponse updates the results from Invoke and then returns the modified results.
 	OnResponse(context.Context, protocol.Result, protocol.Invoker, protocol.Invocation) protocol.Result
 }
+
+// StreamFilter is an optional interface of Filter to observe streaming calls.
+// OnStreamOpen is called once the stream of the invocation is opened, and OnStreamClose is called with the error
+// which closes the stream, the error is nil if the stream finishes normally.
+type StreamFilter interface {
+	Filter
+	OnStreamOpen(context.Context, protocol.Invoker, protocol.Invocation)
+	OnStreamClose(context.Context, protocol.Invoker, protocol.Invocation, error)
+}
@@ -51,6 +51,8 @@ const (
 	Types = "types"
 	// nolint
 	Arguments = "arguments"
+	// nolint
+	Denied = "denied"
 )
 
 func init() {
@@ -271,5 +273,10 @@ func (ef *AccessLogData) toLogMessage() string {
 	if len(ef.data[Arguments]) > 0 {
 		builder.WriteString(ef.data[Arguments])
 	}
+
+	if len(ef.data[Denied]) > 0 {
+		builder.WriteString(" denied by ")
+		builder.WriteString(ef.data[Denied])
+	}
 	return builder.String()
 }
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// Gradient2Key defines the gradient2 concurrency limit algorithm
+	Gradient2Key = "gradient2"
+
+	// the tolerance of the increase of short rtt before reducing the limit
+	gradient2Tolerance = 1.5
+	// the smoothing factor of the new limit
+	gradient2Smoothing = 0.2
+	// the window of the exponential average of long rtt
+	gradient2LongWindow = 600
+	// the number of samples to warm up the long rtt by the simple average
+	gradient2WarmupWindow = 10
+)
+
+func init() {
+	extension.SetConcurrencyLimiter(Gradient2Key, newGradient2Limiter)
+	extension.SetConcurrencyLimiter(constant.DEFAULT_KEY, newGradient2Limiter)
+}
+
+// gradient2Limiter is the concurrency limiter base on the gradient between the long rtt and the short rtt.
+/**
+ * It's the same as Gradient2Limit of Netflix concurrency-limits.
+ * The long rtt is the exponential average of rtt, and the short rtt is the rtt of the latest sample.
+ * The limit decreases when the short rtt is obviously longer than the long rtt, otherwise it grows by
+ * the queue size sqrt(limit). The limit will not grow if less than half of it is in use.
+ */
+type gradient2Limiter struct {
+	mutex    sync.Mutex
+	limit    float64
+	minLimit float64
+	maxLimit float64
+	longRtt  expAverage
+}
+
+func newGradient2Limiter(initial, min, max int64) filter.ConcurrencyLimiter {
+	return &gradient2Limiter{
+		limit:    float64(initial),
+		minLimit: float64(min),
+		maxLimit: float64(max),
+		longRtt:  expAverage{window: gradient2LongWindow, warmupWindow: gradient2WarmupWindow},
+	}
+}
+
+// Limit returns the current concurrency limit
+func (l *gradient2Limiter) Limit() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return int64(l.limit)
+}
+
+// OnSample updates the limit by the gradient between the long rtt and the rtt of this sample
+func (l *gradient2Limiter) OnSample(rtt time.Duration, inflight int64, _ bool) {
+	if rtt <= 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	shortRtt := float64(rtt)
+	longRtt := l.longRtt.add(shortRtt)
+	// the long rtt is far larger than the short rtt after the latency recovers, so it needs to decay faster
+	if longRtt/shortRtt > 2 {
+		l.longRtt.value = longRtt * 0.95
+	}
+
+	// don't grow the limit if the application can't make use of it
+	if float64(inflight) < l.limit/2 {
+		return
+	}
+
+	gradient := math.Max(0.5, math.Min(1.0, gradient2Tolerance*longRtt/shortRtt))
+	newLimit := l.limit*gradient + math.Sqrt(l.limit)
+	newLimit = l.limit*(1-gradient2Smoothing) + newLimit*gradient2Smoothing
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, newLimit))
+}
+
+// expAverage is the exponential moving average, which is the simple average in the warmup window
+type expAverage struct {
+	window       int
+	warmupWindow int
+	count        int
+	sum          float64
+	value        float64
+}
+
+func (a *expAverage) add(sample float64) float64 {
+	if a.count < a.warmupWindow {
+		a.count++
+		a.sum += sample
+		a.value = a.sum / float64(a.count)
+	} else {
+		factor := 2.0 / float64(a.window+1)
+		a.value = a.value*(1-factor) + sample*factor
+	}
+	return a.value
+}
@@ -0,0 +1,72 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func TestGradient2Limiter(t *testing.T) {
+	limiter := extension.GetConcurrencyLimiterCreator(constant.DEFAULT_KEY)(20, 10, 100)
+	assert.Equal(t, int64(20), limiter.Limit())
+
+	// app limited, the limit doesn't grow
+	limiter.OnSample(10*time.Millisecond, 5, false)
+	assert.Equal(t, int64(20), limiter.Limit())
+
+	// the rtt is stable, the limit grows
+	for i := 0; i < 20; i++ {
+		limiter.OnSample(10*time.Millisecond, limiter.Limit(), false)
+	}
+	grown := limiter.Limit()
+	assert.True(t, grown > 20)
+
+	// the rtt increases, the limit decreases
+	for i := 0; i < 10; i++ {
+		limiter.OnSample(100*time.Millisecond, limiter.Limit(), false)
+	}
+	assert.True(t, limiter.Limit() < grown)
+
+	// the limit is bounded
+	for i := 0; i < 100; i++ {
+		limiter.OnSample(time.Second, limiter.Limit(), false)
+	}
+	assert.Equal(t, int64(10), limiter.Limit())
+	for i := 0; i < 1000; i++ {
+		limiter.OnSample(time.Millisecond, limiter.Limit(), false)
+	}
+	assert.Equal(t, int64(100), limiter.Limit())
+}
+
+func TestExpAverage(t *testing.T) {
+	avg := expAverage{window: 3, warmupWindow: 2}
+	assert.Equal(t, float64(10), avg.add(10))
+	assert.Equal(t, float64(15), avg.add(20))
+	// factor = 2 / (3 + 1)
+	assert.Equal(t, float64(22.5), avg.add(30))
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	// VegasKey defines the vegas concurrency limit algorithm
+	VegasKey = "vegas"
+
+	// the no-load rtt will be reset after limit * vegasProbeMultiplier samples
+	vegasProbeMultiplier = 30
+)
+
+func init() {
+	extension.SetConcurrencyLimiter(VegasKey, newVegasLimiter)
+}
+
+// vegasLimiter is the concurrency limiter base on the queue size estimated by TCP Vegas.
+/**
+ * It's the same as VegasLimit of Netflix concurrency-limits.
+ * The no-load rtt is the minimum rtt, and the queue size is estimated as limit * (1 - rttNoLoad / rtt).
+ * The limit grows when the queue is short and decreases when the queue is long or the request is dropped.
+ * The no-load rtt is reset periodically to probe the change of the latency of the service.
+ */
+type vegasLimiter struct {
+	mutex      sync.Mutex
+	limit      float64
+	minLimit   float64
+	maxLimit   float64
+	rttNoLoad  float64
+	probeCount int64
+}
+
+func newVegasLimiter(initial, min, max int64) filter.ConcurrencyLimiter {
+	return &vegasLimiter{
+		limit:    float64(initial),
+		minLimit: float64(min),
+		maxLimit: float64(max),
+	}
+}
+
+// Limit returns the current concurrency limit
+func (l *vegasLimiter) Limit() int64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return int64(l.limit)
+}
+
+// OnSample updates the limit by the queue size estimated from the rtt of this sample
+func (l *vegasLimiter) OnSample(rtt time.Duration, inflight int64, dropped bool) {
+	if rtt <= 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	sample := float64(rtt)
+	l.probeCount++
+	if float64(l.probeCount) >= l.limit*vegasProbeMultiplier {
+		l.probeCount = 0
+		l.rttNoLoad = sample
+		return
+	}
+	if l.rttNoLoad == 0 || sample < l.rttNoLoad {
+		l.rttNoLoad = sample
+		return
+	}
+
+	step := math.Max(1, math.Log10(l.limit))
+	var newLimit float64
+	if dropped {
+		newLimit = l.limit - step
+	} else if float64(inflight)*2 < l.limit {
+		// don't grow the limit if the application can't make use of it
+		return
+	} else {
+		queueSize := math.Ceil(l.limit * (1 - l.rttNoLoad/sample))
+		alpha, beta := 3*step, 6*step
+		switch {
+		case queueSize <= step:
+			newLimit = l.limit + beta
+		case queueSize < alpha:
+			newLimit = l.limit + step
+		case queueSize > beta:
+			newLimit = l.limit - step
+		default:
+			return
+		}
+	}
+	l.limit = math.Max(l.minLimit, math.Min(l.maxLimit, newLimit))
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adaptive
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+)
+
+func TestVegasLimiter(t *testing.T) {
+	limiter := extension.GetConcurrencyLimiterCreator(VegasKey)(20, 5, 100)
+	assert.Equal(t, int64(20), limiter.Limit())
+
+	// the first sample is the no-load rtt
+	limiter.OnSample(10*time.Millisecond, 20, false)
+	assert.Equal(t, int64(20), limiter.Limit())
+
+	// app limited, the limit doesn't grow
+	limiter.OnSample(10*time.Millisecond, 5, false)
+	assert.Equal(t, int64(20), limiter.Limit())
+
+	// no queue, the limit grows by 6 * log10(20)
+	limiter.OnSample(10*time.Millisecond, 20, false)
+	assert.Equal(t, int64(27), limiter.Limit())
+
+	// the queue is long, the limit decreases by log10(limit)
+	limiter.OnSample(20*time.Millisecond, 27, false)
+	assert.Equal(t, int64(26), limiter.Limit())
+
+	// dropped, the limit decreases even if app limited
+	limiter.OnSample(10*time.Millisecond, 1, true)
+	assert.Equal(t, int64(24), limiter.Limit())
+
+	// the limit is bounded
+	for i := 0; i < 100; i++ {
+		limiter.OnSample(10*time.Millisecond, 1, true)
+	}
+	assert.Equal(t, int64(5), limiter.Limit())
+}
+
+func TestVegasLimiterProbe(t *testing.T) {
+	limiter := newVegasLimiter(1, 1, 100).(*vegasLimiter)
+	limiter.OnSample(10*time.Millisecond, 1, false)
+	assert.Equal(t, float64(10*time.Millisecond), limiter.rttNoLoad)
+
+	// the no-load rtt is reset after limit * vegasProbeMultiplier samples
+	for i := 0; i < vegasProbeMultiplier-2; i++ {
+		limiter.OnSample(20*time.Millisecond, 0, false)
+	}
+	assert.Equal(t, float64(10*time.Millisecond), limiter.rttNoLoad)
+	limiter.OnSample(20*time.Millisecond, 0, false)
+	assert.Equal(t, float64(20*time.Millisecond), limiter.rttNoLoad)
+}
@@ -0,0 +1,182 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/filter_impl/adaptive"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/handler"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// the name of the gauge of the concurrency limit reported to metrics.GaugeReporter
+	concurrencyLimitGauge = "concurrency_limit"
+
+	defaultAdaptiveLimitInitial = 20
+	defaultAdaptiveLimitMin     = 1
+	defaultAdaptiveLimitMax     = 1000
+)
+
+var (
+	adaptiveLimitOnce   sync.Once
+	adaptiveLimitFilter *AdaptiveLimitFilter
+)
+
+func init() {
+	extension.SetFilter(constant.ADAPTIVE_LIMIT_FILTER, GetAdaptiveLimitFilter)
+}
+
+// AdaptiveLimitFilter limits the number of in-progress requests of a service by the limit which is adjusted
+// according to the latency of the requests, instead of a fixed number like ExecuteLimitFilter.
+/**
+ * example:
+ * "UserProvider":
+ *   interface : "com.ikurento.user.UserProvider"
+ *   ... # other configuration
+ *   filter: "adaptive_limit"
+ *   params:
+ *     adaptive.limiter: "vegas" # the name of ConcurrencyLimiter, "gradient2" or "vegas", gradient2 by default
+ *     adaptive.limit.initial: 20 # the initial limit, 20 by default
+ *     adaptive.limit.min: 10 # the minimum limit, 1 by default
+ *     adaptive.limit.max: 500 # the maximum limit, 1000 by default
+ *     adaptive.limit.rejected.handler: "default" # the name of rejected handler
+ * The requests over the limit are handled by the RejectedExecutionHandler.
+ * The current limit is reported as gauge "concurrency_limit" by the metric reporters which implement
+ * metrics.GaugeReporter, like prometheus.
+ */
+type AdaptiveLimitFilter struct {
+	limitState sync.Map
+	reporters  []metrics.GaugeReporter
+}
+
+// adaptiveLimitState defines the limiter and the concurrent count of a service
+type adaptiveLimitState struct {
+	limiter         filter.ConcurrencyLimiter
+	concurrentCount int64
+	reportedLimit   int64
+}
+
+// Invoke rejects the invocation if the processing requests are over the limit, or updates the limit by its latency
+func (f *AdaptiveLimitFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	ivkURL := invoker.GetURL()
+	state := f.getState(ivkURL)
+
+	concurrentCount := atomic.AddInt64(&state.concurrentCount, 1)
+	defer atomic.AddInt64(&state.concurrentCount, -1)
+	if concurrentCount > state.limiter.Limit() {
+		logger.Errorf("The invocation was rejected due to over the adaptive concurrency limitation, url: %s ", ivkURL.String())
+		rejectedHandlerConfig := ivkURL.GetParam(constant.ADAPTIVE_REJECTED_EXECUTION_HANDLER_KEY, constant.DEFAULT_KEY)
+		return extension.GetRejectedExecutionHandler(rejectedHandlerConfig).RejectedExecution(ivkURL, invocation)
+	}
+
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	// the request is regarded as dropped if it is timeout or canceled
+	state.limiter.OnSample(time.Since(start), concurrentCount, ctx.Err() != nil)
+	f.reportLimit(ivkURL, state)
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *AdaptiveLimitFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+func (f *AdaptiveLimitFilter) getState(url *common.URL) *adaptiveLimitState {
+	key := url.ServiceKey()
+	if state, ok := f.limitState.Load(key); ok {
+		return state.(*adaptiveLimitState)
+	}
+
+	minLimit := getLimitParam(url, constant.ADAPTIVE_LIMIT_MIN_KEY, defaultAdaptiveLimitMin)
+	maxLimit := getLimitParam(url, constant.ADAPTIVE_LIMIT_MAX_KEY, defaultAdaptiveLimitMax)
+	if maxLimit < minLimit {
+		logger.Warnf("The adaptive.limit.max %d is less than adaptive.limit.min %d, use the min instead", maxLimit, minLimit)
+		maxLimit = minLimit
+	}
+	initial := getLimitParam(url, constant.ADAPTIVE_LIMIT_INITIAL_KEY, defaultAdaptiveLimitInitial)
+	if initial < minLimit {
+		initial = minLimit
+	} else if initial > maxLimit {
+		initial = maxLimit
+	}
+	limiterName := url.GetParam(constant.ADAPTIVE_LIMITER_KEY, constant.DEFAULT_KEY)
+	state, _ := f.limitState.LoadOrStore(key, &adaptiveLimitState{
+		limiter: extension.GetConcurrencyLimiterCreator(limiterName)(initial, minLimit, maxLimit),
+	})
+	return state.(*adaptiveLimitState)
+}
+
+// reportLimit reports the limit to the gauge reporters when it changes
+func (f *AdaptiveLimitFilter) reportLimit(url *common.URL, state *adaptiveLimitState) {
+	if len(f.reporters) == 0 {
+		return
+	}
+	limit := state.limiter.Limit()
+	if atomic.SwapInt64(&state.reportedLimit, limit) == limit {
+		return
+	}
+	for _, reporter := range f.reporters {
+		reporter.ReportGauge(concurrencyLimitGauge, url, float64(limit))
+	}
+}
+
+func getLimitParam(url *common.URL, key string, defaultValue int64) int64 {
+	value := url.GetParam(key, "")
+	if len(value) == 0 {
+		return defaultValue
+	}
+	limit, err := strconv.ParseInt(value, 0, 0)
+	if err != nil || limit <= 0 {
+		logger.Errorf("The configuration of %s is invalid: %s, use %d instead", key, value, defaultValue)
+		return defaultValue
+	}
+	return limit
+}
+
+// GetAdaptiveLimitFilter returns the singleton AdaptiveLimitFilter instance
+// make sure that the configuration had been loaded before invoking this method.
+func GetAdaptiveLimitFilter() filter.Filter {
+	adaptiveLimitOnce.Do(func() {
+		reporters := make([]metrics.GaugeReporter, 0)
+		for _, name := range config.GetMetricConfig().Reporters {
+			if reporter, ok := extension.GetMetricReporter(name).(metrics.GaugeReporter); ok {
+				reporters = append(reporters, reporter)
+			}
+		}
+		adaptiveLimitFilter = &AdaptiveLimitFilter{
+			reporters: reporters,
+		}
+	})
+	return adaptiveLimitFilter
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+var errAdaptiveLimitRejected = errors.New("rejected by adaptive limit")
+
+type adaptiveLimitRejectedHandler struct{}
+
+func (h *adaptiveLimitRejectedHandler) RejectedExecution(_ *common.URL, _ protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Err: errAdaptiveLimitRejected}
+}
+
+type blockingInvoker struct {
+	protocol.BaseInvoker
+	started chan struct{}
+	release chan struct{}
+}
+
+func (invoker *blockingInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	invoker.started <- struct{}{}
+	<-invoker.release
+	return &protocol.RPCResult{}
+}
+
+type gaugeReporterMock struct {
+	values []float64
+}
+
+func (reporter *gaugeReporterMock) ReportGauge(name string, _ *common.URL, value float64) {
+	if name == concurrencyLimitGauge {
+		reporter.values = append(reporter.values, value)
+	}
+}
+
+func TestAdaptiveLimitFilterInvoke(t *testing.T) {
+	extension.SetRejectedExecutionHandler("adaptive_test", func() filter.RejectedExecutionHandler {
+		return &adaptiveLimitRejectedHandler{}
+	})
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.INTERFACE_KEY, "adaptive"),
+		common.WithParamsValue(constant.ADAPTIVE_LIMITER_KEY, "vegas"),
+		common.WithParamsValue(constant.ADAPTIVE_LIMIT_INITIAL_KEY, "1"),
+		common.WithParamsValue(constant.ADAPTIVE_LIMIT_MAX_KEY, "1"),
+		common.WithParamsValue(constant.ADAPTIVE_REJECTED_EXECUTION_HANDLER_KEY, "adaptive_test"),
+	)
+	invoker := &blockingInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(invokeUrl),
+		started:     make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	inv := invocation.NewRPCInvocation("hello", []interface{}{"OK"}, make(map[string]interface{}))
+	reporter := &gaugeReporterMock{}
+	limitFilter := &AdaptiveLimitFilter{reporters: []metrics.GaugeReporter{reporter}}
+
+	done := make(chan protocol.Result)
+	go func() {
+		done <- limitFilter.Invoke(context.Background(), invoker, inv)
+	}()
+	<-invoker.started
+
+	// the limit is 1, so the second invocation is rejected
+	result := limitFilter.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, errAdaptiveLimitRejected, result.Error())
+
+	close(invoker.release)
+	select {
+	case result = <-done:
+		assert.Nil(t, result.Error())
+	case <-time.After(time.Second):
+		assert.Fail(t, "the invocation is not finished")
+	}
+	assert.Equal(t, []float64{1}, reporter.values)
+}
+
+func TestAdaptiveLimitFilterState(t *testing.T) {
+	limitFilter := GetAdaptiveLimitFilter().(*AdaptiveLimitFilter)
+
+	invokeUrl := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.INTERFACE_KEY, "adaptiveState"),
+	)
+	state := limitFilter.getState(invokeUrl)
+	assert.Equal(t, int64(defaultAdaptiveLimitInitial), state.limiter.Limit())
+	assert.Same(t, state, limitFilter.getState(invokeUrl))
+
+	// the initial limit is bounded by the min and max limit
+	invokeUrl = common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.INTERFACE_KEY, "adaptiveBounded"),
+		common.WithParamsValue(constant.ADAPTIVE_LIMIT_MIN_KEY, "50"),
+		common.WithParamsValue(constant.ADAPTIVE_LIMIT_MAX_KEY, "a100"),
+	)
+	state = limitFilter.getState(invokeUrl)
+	assert.Equal(t, int64(50), state.limiter.Limit())
+
+	result := limitFilter.Invoke(context.Background(), protocol.NewBaseInvoker(invokeUrl),
+		invocation.NewRPCInvocation("hello", []interface{}{"OK"}, make(map[string]interface{})))
+	assert.Nil(t, result.Error())
+	assert.Equal(t, int64(0), state.concurrentCount)
+}
@@ -44,3 +44,32 @@ func init() {
 func GetDefaultAccesskeyStorage() filter.AccessKeyStorage {
 	return &DefaultAccesskeyStorage{}
 }
+
+// getConsumerApplication returns the application name of consumer, which is carried by the attachments
+// on the provider side
+func getConsumerApplication(invocation protocol.Invocation, url *common.URL) string {
+	if invocation != nil {
+		if consumer := invocation.AttachmentsByKey(constant.CONSUMER, ""); len(consumer) > 0 {
+			return consumer
+		}
+	}
+	return url.GetParam(constant.APPLICATION_KEY, "")
+}
+
+// firstAccessKeyPair returns the first one of @pairs, which is used to sign the requests
+func firstAccessKeyPair(pairs []*filter.AccessKeyPair) *filter.AccessKeyPair {
+	if len(pairs) == 0 {
+		return nil
+	}
+	return pairs[0]
+}
+
+// setConsumerSide sets the ConsumerSide of @pairs to the application they are stored for,
+// which is trusted by the authenticator once the request is verified
+func setConsumerSide(pairs []*filter.AccessKeyPair, application string) {
+	for _, pair := range pairs {
+		if pair != nil {
+			pair.ConsumerSide = application
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+var configCenterAccessKeyStorageInstance = &ConfigCenterAccessKeyStorage{rules: make(map[string]*accessKeyRule)}
+
+func init() {
+	extension.SetAccesskeyStorages(constant.CONFIG_CENTER_ACCESS_KEY_STORAGE, GetConfigCenterAccessKeyStorage)
+}
+
+// ConfigCenterAccessKeyStorage loads the AccessKeyPairs of consumer applications from the config center,
+// and listens the changes of them.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   params:
+ *     auth: "true"
+ *     accessKey.storage: "configcenterstorage"
+ *     accessKey.group: "dubbo" # the group of access keys in config center, dubbo by default
+ * the AccessKeyPairs of application user-info-client are stored with the key "user-info-client.accesskeys",
+ * and the consumerSide of them is user-info-client:
+ * - accessKey: "ak2" # the first key is used by consumer to sign the requests
+ *   secretKey: "sk2"
+ * - accessKey: "ak1" # the old key is still accepted by provider until it is removed
+ *   secretKey: "sk1"
+ */
+type ConfigCenterAccessKeyStorage struct {
+	mutex sync.Mutex
+	rules map[string]*accessKeyRule
+}
+
+// GetAccessKeyPair returns the first active AccessKeyPair of the consumer application
+func (storage *ConfigCenterAccessKeyStorage) GetAccessKeyPair(invocation protocol.Invocation, url *common.URL) *filter.AccessKeyPair {
+	return firstAccessKeyPair(storage.GetAccessKeyPairs(invocation, url))
+}
+
+// GetAccessKeyPairs returns all active AccessKeyPairs of the consumer application
+func (storage *ConfigCenterAccessKeyStorage) GetAccessKeyPairs(invocation protocol.Invocation, url *common.URL) []*filter.AccessKeyPair {
+	application := getConsumerApplication(invocation, url)
+	if len(application) == 0 {
+		logger.Errorf("The application of consumer is unknown, url: %s", url)
+		return nil
+	}
+	group := url.GetParam(constant.ACCESS_KEY_GROUP_KEY, config_center.DEFAULT_GROUP)
+	rule, err := storage.getRule(group, application)
+	if err != nil {
+		logger.Errorf("Failed to load the access keys of application %s, error: %v", application, err)
+		return nil
+	}
+	return rule.get()
+}
+
+// getRule returns the accessKeyRule of @application, it will be loaded and listened at the first time
+func (storage *ConfigCenterAccessKeyStorage) getRule(group, application string) (*accessKeyRule, error) {
+	key := application + constant.AccessKeyRuleSuffix
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+	ruleKey := group + constant.PATH_SEPARATOR + key
+	if rule, ok := storage.rules[ruleKey]; ok {
+		return rule, nil
+	}
+
+	dynamicConfiguration := config.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		return nil, perrors.New("the dynamic configuration is nil, please config the config center")
+	}
+	rule := &accessKeyRule{application: application}
+	dynamicConfiguration.AddListener(key, rule, config_center.WithGroup(group))
+	value, err := dynamicConfiguration.GetRule(key, config_center.WithGroup(group))
+	if err != nil {
+		dynamicConfiguration.RemoveListener(key, rule, config_center.WithGroup(group))
+		return nil, perrors.WithMessagef(err, "get access keys fail, key{%s}", key)
+	}
+	if len(value) != 0 {
+		rule.Process(&config_center.ConfigChangeEvent{Key: key, Value: value, ConfigType: remoting.EventTypeAdd})
+	}
+	storage.rules[ruleKey] = rule
+	return rule, nil
+}
+
+// GetConfigCenterAccessKeyStorage returns the singleton ConfigCenterAccessKeyStorage
+func GetConfigCenterAccessKeyStorage() filter.AccessKeyStorage {
+	return configCenterAccessKeyStorageInstance
+}
+
+// accessKeyRule keeps the AccessKeyPairs of an application in sync with the config center
+type accessKeyRule struct {
+	application string
+	mutex       sync.RWMutex
+	keys        []*filter.AccessKeyPair
+}
+
+func (rule *accessKeyRule) get() []*filter.AccessKeyPair {
+	rule.mutex.RLock()
+	defer rule.mutex.RUnlock()
+	return rule.keys
+}
+
+// Process updates the AccessKeyPairs once they are changed in the config center
+func (rule *accessKeyRule) Process(event *config_center.ConfigChangeEvent) {
+	logger.Infof("Notification of access keys %s, change type is:[%s]", event.Key, event.ConfigType)
+	if remoting.EventTypeDel == event.ConfigType {
+		rule.mutex.Lock()
+		rule.keys = nil
+		rule.mutex.Unlock()
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok {
+		logger.Errorf("Convert event content fail, raw content:[%v]", event.Value)
+		return
+	}
+	var keys []*filter.AccessKeyPair
+	// the keys in use are kept if the new content is broken
+	if err := yaml.Unmarshal([]byte(strings.TrimSpace(content)), &keys); err != nil {
+		logger.Errorf("Parse access keys %s fail, error:[%v]", event.Key, err)
+		return
+	}
+	setConsumerSide(keys, rule.application)
+	rule.mutex.Lock()
+	rule.keys = keys
+	rule.mutex.Unlock()
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestConfigCenterAccessKeyStorage(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: `
+- accessKey: "ak2"
+  secretKey: "sk2"
+- accessKey: "ak1"
+  secretKey: "sk1"
+`}
+	dynamicConfiguration, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dynamicConfiguration)
+
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=test")
+	url.SetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.CONFIG_CENTER_ACCESS_KEY_STORAGE)
+	storage := getAccesskeyStorage(url).(*ConfigCenterAccessKeyStorage)
+	inv := invocation.NewRPCInvocation("test", []interface{}{"OK"}, nil)
+
+	accessKeyPair := storage.GetAccessKeyPair(inv, url)
+	assert.Equal(t, "ak2", accessKeyPair.AccessKey)
+	assert.Equal(t, "sk2", accessKeyPair.SecretKey)
+	assert.Equal(t, "test", accessKeyPair.ConsumerSide)
+	assert.Len(t, storage.GetAccessKeyPairs(inv, url), 2)
+
+	// the keys are updated once they are changed in the config center
+	rule, err := storage.getRule(config_center.DEFAULT_GROUP, "test")
+	assert.Nil(t, err)
+	rule.Process(&config_center.ConfigChangeEvent{Key: "test" + constant.AccessKeyRuleSuffix,
+		Value: "- accessKey: ak3\n  secretKey: sk3\n", ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, "ak3", storage.GetAccessKeyPair(inv, url).AccessKey)
+	assert.Equal(t, "test", storage.GetAccessKeyPair(inv, url).ConsumerSide)
+
+	// the broken content is ignored
+	rule.Process(&config_center.ConfigChangeEvent{Key: "test" + constant.AccessKeyRuleSuffix,
+		Value: "- [", ConfigType: remoting.EventTypeUpdate})
+	assert.Equal(t, "ak3", storage.GetAccessKeyPair(inv, url).AccessKey)
+
+	rule.Process(&config_center.ConfigChangeEvent{Key: "test" + constant.AccessKeyRuleSuffix,
+		ConfigType: remoting.EventTypeDel})
+	assert.Nil(t, storage.GetAccessKeyPair(inv, url))
+
+	// the consumer is unknown
+	url.DelParam(constant.APPLICATION_KEY)
+	assert.Nil(t, storage.GetAccessKeyPairs(inv, url))
+}
@@ -18,6 +18,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -83,6 +84,25 @@ func getSignature(url *common.URL, invocation protocol.Invocation, secrectKey st
 
 // Authenticate verifies whether the signature sent by the requester is correct
 func (authenticator *DefaultAuthenticator) Authenticate(invocation protocol.Invocation, url *common.URL) error {
+	_, err := authenticate(invocation, url)
+	return err
+}
+
+// AuthenticateContext verifies the signature and puts the consumer application into the context if the
+// access key is issued to it, i.e. the consumerSide of the AccessKeyPair is the consumer in the request
+func (authenticator *DefaultAuthenticator) AuthenticateContext(ctx context.Context, invocation protocol.Invocation,
+	url *common.URL) (context.Context, error) {
+	accessKeyPair, err := authenticate(invocation, url)
+	if err != nil {
+		return ctx, err
+	}
+	if consumer := invocation.AttachmentsByKey(constant.CONSUMER, ""); consumer == accessKeyPair.ConsumerSide {
+		ctx = context.WithValue(ctx, constant.AuthApplicationKey, consumer)
+	}
+	return ctx, nil
+}
+
+func authenticate(invocation protocol.Invocation, url *common.URL) (*filter.AccessKeyPair, error) {
 	accessKeyId := invocation.AttachmentsByKey(constant.AK_KEY, "")
 
 	requestTimestamp := invocation.AttachmentsByKey(constant.REQUEST_TIMESTAMP_KEY, "")
@@ -90,27 +110,26 @@ func (authenticator *DefaultAuthenticator) Authenticate(invocation protocol.Invo
 	consumer := invocation.AttachmentsByKey(constant.CONSUMER, "")
 	if IsEmpty(accessKeyId, false) || IsEmpty(consumer, false) ||
 		IsEmpty(requestTimestamp, false) || IsEmpty(originSignature, false) {
-		return errors.New("failed to authenticate your ak/sk, maybe the consumer has not enabled the auth")
+		return nil, errors.New("failed to authenticate your ak/sk, maybe the consumer has not enabled the auth")
 	}
 
-	accessKeyPair, err := getAccessKeyPair(invocation, url)
+	accessKeyPair, err := getAccessKeyPairById(invocation, url, accessKeyId)
 	if err != nil {
-		return errors.New("failed to authenticate , can't load the accessKeyPair")
+		return nil, errors.New("failed to authenticate , can't load the accessKeyPair")
 	}
 
 	computeSignature, err := getSignature(url, invocation, accessKeyPair.SecretKey, requestTimestamp)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if success := computeSignature == originSignature; !success {
-		return errors.New("failed to authenticate, signature is not correct")
+		return nil, errors.New("failed to authenticate, signature is not correct")
 	}
-	return nil
+	return accessKeyPair, nil
 }
 
 func getAccessKeyPair(invocation protocol.Invocation, url *common.URL) (*filter.AccessKeyPair, error) {
-	accesskeyStorage := extension.GetAccesskeyStorages(url.GetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.DEFAULT_ACCESS_KEY_STORAGE))
-	accessKeyPair := accesskeyStorage.GetAccessKeyPair(invocation, url)
+	accessKeyPair := getAccesskeyStorage(url).GetAccessKeyPair(invocation, url)
 	if accessKeyPair == nil || IsEmpty(accessKeyPair.AccessKey, false) || IsEmpty(accessKeyPair.SecretKey, true) {
 		return nil, errors.New("accessKeyId or secretAccessKey not found")
 	} else {
@@ -118,6 +137,25 @@ func getAccessKeyPair(invocation protocol.Invocation, url *common.URL) (*filter.
 	}
 }
 
+// getAccessKeyPairById finds the active AccessKeyPair of @accessKeyId if the storage keeps multiple pairs,
+// so that the requests signed by any of them can be verified during the rotation of keys
+func getAccessKeyPairById(invocation protocol.Invocation, url *common.URL, accessKeyId string) (*filter.AccessKeyPair, error) {
+	multiStorage, ok := getAccesskeyStorage(url).(filter.MultiAccessKeyStorage)
+	if !ok {
+		return getAccessKeyPair(invocation, url)
+	}
+	for _, accessKeyPair := range multiStorage.GetAccessKeyPairs(invocation, url) {
+		if accessKeyPair != nil && accessKeyPair.AccessKey == accessKeyId && !IsEmpty(accessKeyPair.SecretKey, true) {
+			return accessKeyPair, nil
+		}
+	}
+	return nil, errors.New("accessKeyId " + accessKeyId + " not found")
+}
+
+func getAccesskeyStorage(url *common.URL) filter.AccessKeyStorage {
+	return extension.GetAccesskeyStorages(url.GetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.DEFAULT_ACCESS_KEY_STORAGE))
+}
+
 // GetDefaultAuthenticator creates an empty DefaultAuthenticator instance
 func GetDefaultAuthenticator() filter.Authenticator {
 	return &DefaultAuthenticator{}
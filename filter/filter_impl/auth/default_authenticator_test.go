@@ -18,6 +18,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -32,6 +33,9 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
@@ -71,6 +75,51 @@ func TestDefaultAuthenticator_Authenticate(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+type consumerSideAccesskeyStorage struct{}
+
+func (storage *consumerSideAccesskeyStorage) GetAccessKeyPair(_ protocol.Invocation, url *common.URL) *filter.AccessKeyPair {
+	return &filter.AccessKeyPair{
+		AccessKey:    url.GetParam(constant.ACCESS_KEY_ID_KEY, ""),
+		SecretKey:    url.GetParam(constant.SECRET_ACCESS_KEY_KEY, ""),
+		ConsumerSide: "user-info-client",
+	}
+}
+
+func TestDefaultAuthenticator_AuthenticateContext(t *testing.T) {
+	extension.SetAccesskeyStorages("consumer-side", func() filter.AccessKeyStorage {
+		return &consumerSideAccesskeyStorage{}
+	})
+	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	testurl.SetParam(constant.ACCESS_KEY_ID_KEY, "ak")
+	testurl.SetParam(constant.SECRET_ACCESS_KEY_KEY, "sk")
+	newInvocation := func(consumer string) protocol.Invocation {
+		requestTime := strconv.Itoa(int(time.Now().Unix() * 1000))
+		signature, _ := getSignature(testurl, invocation.NewRPCInvocation("test", nil, nil), "sk", requestTime)
+		return invocation.NewRPCInvocation("test", nil, map[string]interface{}{
+			constant.REQUEST_SIGNATURE_KEY: signature,
+			constant.CONSUMER:              consumer,
+			constant.REQUEST_TIMESTAMP_KEY: requestTime,
+			constant.AK_KEY:                "ak",
+		})
+	}
+	authenticator := &DefaultAuthenticator{}
+
+	// the application is not verified if the access key is not issued to any consumer
+	ctx, err := authenticator.AuthenticateContext(context.Background(), newInvocation("user-info-client"), testurl)
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.Value(constant.AuthApplicationKey))
+
+	testurl.SetParam(constant.ACCESS_KEY_STORAGE_KEY, "consumer-side")
+	ctx, err = authenticator.AuthenticateContext(context.Background(), newInvocation("user-info-client"), testurl)
+	assert.Nil(t, err)
+	assert.Equal(t, "user-info-client", ctx.Value(constant.AuthApplicationKey))
+
+	// the consumer claiming another application is not verified
+	ctx, err = authenticator.AuthenticateContext(context.Background(), newInvocation("admin-client"), testurl)
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.Value(constant.AuthApplicationKey))
+}
+
 func TestDefaultAuthenticator_Sign(t *testing.T) {
 	authenticator := &DefaultAuthenticator{}
 	testurl, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=test&interface=com.ikurento.user.UserProvider&group=gg&version=2.6.0")
@@ -0,0 +1,188 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+)
+
+import (
+	"github.com/fsnotify/fsnotify"
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var fileAccessKeyStorageInstance = &FileAccessKeyStorage{files: make(map[string]*accessKeyFile)}
+
+func init() {
+	extension.SetAccesskeyStorages(constant.FILE_ACCESS_KEY_STORAGE, GetFileAccessKeyStorage)
+}
+
+// FileAccessKeyStorage loads the AccessKeyPairs of consumer applications from a local yaml file,
+// and reloads them once the file is changed.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   params:
+ *     auth: "true"
+ *     accessKey.storage: "filestorage"
+ *     accessKey.file: "/etc/dubbo/accesskeys.yml"
+ * the content of file:
+ * user-info-client: # the application name of consumer, which is the consumerSide of its keys
+ *   - accessKey: "ak2" # the first key is used by consumer to sign the requests
+ *     secretKey: "sk2"
+ *   - accessKey: "ak1" # the old key is still accepted by provider until it is removed
+ *     secretKey: "sk1"
+ */
+type FileAccessKeyStorage struct {
+	mutex sync.Mutex
+	files map[string]*accessKeyFile
+}
+
+// GetAccessKeyPair returns the first active AccessKeyPair of the consumer application
+func (storage *FileAccessKeyStorage) GetAccessKeyPair(invocation protocol.Invocation, url *common.URL) *filter.AccessKeyPair {
+	return firstAccessKeyPair(storage.GetAccessKeyPairs(invocation, url))
+}
+
+// GetAccessKeyPairs returns all active AccessKeyPairs of the consumer application
+func (storage *FileAccessKeyStorage) GetAccessKeyPairs(invocation protocol.Invocation, url *common.URL) []*filter.AccessKeyPair {
+	path := url.GetParam(constant.ACCESS_KEY_FILE_KEY, "")
+	if len(path) == 0 {
+		logger.Errorf("The access key file is not configured by %s, url: %s", constant.ACCESS_KEY_FILE_KEY, url)
+		return nil
+	}
+	file, err := storage.getFile(path)
+	if err != nil {
+		logger.Errorf("Failed to load the access key file %s, error: %v", path, err)
+		return nil
+	}
+	return file.get(getConsumerApplication(invocation, url))
+}
+
+// getFile returns the loaded accessKeyFile of @path, it will be loaded and watched at the first time
+func (storage *FileAccessKeyStorage) getFile(path string) (*accessKeyFile, error) {
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+	if file, ok := storage.files[path]; ok {
+		return file, nil
+	}
+	file, err := newAccessKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	storage.files[path] = file
+	return file, nil
+}
+
+// GetFileAccessKeyStorage returns the singleton FileAccessKeyStorage
+func GetFileAccessKeyStorage() filter.AccessKeyStorage {
+	return fileAccessKeyStorageInstance
+}
+
+// accessKeyFile keeps the AccessKeyPairs in the file in sync with the file
+type accessKeyFile struct {
+	path    string
+	mutex   sync.RWMutex
+	keys    map[string][]*filter.AccessKeyPair
+	watcher *fsnotify.Watcher
+}
+
+func newAccessKeyFile(path string) (*accessKeyFile, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	file := &accessKeyFile{path: path}
+	if err = file.load(); err != nil {
+		return nil, err
+	}
+
+	// watch the directory since the file may be replaced by rename during the rotation
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, perrors.WithStack(err)
+	}
+	file.watcher = watcher
+	go file.watch()
+	return file, nil
+}
+
+func (file *accessKeyFile) get(application string) []*filter.AccessKeyPair {
+	file.mutex.RLock()
+	defer file.mutex.RUnlock()
+	return file.keys[application]
+}
+
+func (file *accessKeyFile) load() error {
+	content, err := ioutil.ReadFile(file.path)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	keys := make(map[string][]*filter.AccessKeyPair)
+	if err = yaml.Unmarshal(content, &keys); err != nil {
+		return perrors.WithStack(err)
+	}
+	for application, pairs := range keys {
+		setConsumerSide(pairs, application)
+	}
+	file.mutex.Lock()
+	file.keys = keys
+	file.mutex.Unlock()
+	return nil
+}
+
+func (file *accessKeyFile) watch() {
+	for {
+		select {
+		case event, ok := <-file.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != file.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			// the keys in use are kept if the new file is broken
+			if err := file.load(); err != nil {
+				logger.Warnf("Failed to reload the access key file %s, error: %v", file.path, err)
+				continue
+			}
+			logger.Infof("The access key file %s is reloaded", file.path)
+		case err, ok := <-file.watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("Failed to watch the access key file %s, error: %v", file.path, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const accessKeyFileContent = `
+test:
+  - accessKey: "ak2"
+    secretKey: "sk2"
+  - accessKey: "ak1"
+    secretKey: "sk1"
+other:
+  - accessKey: "ak3"
+    secretKey: "sk3"
+`
+
+func TestFileAccessKeyStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesskeys")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accesskeys.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(accessKeyFileContent), 0o600))
+
+	consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=test")
+	consumerURL.SetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.FILE_ACCESS_KEY_STORAGE)
+	consumerURL.SetParam(constant.ACCESS_KEY_FILE_KEY, path)
+	storage := getAccesskeyStorage(consumerURL).(*FileAccessKeyStorage)
+	inv := invocation.NewRPCInvocation("test", []interface{}{"OK"}, nil)
+
+	// the first key is used to sign
+	accessKeyPair := storage.GetAccessKeyPair(inv, consumerURL)
+	assert.Equal(t, "ak2", accessKeyPair.AccessKey)
+	assert.Equal(t, "sk2", accessKeyPair.SecretKey)
+	assert.Equal(t, "test", accessKeyPair.ConsumerSide)
+
+	// the provider finds the keys by the consumer in attachments
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=provider")
+	providerURL.SetParam(constant.ACCESS_KEY_FILE_KEY, path)
+	inv.SetAttachments(constant.CONSUMER, "other")
+	assert.Len(t, storage.GetAccessKeyPairs(inv, providerURL), 1)
+	assert.Equal(t, "other", storage.GetAccessKeyPair(inv, providerURL).ConsumerSide)
+	inv.SetAttachments(constant.CONSUMER, "unknown")
+	assert.Nil(t, storage.GetAccessKeyPair(inv, providerURL))
+
+	// the keys are reloaded once the file is changed, and the broken file is ignored
+	assert.Nil(t, ioutil.WriteFile(path, []byte("test: ["), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	assert.Len(t, storage.GetAccessKeyPairs(nil, consumerURL), 2)
+	assert.Nil(t, ioutil.WriteFile(path, []byte("test:\n  - accessKey: ak4\n    secretKey: sk4\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		accessKeyPair := storage.GetAccessKeyPair(nil, consumerURL)
+		return accessKeyPair != nil && accessKeyPair.AccessKey == "ak4"
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// the file is not existing
+	consumerURL.SetParam(constant.ACCESS_KEY_FILE_KEY, filepath.Join(dir, "unknown.yml"))
+	assert.Nil(t, storage.GetAccessKeyPairs(nil, consumerURL))
+}
+
+func TestDefaultAuthenticator_AuthenticateWithRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesskeys")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "accesskeys.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(accessKeyFileContent), 0o600))
+
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	providerURL.SetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.FILE_ACCESS_KEY_STORAGE)
+	providerURL.SetParam(constant.ACCESS_KEY_FILE_KEY, path)
+	authenticator := &DefaultAuthenticator{}
+	requestTime := strconv.Itoa(int(time.Now().Unix() * 1000))
+	newInvocation := func(access, secret string) *invocation.RPCInvocation {
+		inv := invocation.NewRPCInvocation("test", []interface{}{"OK"}, nil)
+		signature, _ := getSignature(providerURL, inv, secret, requestTime)
+		inv.SetAttachments(constant.REQUEST_SIGNATURE_KEY, signature)
+		inv.SetAttachments(constant.REQUEST_TIMESTAMP_KEY, requestTime)
+		inv.SetAttachments(constant.AK_KEY, access)
+		inv.SetAttachments(constant.CONSUMER, "test")
+		return inv
+	}
+
+	// both the new and old keys are accepted during the rotation
+	assert.Nil(t, authenticator.Authenticate(newInvocation("ak2", "sk2"), providerURL))
+	assert.Nil(t, authenticator.Authenticate(newInvocation("ak1", "sk1"), providerURL))
+	assert.NotNil(t, authenticator.Authenticate(newInvocation("ak1", "sk2"), providerURL))
+	assert.NotNil(t, authenticator.Authenticate(newInvocation("ak3", "sk3"), providerURL))
+}
@@ -0,0 +1,313 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	defaultJWTClockSkew = time.Minute
+	bearerPrefix        = "bearer "
+)
+
+var (
+	jwtAuthenticatorOnce sync.Once
+	jwtAuthenticator     *JWTAuthenticator
+)
+
+func init() {
+	extension.SetAuthenticator(constant.JWT_AUTHENTICATOR, GetJWTAuthenticator)
+}
+
+// JWTClaims is the claims of a verified jwt token
+type JWTClaims map[string]interface{}
+
+// JWTClaimsFromContext returns the claims of the verified jwt token carried by @ctx
+func JWTClaimsFromContext(ctx context.Context) (JWTClaims, bool) {
+	claims, ok := ctx.Value(constant.JWTClaimsKey).(JWTClaims)
+	return claims, ok
+}
+
+// JWTAuthenticator verifies the bearer token signed by asymmetric keys,
+// the public keys are loaded from JWKS or PEM files configured in the url.
+type JWTAuthenticator struct{}
+
+// GetJWTAuthenticator returns the singleton JWTAuthenticator
+func GetJWTAuthenticator() filter.Authenticator {
+	jwtAuthenticatorOnce.Do(func() {
+		jwtAuthenticator = &JWTAuthenticator{}
+	})
+	return jwtAuthenticator
+}
+
+// Sign attaches the token configured by jwt.token if the invocation carries no token
+func (authenticator *JWTAuthenticator) Sign(invocation protocol.Invocation, url *common.URL) error {
+	attachmentKey := url.GetParam(constant.JWT_ATTACHMENT_KEY, constant.DEFAULT_JWT_ATTACHMENT)
+	if len(getAttachmentString(invocation, attachmentKey)) > 0 {
+		return nil
+	}
+	token := url.GetParam(constant.JWT_TOKEN_KEY, "")
+	if len(token) == 0 {
+		return perrors.Errorf("no jwt token is found in attachment %s or configuration", attachmentKey)
+	}
+	invocation.SetAttachments(attachmentKey, "Bearer "+token)
+	return nil
+}
+
+// Authenticate verifies the jwt token carried by the invocation
+func (authenticator *JWTAuthenticator) Authenticate(invocation protocol.Invocation, url *common.URL) error {
+	_, err := authenticator.AuthenticateContext(context.Background(), invocation, url)
+	return err
+}
+
+// AuthenticateContext verifies the jwt token carried by the invocation and puts the claims into the context
+func (authenticator *JWTAuthenticator) AuthenticateContext(ctx context.Context, invocation protocol.Invocation,
+	url *common.URL) (context.Context, error) {
+	attachmentKey := url.GetParam(constant.JWT_ATTACHMENT_KEY, constant.DEFAULT_JWT_ATTACHMENT)
+	token := getAttachmentString(invocation, attachmentKey)
+	if len(token) >= len(bearerPrefix) && strings.EqualFold(token[:len(bearerPrefix)], bearerPrefix) {
+		token = strings.TrimSpace(token[len(bearerPrefix):])
+	}
+	if len(token) == 0 {
+		return ctx, perrors.New("no jwt token is found in the request")
+	}
+
+	claims, err := verifyJWT(token, url)
+	if err != nil {
+		return ctx, perrors.WithMessage(err, "invalid jwt token")
+	}
+	if err = checkJWTClaims(claims, url, invocation.MethodName()); err != nil {
+		return ctx, err
+	}
+	ctx = context.WithValue(ctx, constant.JWTClaimsKey, claims)
+	// the subject of the verified token is the consumer application
+	if sub, ok := claims["sub"].(string); ok && len(sub) > 0 {
+		ctx = context.WithValue(ctx, constant.AuthApplicationKey, sub)
+	}
+	return ctx, nil
+}
+
+// verifyJWT verifies the signature of the compact serialized @token and returns its claims
+func verifyJWT(token string, url *common.URL) (JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, perrors.New("malformed token")
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, perrors.New("malformed token header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, perrors.New("malformed token header")
+	}
+	// the symmetric algorithms and unsecured tokens are never accepted
+	if len(header.Alg) == 0 || strings.EqualFold(header.Alg, "none") || strings.HasPrefix(header.Alg, "HS") {
+		return nil, perrors.Errorf("algorithm %s is not allowed", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, perrors.New("malformed token signature")
+	}
+
+	keys, err := findJWTKeys(url, header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	signingInput := []byte(parts[0] + "." + parts[1])
+	verified := false
+	for _, key := range keys {
+		if verifyJWTSignature(header.Alg, key, signingInput, signature) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, perrors.New("signature verification failed")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, perrors.New("malformed token payload")
+	}
+	claims := JWTClaims{}
+	decoder := json.NewDecoder(strings.NewReader(string(payload)))
+	decoder.UseNumber()
+	if err = decoder.Decode(&claims); err != nil {
+		return nil, perrors.New("malformed token payload")
+	}
+	return claims, nil
+}
+
+// findJWTKeys returns the keys configured by jwt.jwks.file and jwt.pem.file which match @kid and @alg
+func findJWTKeys(url *common.URL, kid, alg string) ([]crypto.PublicKey, error) {
+	jwksFile := url.GetParam(constant.JWT_JWKS_FILE_KEY, "")
+	pemFile := url.GetParam(constant.JWT_PEM_FILE_KEY, "")
+	if len(jwksFile) == 0 && len(pemFile) == 0 {
+		return nil, perrors.Errorf("neither %s nor %s is configured", constant.JWT_JWKS_FILE_KEY, constant.JWT_PEM_FILE_KEY)
+	}
+	keys := make([]crypto.PublicKey, 0, 1)
+	if len(jwksFile) > 0 {
+		keySet, err := getJWTKeySet(jwksFile, parseJWKS)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keySet.find(kid, alg) {
+			keys = append(keys, key)
+		}
+	}
+	if len(pemFile) > 0 {
+		keySet, err := getJWTKeySet(pemFile, parsePEMKeys)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keySet.find(kid, alg) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, perrors.Errorf("no key is found for kid %s", kid)
+	}
+	return keys, nil
+}
+
+// checkJWTClaims checks the registered claims and the scopes required by @methodName
+func checkJWTClaims(claims JWTClaims, url *common.URL, methodName string) error {
+	skew := defaultJWTClockSkew
+	if v := url.GetParam(constant.JWT_CLOCK_SKEW_KEY, ""); len(v) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return perrors.Errorf("invalid %s: %s", constant.JWT_CLOCK_SKEW_KEY, v)
+		}
+		skew = d
+	}
+	now := time.Now()
+
+	exp, ok := claims.numericDate("exp")
+	if !ok {
+		return perrors.New("the token has no expiration time")
+	}
+	if now.After(exp.Add(skew)) {
+		return perrors.New("the token is expired")
+	}
+	if nbf, ok := claims.numericDate("nbf"); ok && now.Add(skew).Before(nbf) {
+		return perrors.New("the token is not valid yet")
+	}
+
+	if issuer := url.GetParam(constant.JWT_ISSUER_KEY, ""); len(issuer) > 0 {
+		if iss, _ := claims["iss"].(string); iss != issuer {
+			return perrors.Errorf("unexpected issuer %v", claims["iss"])
+		}
+	}
+	if audience := url.GetParam(constant.JWT_AUDIENCE_KEY, ""); len(audience) > 0 {
+		if !containsString(claims.stringList("aud", ""), audience) {
+			return perrors.Errorf("the token is not issued for audience %s", audience)
+		}
+	}
+
+	required := url.GetMethodParam(methodName, constant.JWT_SCOPES_KEY, url.GetParam(constant.JWT_SCOPES_KEY, ""))
+	if len(required) > 0 {
+		scopes := claims.stringList("scope", " ")
+		scopes = append(scopes, claims.stringList("scp", " ")...)
+		for _, scope := range strings.Split(required, ",") {
+			scope = strings.TrimSpace(scope)
+			if len(scope) > 0 && !containsString(scopes, scope) {
+				return perrors.Errorf("the token lacks scope %s required by method %s", scope, methodName)
+			}
+		}
+	}
+	return nil
+}
+
+// numericDate returns the time of NumericDate claim @name
+func (c JWTClaims) numericDate(name string) (time.Time, bool) {
+	n, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	sec := int64(f)
+	return time.Unix(sec, int64((f-float64(sec))*float64(time.Second))), true
+}
+
+// stringList returns the claim @name which is a string array or a string separated by @sep
+func (c JWTClaims) stringList(name string, sep string) []string {
+	switch v := c[name].(type) {
+	case string:
+		if len(sep) == 0 {
+			return []string{v}
+		}
+		return strings.Fields(v)
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// getAttachmentString returns the attachment @key which may be a string or a string slice
+func getAttachmentString(invocation protocol.Invocation, key string) string {
+	switch v := invocation.Attachment(key).(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,243 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signJWT(t *testing.T, header, claims map[string]interface{}, key crypto.Signer) string {
+	headerBytes, _ := json.Marshal(header)
+	claimsBytes, _ := json.Marshal(claims)
+	signingInput := b64(headerBytes) + "." + b64(claimsBytes)
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.Nil(t, err)
+		signature = sig
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.Nil(t, err)
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signingInput + "." + b64(signature)
+}
+
+type jwtTestKeys struct {
+	rsaKey   *rsa.PrivateKey
+	ecKey    *ecdsa.PrivateKey
+	jwksFile string
+	pemFile  string
+}
+
+func newJWTTestKeys(t *testing.T) *jwtTestKeys {
+	dir, err := ioutil.TempDir("", "jwt")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	jwks := map[string]interface{}{
+		"keys": []map[string]interface{}{
+			{
+				"kty": "RSA",
+				"kid": "rsa-1",
+				"alg": "RS256",
+				"n":   b64(rsaKey.N.Bytes()),
+				"e":   b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+		},
+	}
+	jwksBytes, _ := json.Marshal(jwks)
+	keys := &jwtTestKeys{
+		rsaKey:   rsaKey,
+		ecKey:    ecKey,
+		jwksFile: filepath.Join(dir, "jwks.json"),
+		pemFile:  filepath.Join(dir, "key.pem"),
+	}
+	assert.Nil(t, ioutil.WriteFile(keys.jwksFile, jwksBytes, 0o644))
+	der, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(keys.pemFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+	return keys
+}
+
+func (k *jwtTestKeys) url() *common.URL {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam(constant.JWT_JWKS_FILE_KEY, k.jwksFile)
+	url.SetParam(constant.JWT_PEM_FILE_KEY, k.pemFile)
+	url.SetParam(constant.JWT_ISSUER_KEY, "https://issuer.example.com")
+	url.SetParam(constant.JWT_AUDIENCE_KEY, "user-service")
+	url.SetParam("methods.GetUser."+constant.JWT_SCOPES_KEY, "user.read")
+	return url
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "alice",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"user-service", "order-service"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "user.read user.write",
+	}
+}
+
+func newJWTInvocation(token string) protocol.Invocation {
+	return invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, map[string]interface{}{
+		constant.DEFAULT_JWT_ATTACHMENT: "Bearer " + token,
+	})
+}
+
+func TestJWTAuthenticator_AuthenticateContext(t *testing.T) {
+	keys := newJWTTestKeys(t)
+	url := keys.url()
+	authenticator := GetJWTAuthenticator().(*JWTAuthenticator)
+	rsaHeader := map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}
+
+	token := signJWT(t, rsaHeader, validClaims(), keys.rsaKey)
+	ctx, err := authenticator.AuthenticateContext(context.Background(), newJWTInvocation(token), url)
+	assert.Nil(t, err)
+	claims, ok := JWTClaimsFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "alice", claims["sub"])
+	assert.Equal(t, "alice", ctx.Value(constant.AuthApplicationKey))
+
+	// the ec key in pem file
+	token = signJWT(t, map[string]interface{}{"alg": "ES256"}, validClaims(), keys.ecKey)
+	assert.Nil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	token = signJWT(t, rsaHeader, expired, keys.rsaKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "https://evil.example.com"
+	token = signJWT(t, rsaHeader, wrongIssuer, keys.rsaKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "order-service"
+	token = signJWT(t, rsaHeader, wrongAudience, keys.rsaKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	noScope := validClaims()
+	noScope["scope"] = "user.write"
+	token = signJWT(t, rsaHeader, noScope, keys.rsaKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+	noScope["scp"] = []string{"user.read"}
+	token = signJWT(t, rsaHeader, noScope, keys.rsaKey)
+	assert.Nil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	// signed by a key which is not trusted
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	token = signJWT(t, rsaHeader, validClaims(), otherKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	token = signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "unknown"}, validClaims(), keys.rsaKey)
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	claimsBytes, _ := json.Marshal(validClaims())
+	token = b64([]byte(`{"alg":"none"}`)) + "." + b64(claimsBytes) + "."
+	assert.NotNil(t, authenticator.Authenticate(newJWTInvocation(token), url))
+
+	assert.NotNil(t, authenticator.Authenticate(invocation.NewRPCInvocation("GetUser", nil, nil), url))
+}
+
+func TestJWTAuthenticator_Sign(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	authenticator := GetJWTAuthenticator()
+
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	assert.NotNil(t, authenticator.Sign(inv, url))
+
+	url.SetParam(constant.JWT_TOKEN_KEY, "token")
+	assert.Nil(t, authenticator.Sign(inv, url))
+	assert.Equal(t, "Bearer token", inv.Attachment(constant.DEFAULT_JWT_ATTACHMENT))
+
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{
+		constant.DEFAULT_JWT_ATTACHMENT: "Bearer other",
+	})
+	assert.Nil(t, authenticator.Sign(inv, url))
+	assert.Equal(t, "Bearer other", inv.Attachment(constant.DEFAULT_JWT_ATTACHMENT))
+}
+
+type claimsInvoker struct {
+	*protocol.BaseInvoker
+	claims JWTClaims
+}
+
+func (ci *claimsInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	ci.claims, _ = JWTClaimsFromContext(ctx)
+	return &protocol.RPCResult{}
+}
+
+func TestProviderAuthFilter_InvokeWithJWT(t *testing.T) {
+	keys := newJWTTestKeys(t)
+	url := keys.url()
+	url.SetParam(constant.SERVICE_AUTH_KEY, "true")
+	url.SetParam(constant.AUTHENTICATOR_KEY, constant.JWT_AUTHENTICATOR)
+	invoker := &claimsInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	filter := &ProviderAuthFilter{}
+
+	token := signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, validClaims(), keys.rsaKey)
+	result := filter.Invoke(context.Background(), invoker, newJWTInvocation(token))
+	assert.Nil(t, result.Error())
+	assert.Equal(t, "alice", invoker.claims["sub"])
+
+	result = filter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.NotNil(t, result.Error())
+}
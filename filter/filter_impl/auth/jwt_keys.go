@@ -0,0 +1,310 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// jwtKeysReloadInterval is the interval of checking whether the key files are changed
+var jwtKeysReloadInterval = 10 * time.Second
+
+// jwtKey is a public key which verifies the signature of jwt token
+type jwtKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// jwtKeySet is the public keys loaded from a JWKS or PEM file, it will be reloaded once the file is changed
+type jwtKeySet struct {
+	path      string
+	parse     func([]byte) ([]*jwtKey, error)
+	mutex     sync.RWMutex
+	keys      []*jwtKey
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+var (
+	jwtKeySetsMutex sync.Mutex
+	jwtKeySets      = make(map[string]*jwtKeySet)
+)
+
+// getJWTKeySet returns the cached jwtKeySet of @path which is parsed by @parse
+func getJWTKeySet(path string, parse func([]byte) ([]*jwtKey, error)) (*jwtKeySet, error) {
+	jwtKeySetsMutex.Lock()
+	defer jwtKeySetsMutex.Unlock()
+	if keySet, ok := jwtKeySets[path]; ok {
+		return keySet, nil
+	}
+	keySet := &jwtKeySet{path: path, parse: parse}
+	if err := keySet.load(); err != nil {
+		return nil, err
+	}
+	jwtKeySets[path] = keySet
+	return keySet, nil
+}
+
+// find returns the keys which may verify the token signed by @alg with key id @kid
+func (s *jwtKeySet) find(kid, alg string) []crypto.PublicKey {
+	s.reloadIfChanged()
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	keys := make([]crypto.PublicKey, 0, 1)
+	for _, k := range s.keys {
+		if len(kid) > 0 && len(k.kid) > 0 && kid != k.kid {
+			continue
+		}
+		if len(k.alg) > 0 && alg != k.alg {
+			continue
+		}
+		keys = append(keys, k.key)
+	}
+	return keys
+}
+
+func (s *jwtKeySet) load() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	content, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	keys, err := s.parse(content)
+	if err != nil {
+		return perrors.WithMessagef(err, "parse keys in %s", s.path)
+	}
+	s.mutex.Lock()
+	s.keys = keys
+	s.modTime = info.ModTime()
+	s.lastCheck = time.Now()
+	s.mutex.Unlock()
+	return nil
+}
+
+// reloadIfChanged reloads the keys if the file is modified, the keys in use are kept if the file is broken
+func (s *jwtKeySet) reloadIfChanged() {
+	s.mutex.Lock()
+	if time.Since(s.lastCheck) < jwtKeysReloadInterval {
+		s.mutex.Unlock()
+		return
+	}
+	s.lastCheck = time.Now()
+	modTime := s.modTime
+	s.mutex.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		return
+	}
+	_ = s.load()
+}
+
+// parseJWKS parses the keys in JSON Web Key Set format
+func parseJWKS(content []byte) ([]*jwtKey, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &jwks); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	keys := make([]*jwtKey, 0, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if len(k.Use) > 0 && k.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		switch k.Kty {
+		case "RSA":
+			n, err := decodeJWKBigInt(k.N)
+			if err != nil {
+				return nil, err
+			}
+			e, err := decodeJWKBigInt(k.E)
+			if err != nil {
+				return nil, err
+			}
+			key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				return nil, perrors.Errorf("unsupported curve %s of key %s", k.Crv, k.Kid)
+			}
+			x, err := decodeJWKBigInt(k.X)
+			if err != nil {
+				return nil, err
+			}
+			y, err := decodeJWKBigInt(k.Y)
+			if err != nil {
+				return nil, err
+			}
+			key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		case "OKP":
+			if k.Crv != "Ed25519" {
+				return nil, perrors.Errorf("unsupported curve %s of key %s", k.Crv, k.Kid)
+			}
+			x, err := base64.RawURLEncoding.DecodeString(k.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, perrors.Errorf("invalid ed25519 key %s", k.Kid)
+			}
+			key = ed25519.PublicKey(x)
+		default:
+			return nil, perrors.Errorf("unsupported key type %s of key %s", k.Kty, k.Kid)
+		}
+		keys = append(keys, &jwtKey{kid: k.Kid, alg: k.Alg, key: key})
+	}
+	return keys, nil
+}
+
+func decodeJWKBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// parsePEMKeys parses the public keys and certificates in PEM format
+func parsePEMKeys(content []byte) ([]*jwtKey, error) {
+	keys := make([]*jwtKey, 0, 1)
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			break
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		keys = append(keys, &jwtKey{key: key})
+	}
+	if len(keys) == 0 {
+		return nil, perrors.New("no public key is found")
+	}
+	return keys, nil
+}
+
+// verifyJWTSignature verifies the signature of @signingInput by the algorithm @alg
+func verifyJWTSignature(alg string, key crypto.PublicKey, signingInput, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok || !ed25519.Verify(edKey, signingInput, signature) {
+			return perrors.New("invalid signature")
+		}
+		return nil
+	default:
+		return perrors.Errorf("unsupported algorithm %s", alg)
+	}
+	hasher := hash.New()
+	hasher.Write(signingInput)
+	digest := hasher.Sum(nil)
+
+	switch alg[0] {
+	case 'R':
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return perrors.New("the key is not rsa key")
+		}
+		return perrors.WithStack(rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature))
+	case 'P':
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return perrors.New("the key is not rsa key")
+		}
+		return perrors.WithStack(rsa.VerifyPSS(rsaKey, hash, digest, signature,
+			&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}))
+	default:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return perrors.New("the key is not ecdsa key")
+		}
+		// the signature is the concatenation of r and s in fixed size
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return perrors.New("invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return perrors.New("invalid signature")
+		}
+		return nil
+	}
+}
@@ -42,6 +42,13 @@ func (paf *ProviderAuthFilter) Invoke(ctx context.Context, invoker protocol.Invo
 	url := invoker.GetURL()
 
 	err := doAuthWork(url, func(authenticator filter.Authenticator) error {
+		if ctxAuthenticator, ok := authenticator.(filter.ContextAuthenticator); ok {
+			authCtx, err := ctxAuthenticator.AuthenticateContext(ctx, invocation, url)
+			if err == nil {
+				ctx = authCtx
+			}
+			return err
+		}
 		return authenticator.Authenticate(invocation, url)
 	})
 	if err != nil {
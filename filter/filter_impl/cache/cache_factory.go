@@ -0,0 +1,44 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// baseCacheFactory keeps one Cache for each method of each service
+type baseCacheFactory struct {
+	caches sync.Map
+	create func(url *common.URL, methodName string) filter.Cache
+}
+
+// GetCache returns the Cache of the method, it will be created at the first time
+func (factory *baseCacheFactory) GetCache(url *common.URL, invocation protocol.Invocation) filter.Cache {
+	key := url.ServiceKey() + "." + invocation.MethodName()
+	if cache, ok := factory.caches.Load(key); ok {
+		return cache.(filter.Cache)
+	}
+	cache, _ := factory.caches.LoadOrStore(key, factory.create(url, invocation.MethodName()))
+	return cache.(filter.Cache)
+}
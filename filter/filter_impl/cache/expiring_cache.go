@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	expiringName          = "expiring"
+	defaultExpiringSecond = 180
+)
+
+var expiringCacheFactory = &baseCacheFactory{create: func(url *common.URL, methodName string) filter.Cache {
+	seconds := url.GetMethodParamInt64(methodName, constant.CACHE_SECONDS_KEY,
+		url.GetParamInt(constant.CACHE_SECONDS_KEY, defaultExpiringSecond))
+	size := url.GetMethodParamInt64(methodName, constant.CACHE_SIZE_KEY,
+		url.GetParamInt(constant.CACHE_SIZE_KEY, 0))
+	return NewExpiringCache(time.Duration(seconds)*time.Second, size)
+}}
+
+func init() {
+	extension.SetCacheFactory(expiringName, GetExpiringCacheFactory)
+}
+
+// ExpiringCache drops the values once they have lived longer than the ttl,
+// the expired values are purged when they are read or when the values are written after a ttl.
+type ExpiringCache struct {
+	mutex     sync.Mutex
+	ttl       time.Duration
+	size      int64
+	entries   map[string]*expiringEntry
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+type expiringEntry struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+// NewExpiringCache creates the ExpiringCache with @ttl, it holds at most @size values if @size is positive
+func NewExpiringCache(ttl time.Duration, size int64) *ExpiringCache {
+	return &ExpiringCache{
+		ttl:       ttl,
+		size:      size,
+		entries:   make(map[string]*expiringEntry),
+		lastPurge: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Get returns the value cached with @key if it's not expired
+func (c *ExpiringCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expireAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Put caches @value with @key, the value is dropped if the cache is full of unexpired values
+func (c *ExpiringCache) Put(key string, value interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	if now.Sub(c.lastPurge) >= c.ttl {
+		c.purge(now)
+	}
+	if _, ok := c.entries[key]; !ok && c.size > 0 && int64(len(c.entries)) >= c.size {
+		c.purge(now)
+		if int64(len(c.entries)) >= c.size {
+			return
+		}
+	}
+	c.entries[key] = &expiringEntry{value: value, expireAt: now.Add(c.ttl)}
+}
+
+// Len returns the number of cached values including the expired ones which are not purged
+func (c *ExpiringCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+func (c *ExpiringCache) purge(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expireAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.lastPurge = now
+}
+
+// GetExpiringCacheFactory returns the factory of ExpiringCache
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   methods:
+ *     - name: "GetUser"
+ *       cache: "expiring"
+ *       cache.seconds: 180 # the seconds the results live, 180 by default
+ *       cache.size: 1000 # the max number of cached results, unlimited by default
+ */
+func GetExpiringCacheFactory() filter.CacheFactory {
+	return expiringCacheFactory
+}
@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiringCache(t *testing.T) {
+	now := time.Now()
+	cache := NewExpiringCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	v, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// the cache is full of unexpired values
+	cache.Put("c", 3)
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+
+	now = now.Add(30 * time.Second)
+	cache.Put("a", 4)
+	now = now.Add(40 * time.Second)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	v, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	// the expired values are purged
+	now = now.Add(time.Minute)
+	cache.Put("c", 3)
+	assert.Equal(t, 1, cache.Len())
+}
@@ -0,0 +1,155 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+)
+
+const (
+	lruName         = "lru"
+	defaultLRUSize  = 1000
+	defaultLRUBytes = 64 * 1024 * 1024
+	sizedName       = "sized"
+)
+
+var (
+	lruCacheFactory = &baseCacheFactory{create: func(url *common.URL, methodName string) filter.Cache {
+		size := url.GetMethodParamInt64(methodName, constant.CACHE_SIZE_KEY,
+			url.GetParamInt(constant.CACHE_SIZE_KEY, defaultLRUSize))
+		return NewLRUCache(size, nil)
+	}}
+	sizedCacheFactory = &baseCacheFactory{create: func(url *common.URL, methodName string) filter.Cache {
+		bytes := url.GetMethodParamInt64(methodName, constant.CACHE_BYTES_KEY,
+			url.GetParamInt(constant.CACHE_BYTES_KEY, defaultLRUBytes))
+		return NewLRUCache(bytes, estimateSize)
+	}}
+)
+
+func init() {
+	extension.SetCacheFactory(lruName, GetLRUCacheFactory)
+	extension.SetCacheFactory(sizedName, GetSizedCacheFactory)
+}
+
+// LRUCache evicts the least recently used values once the total weight of values exceeds the capacity
+type LRUCache struct {
+	mutex    sync.Mutex
+	capacity int64
+	weight   int64
+	weigh    func(interface{}) int64
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key    string
+	value  interface{}
+	weight int64
+}
+
+// NewLRUCache creates the LRUCache with @capacity, @weigh returns the weight of a value and each value weighs 1 if @weigh is nil
+func NewLRUCache(capacity int64, weigh func(interface{}) int64) *LRUCache {
+	if weigh == nil {
+		weigh = func(interface{}) int64 { return 1 }
+	}
+	return &LRUCache{
+		capacity: capacity,
+		weigh:    weigh,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value cached with @key and marks it as the most recently used
+func (c *LRUCache) Get(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+// Put caches @value with @key, the value heavier than the capacity is never cached
+func (c *LRUCache) Put(key string, value interface{}) {
+	weight := c.weigh(value)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	if weight > c.capacity {
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, weight: weight})
+	c.weight += weight
+	for c.weight > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of cached values
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*lruEntry)
+	delete(c.entries, entry.key)
+	c.weight -= entry.weight
+}
+
+// GetLRUCacheFactory returns the factory of LRUCache bounded by the number of values
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   methods:
+ *     - name: "GetUser"
+ *       cache: "lru"
+ *       cache.size: 1000 # the max number of cached results, 1000 by default
+ */
+func GetLRUCacheFactory() filter.CacheFactory {
+	return lruCacheFactory
+}
+
+// GetSizedCacheFactory returns the factory of LRUCache bounded by the estimated bytes of values
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   methods:
+ *     - name: "GetUser"
+ *       cache: "sized"
+ *       cache.bytes: 67108864 # the max bytes of cached results, 64MB by default
+ */
+func GetSizedCacheFactory() filter.CacheFactory {
+	return sizedCacheFactory
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2, nil)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	v, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	// b is the least recently used
+	cache.Put("c", 3)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Put("a", 4)
+	v, _ = cache.Get("a")
+	assert.Equal(t, 4, v)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestSizedCache(t *testing.T) {
+	cache := NewLRUCache(100, estimateSize)
+	cache.Put("a", make([]byte, 40))
+	cache.Put("b", make([]byte, 40))
+	assert.Equal(t, 1, cache.Len())
+	_, ok := cache.Get("b")
+	assert.True(t, ok)
+
+	// the value heavier than the capacity is never cached
+	cache.Put("c", make([]byte, 200))
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestEstimateSize(t *testing.T) {
+	type user struct {
+		Name string
+		Tags []string
+	}
+	assert.Equal(t, int64(0), estimateSize(nil))
+	assert.Equal(t, int64(8), estimateSize(int64(1)))
+	assert.Equal(t, int64(16+5), estimateSize("hello"))
+	u := &user{Name: "alice", Tags: []string{"a", "bc"}}
+	assert.Equal(t, int64(8+40+5+2*16+3), estimateSize(u))
+	// the shared memory is counted once
+	assert.Equal(t, int64(24+2*8+40+5+2*16+3), estimateSize([]*user{u, u}))
+}
+
+func TestCacheFactory(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam("methods.GetUser."+constant.CACHE_SIZE_KEY, "1")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	factory, ok := extension.GetCacheFactory(lruName)
+	assert.True(t, ok)
+	cache := factory.GetCache(url, inv)
+	assert.Equal(t, cache, factory.GetCache(url, inv))
+	assert.NotEqual(t, cache, factory.GetCache(url, invocation.NewRPCInvocation("GetUsers", nil, nil)))
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	assert.Equal(t, 1, cache.(*LRUCache).Len())
+
+	factory, _ = extension.GetCacheFactory(sizedName)
+	assert.IsType(t, &LRUCache{}, factory.GetCache(url, inv))
+	factory, _ = extension.GetCacheFactory(expiringName)
+	assert.IsType(t, &ExpiringCache{}, factory.GetCache(url, inv))
+	_, ok = extension.GetCacheFactory("unknown")
+	assert.False(t, ok)
+}
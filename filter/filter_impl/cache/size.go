@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"reflect"
+)
+
+// estimateSize returns the approximate bytes of memory referenced by @value,
+// the memory shared by several pointers is counted once.
+func estimateSize(value interface{}) int64 {
+	if value == nil {
+		return 0
+	}
+	v := reflect.ValueOf(value)
+	return int64(v.Type().Size()) + estimateIndirectSize(v, make(map[uintptr]struct{}))
+}
+
+// estimateIndirectSize returns the bytes of memory referenced by @v besides itself
+func estimateIndirectSize(v reflect.Value, visited map[uintptr]struct{}) int64 {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || isVisited(v.Pointer(), visited) {
+			return 0
+		}
+		return int64(v.Type().Elem().Size()) + estimateIndirectSize(v.Elem(), visited)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + estimateIndirectSize(elem, visited)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() || isVisited(v.Pointer(), visited) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += estimateIndirectSize(v.Index(i), visited)
+		}
+		return size
+	case reflect.Array:
+		size := int64(0)
+		for i := 0; i < v.Len(); i++ {
+			size += estimateIndirectSize(v.Index(i), visited)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() || isVisited(v.Pointer(), visited) {
+			return 0
+		}
+		size := int64(0)
+		iter := v.MapRange()
+		for iter.Next() {
+			key, value := iter.Key(), iter.Value()
+			size += int64(key.Type().Size()+value.Type().Size()) +
+				estimateIndirectSize(key, visited) + estimateIndirectSize(value, visited)
+		}
+		return size
+	case reflect.Struct:
+		size := int64(0)
+		for i := 0; i < v.NumField(); i++ {
+			size += estimateIndirectSize(v.Field(i), visited)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+func isVisited(pointer uintptr, visited map[uintptr]struct{}) bool {
+	if _, ok := visited[pointer]; ok {
+		return true
+	}
+	visited[pointer] = struct{}{}
+	return false
+}
@@ -0,0 +1,321 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	_ "dubbo.apache.org/dubbo-go/v3/filter/filter_impl/cache"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var cacheFilter = &CacheFilter{}
+
+func init() {
+	extension.SetFilter(constant.CACHE_FILTER, GetCacheFilter)
+}
+
+// CacheFilter returns the cached result of the method if it has been invoked with the same arguments,
+// it works on both consumer and provider side, and only the successful results are cached.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   filter: "cache"
+ *   methods:
+ *     - name: "GetUser"
+ *       cache: "lru" # the name of cache factory, lru, sized and expiring are provided
+ *       cache.size: 1000
+ */
+type CacheFilter struct{}
+
+// Invoke returns the cached result if it exists, otherwise invokes the invoker and caches the result
+func (f *CacheFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	cacheName := url.GetMethodParam(methodName, constant.CACHE_KEY, url.GetParam(constant.CACHE_KEY, ""))
+	if len(cacheName) == 0 || strings.EqualFold(cacheName, "false") {
+		return invoker.Invoke(ctx, invocation)
+	}
+	factory, ok := extension.GetCacheFactory(cacheName)
+	if !ok {
+		return &protocol.RPCResult{Err: perrors.Errorf("the cache %s of method %s is not existing, make sure you have "+
+			"import the package and you have register it by invoking extension.SetCacheFactory", cacheName, methodName)}
+	}
+	key, err := getCacheKey(invocation)
+	if err != nil {
+		logger.Debugf("The result of %s is not cached since the arguments can not be hashed, error: %v", methodName, err)
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	cache := factory.GetCache(url, invocation)
+	if value, ok := cache.Get(key); ok {
+		if result, ok := value.(*cachedResult).toResult(invocation); ok {
+			return result
+		}
+	}
+
+	result := invoker.Invoke(ctx, invocation)
+	if result.Error() == nil {
+		cache.Put(key, newCachedResult(result))
+	}
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *CacheFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// GetCacheFilter returns the singleton CacheFilter
+func GetCacheFilter() filter.Filter {
+	return cacheFilter
+}
+
+// getCacheKey returns the method name with the hash of the types and values of arguments,
+// the unexported fields are hashed too and the entries of maps are hashed in a stable order
+func getCacheKey(invocation protocol.Invocation) (string, error) {
+	h := sha256.New()
+	for _, arg := range invocation.Arguments() {
+		if err := writeKey(h, reflect.ValueOf(arg), make(map[uintptr]bool)); err != nil {
+			return "", err
+		}
+	}
+	return invocation.MethodName() + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// writeKey writes the type name and the value of @v into @h, @visiting guards against the cyclic pointers
+func writeKey(h hash.Hash, v reflect.Value, visiting map[uintptr]bool) error {
+	if !v.IsValid() {
+		_, err := h.Write([]byte("nil;"))
+		return err
+	}
+	fmt.Fprintf(h, "%s(", v.Type().String())
+	switch v.Kind() {
+	case reflect.Bool:
+		fmt.Fprintf(h, "%t", v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(h, "%d", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprintf(h, "%d", v.Uint())
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		fmt.Fprintf(h, "%v", v)
+	case reflect.String:
+		fmt.Fprintf(h, "%d:%s", v.Len(), v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			break
+		}
+		if v.Kind() == reflect.Ptr {
+			if visiting[v.Pointer()] {
+				return perrors.Errorf("the cyclic pointer of %s can not be hashed", v.Type())
+			}
+			visiting[v.Pointer()] = true
+			defer delete(visiting, v.Pointer())
+		}
+		if err := writeKey(h, v.Elem(), visiting); err != nil {
+			return err
+		}
+	case reflect.Array, reflect.Slice:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			break
+		}
+		fmt.Fprintf(h, "%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if err := writeKey(h, v.Index(i), visiting); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			break
+		}
+		entries := make([]string, 0, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			entry := sha256.New()
+			if err := writeKey(entry, iter.Key(), visiting); err != nil {
+				return err
+			}
+			if err := writeKey(entry, iter.Value(), visiting); err != nil {
+				return err
+			}
+			entries = append(entries, string(entry.Sum(nil)))
+		}
+		sort.Strings(entries)
+		fmt.Fprintf(h, "%d:", len(entries))
+		for _, entry := range entries {
+			h.Write([]byte(entry))
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			t := v.Interface().(time.Time)
+			fmt.Fprintf(h, "%d@%s", t.UnixNano(), t.Location())
+			break
+		}
+		v = addressable(v)
+		for i := 0; i < v.NumField(); i++ {
+			fmt.Fprintf(h, "%s=", v.Type().Field(i).Name)
+			if err := writeKey(h, exported(v.Field(i)), visiting); err != nil {
+				return err
+			}
+		}
+	default:
+		return perrors.Errorf("the value of %s can not be hashed", v.Type())
+	}
+	_, err := h.Write([]byte(");"))
+	return err
+}
+
+// addressable returns @v itself if it's addressable, otherwise an addressable copy of @v
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	return copied
+}
+
+// exported returns the settable view of the addressable field @v even if it's unexported
+func exported(v reflect.Value) reflect.Value {
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// deepCopy returns the copy of @v which shares no memory with @v except for the chans and funcs
+func deepCopy(v reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	if !v.IsValid() {
+		return v
+	}
+	dst := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			break
+		}
+		if ptr, ok := copied[v.Pointer()]; ok {
+			return ptr
+		}
+		ptr := reflect.New(v.Type().Elem())
+		copied[v.Pointer()] = ptr
+		ptr.Elem().Set(deepCopy(v.Elem(), copied))
+		dst.Set(ptr)
+	case reflect.Interface:
+		if !v.IsNil() {
+			dst.Set(deepCopy(v.Elem(), copied))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+		dst.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			dst.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			break
+		}
+		dst.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		for iter := v.MapRange(); iter.Next(); {
+			dst.SetMapIndex(deepCopy(iter.Key(), copied), deepCopy(iter.Value(), copied))
+		}
+	case reflect.Struct:
+		// time.Time is immutable, and its location must not be copied
+		if v.Type() == timeType {
+			dst.Set(v)
+			break
+		}
+		v = addressable(v)
+		for i := 0; i < v.NumField(); i++ {
+			exported(dst.Field(i)).Set(deepCopy(exported(v.Field(i)), copied))
+		}
+	default:
+		dst.Set(v)
+	}
+	return dst
+}
+
+// cachedResult is the successful result of an invocation
+type cachedResult struct {
+	value       interface{}
+	attachments map[string]interface{}
+}
+
+// newCachedResult deep copies the result, so that the cached value won't be changed by the caller
+func newCachedResult(result protocol.Result) *cachedResult {
+	cached := &cachedResult{}
+	if value := result.Result(); value != nil {
+		cached.value = deepCopy(reflect.ValueOf(value), make(map[uintptr]reflect.Value)).Interface()
+	}
+	if attachments := result.Attachments(); len(attachments) > 0 {
+		cached.attachments = make(map[string]interface{}, len(attachments))
+		for k, v := range attachments {
+			cached.attachments[k] = v
+		}
+	}
+	return cached
+}
+
+// toResult builds the result of @invocation with a deep copy of the cached value,
+// the copy is set into the reply of consumer
+func (c *cachedResult) toResult(invocation protocol.Invocation) (protocol.Result, bool) {
+	var value interface{}
+	if c.value != nil {
+		value = deepCopy(reflect.ValueOf(c.value), make(map[uintptr]reflect.Value)).Interface()
+	}
+	result := &protocol.RPCResult{Rest: value}
+	if reply := invocation.Reply(); reply != nil {
+		replyValue, cachedValue := reflect.ValueOf(reply), reflect.ValueOf(value)
+		if replyValue.Kind() != reflect.Ptr || replyValue.IsNil() ||
+			cachedValue.Kind() != reflect.Ptr || cachedValue.IsNil() ||
+			!cachedValue.Elem().Type().AssignableTo(replyValue.Elem().Type()) {
+			return nil, false
+		}
+		replyValue.Elem().Set(cachedValue.Elem())
+		result.Rest = reply
+	}
+	for k, v := range c.attachments {
+		result.AddAttachment(k, v)
+	}
+	return result, true
+}
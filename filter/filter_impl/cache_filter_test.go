@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type cacheTestUser struct {
+	ID   string
+	Name string
+}
+
+type countingInvoker struct {
+	*protocol.BaseInvoker
+	count int
+	err   error
+}
+
+func (ci *countingInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	ci.count++
+	if ci.err != nil {
+		return &protocol.RPCResult{Err: ci.err}
+	}
+	user := &cacheTestUser{ID: inv.Arguments()[0].(string), Name: "alice"}
+	if reply, ok := inv.Reply().(*cacheTestUser); ok {
+		*reply = *user
+		user = reply
+	}
+	result := &protocol.RPCResult{Rest: user}
+	result.AddAttachment("count", ci.count)
+	return result
+}
+
+func TestCacheFilter_Invoke(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam("methods.GetUser."+constant.CACHE_KEY, "lru")
+	invoker := &countingInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	cacheFilter := GetCacheFilter()
+
+	// provider side
+	result := cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil))
+	assert.Nil(t, result.Error())
+	result = cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil))
+	assert.Equal(t, &cacheTestUser{ID: "1", Name: "alice"}, result.Result())
+	assert.Equal(t, 1, result.Attachment("count", 0))
+	assert.Equal(t, 1, invoker.count)
+
+	result = cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"2"}, nil))
+	assert.Equal(t, "2", result.Result().(*cacheTestUser).ID)
+	assert.Equal(t, 2, invoker.count)
+
+	// consumer side, the cached result is copied into the reply
+	reply := &cacheTestUser{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"1"}), invocation.WithReply(reply))
+	result = cacheFilter.Invoke(context.Background(), invoker, inv)
+	assert.Equal(t, 2, invoker.count)
+	assert.Equal(t, reply, result.Result())
+	assert.Equal(t, "alice", reply.Name)
+
+	// the method without cache
+	cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUsers", []interface{}{"1"}, nil))
+	cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUsers", []interface{}{"1"}, nil))
+	assert.Equal(t, 4, invoker.count)
+
+	// the errored results are never cached
+	invoker.err = errors.New("error")
+	cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"3"}, nil))
+	result = cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"3"}, nil))
+	assert.NotNil(t, result.Error())
+	assert.Equal(t, 6, invoker.count)
+}
+
+func TestGetCacheKey(t *testing.T) {
+	key1, err := getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{int32(1)}, nil))
+	assert.Nil(t, err)
+	key2, _ := getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{int64(1)}, nil))
+	assert.NotEqual(t, key1, key2)
+	key3, _ := getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{int32(1)}, nil))
+	assert.Equal(t, key1, key3)
+
+	_, err = getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{make(chan int)}, nil))
+	assert.NotNil(t, err)
+}
+
+type cacheTestKey struct {
+	name  string
+	attrs map[string]int
+}
+
+func TestGetCacheKeyUnexportedFields(t *testing.T) {
+	key1, err := getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{cacheTestKey{name: "a"}}, nil))
+	assert.Nil(t, err)
+	key2, _ := getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{cacheTestKey{name: "b"}}, nil))
+	assert.NotEqual(t, key1, key2)
+
+	attrs := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		attrs[string(rune('a'+i%26))+string(rune('a'+i/26))] = i
+	}
+	key1, _ = getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{&cacheTestKey{attrs: attrs}}, nil))
+	for i := 0; i < 10; i++ {
+		key2, _ = getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{&cacheTestKey{attrs: attrs}}, nil))
+		assert.Equal(t, key1, key2)
+	}
+	key2, _ = getCacheKey(invocation.NewRPCInvocation("GetUser", []interface{}{cacheTestKey{attrs: attrs}}, nil))
+	assert.NotEqual(t, key1, key2)
+}
+
+type cacheTestReply struct {
+	Users []*cacheTestUser
+	tags  map[string]string
+}
+
+type sliceInvoker struct {
+	*protocol.BaseInvoker
+	count int
+}
+
+func (si *sliceInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	si.count++
+	return &protocol.RPCResult{Rest: &cacheTestReply{
+		Users: []*cacheTestUser{{ID: "1", Name: "alice"}},
+		tags:  map[string]string{"k": "v"},
+	}}
+}
+
+func TestCacheFilter_DeepCopy(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam("methods.GetUsers."+constant.CACHE_KEY, "lru")
+	invoker := &sliceInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	cacheFilter := GetCacheFilter()
+
+	result := cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUsers", nil, nil))
+	// changing the returned result changes neither the cache nor the later results
+	first := result.Result().(*cacheTestReply)
+	first.Users[0].Name = "bob"
+	first.tags["k"] = "changed"
+	result = cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUsers", nil, nil))
+	second := result.Result().(*cacheTestReply)
+	assert.Equal(t, "alice", second.Users[0].Name)
+	assert.Equal(t, "v", second.tags["k"])
+	second.Users[0].Name = "carol"
+	result = cacheFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUsers", nil, nil))
+	assert.Equal(t, "alice", result.Result().(*cacheTestReply).Users[0].Name)
+	assert.Equal(t, 1, invoker.count)
+}
+
+func TestCacheFilter_UnknownCache(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam("methods.GetUser."+constant.CACHE_KEY, "unknown")
+	invoker := &countingInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	result := GetCacheFilter().Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil))
+	assert.NotNil(t, result.Error())
+	assert.Equal(t, 0, invoker.count)
+}
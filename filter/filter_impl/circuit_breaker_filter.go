@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var circuitBreakerFilter = &CircuitBreakerFilter{}
+
+func init() {
+	extension.SetFilter(constant.CIRCUIT_BREAKER_FILTER, GetCircuitBreakerFilter)
+}
+
+// CircuitBreakerFilter rejects the invocations to an invoker whose circuit breaker is open.
+// The breaker is shared by all methods of the invoker by default, or created for each method with method scope.
+// The cluster invokers also skip the invokers with open breakers when selecting.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   filter: "circuitbreaker"
+ *   params:
+ *     "circuitbreaker.scope": "invoker" # or method
+ *     "circuitbreaker.window.type": "count" # or time
+ *     "circuitbreaker.window.size": "100" # calls of count window or seconds of time window
+ *     "circuitbreaker.minimum.calls": "20"
+ *     "circuitbreaker.error.rate": "50" # percentage
+ *     "circuitbreaker.slow.rate": "100" # percentage
+ *     "circuitbreaker.slow.duration": "60s"
+ *     "circuitbreaker.open.duration": "30s"
+ *     "circuitbreaker.halfopen.calls": "10"
+ */
+type CircuitBreakerFilter struct{}
+
+// Invoke rejects the invocation if the breaker is open, or records the outcome of the invocation
+func (f *CircuitBreakerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	breaker := protocol.GetCircuitBreaker(invoker.GetURL(), invocation.MethodName(), publishCircuitStateChange)
+	if !breaker.Allow() {
+		return &protocol.RPCResult{
+			Err: perrors.Wrapf(protocol.ErrCircuitBreakerOpen, "the invocation of %s to %s is rejected",
+				invocation.MethodName(), breaker.Key()),
+		}
+	}
+	start := time.Now()
+	failed := true
+	// the permitted call is always reported, or a half-open breaker would wait for it forever if it panics
+	defer func() {
+		breaker.OnResult(time.Since(start), failed)
+	}()
+	result := invoker.Invoke(ctx, invocation)
+	failed = result.Error() != nil
+	return result
+}
+
+// OnResponse does nothing
+func (f *CircuitBreakerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// publishCircuitStateChange logs the state change and publishes it by the global event dispatcher
+func publishCircuitStateChange(event *protocol.CircuitBreakerStateChangedEvent) {
+	logger.Warnf("circuit breaker of %s changes from %s to %s", event.Key, event.From, event.To)
+	if dispatcher := extension.GetGlobalDispatcher(); dispatcher != nil {
+		dispatcher.Dispatch(event)
+	}
+}
+
+// GetCircuitBreakerFilter returns the singleton CircuitBreakerFilter
+func GetCircuitBreakerFilter() filter.Filter {
+	return circuitBreakerFilter
+}
@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+	_ "dubbo.apache.org/dubbo-go/v3/common/observer/dispatcher"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type circuitStateListener struct {
+	events []*protocol.CircuitBreakerStateChangedEvent
+}
+
+func (l *circuitStateListener) GetPriority() int {
+	return 0
+}
+
+func (l *circuitStateListener) OnEvent(e observer.Event) error {
+	l.events = append(l.events, e.(*protocol.CircuitBreakerStateChangedEvent))
+	return nil
+}
+
+func (l *circuitStateListener) GetEventType() reflect.Type {
+	return reflect.TypeOf(&protocol.CircuitBreakerStateChangedEvent{})
+}
+
+func TestCircuitBreakerFilter_Invoke(t *testing.T) {
+	defer protocol.CleanAllCircuitBreakers()
+	extension.SetAndInitGlobalDispatcher("direct")
+	listener := &circuitStateListener{}
+	extension.GetGlobalDispatcher().AddEventListener(listener)
+	defer extension.GetGlobalDispatcher().RemoveEventListener(listener)
+
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"circuitbreaker.minimum.calls=2&circuitbreaker.error.rate=50")
+	failInvoker := &testMockFailInvoker{*protocol.NewBaseInvoker(url)}
+	successInvoker := &testMockSuccessInvoker{*protocol.NewBaseInvoker(url)}
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	f := GetCircuitBreakerFilter()
+
+	assert.Nil(t, f.Invoke(context.Background(), successInvoker, inv).Error())
+	assert.False(t, protocol.IsCircuitBreakerOpen(url, "GetUser"))
+	result := f.Invoke(context.Background(), failInvoker, inv)
+	assert.NotNil(t, result.Error())
+	assert.NotEqual(t, protocol.ErrCircuitBreakerOpen, perrors.Cause(result.Error()))
+	assert.True(t, protocol.IsCircuitBreakerOpen(url, "GetUser"))
+
+	// the breaker is shared by all methods of the invoker
+	result = f.Invoke(context.Background(), successInvoker, invocation.NewRPCInvocation("Save", nil, nil))
+	assert.Equal(t, protocol.ErrCircuitBreakerOpen, perrors.Cause(result.Error()))
+
+	assert.Len(t, listener.events, 1)
+	assert.Equal(t, url.Key(), listener.events[0].Key)
+	assert.Equal(t, protocol.CircuitClosed, listener.events[0].From)
+	assert.Equal(t, protocol.CircuitOpen, listener.events[0].To)
+}
+
+type testMockPanicInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (iv *testMockPanicInvoker) Invoke(_ context.Context, _ protocol.Invocation) protocol.Result {
+	panic("invoke panic")
+}
+
+func TestCircuitBreakerFilterPanicInHalfOpen(t *testing.T) {
+	defer protocol.CleanAllCircuitBreakers()
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?" +
+		"circuitbreaker.minimum.calls=1&circuitbreaker.open.duration=10ms&circuitbreaker.halfopen.calls=1")
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	f := GetCircuitBreakerFilter()
+
+	f.Invoke(context.Background(), &testMockFailInvoker{*protocol.NewBaseInvoker(url)}, inv)
+	assert.True(t, protocol.IsCircuitBreakerOpen(url, "GetUser"))
+	time.Sleep(20 * time.Millisecond)
+
+	// the panicking probe is recorded as a failure instead of holding the half-open permit forever
+	assert.Panics(t, func() {
+		f.Invoke(context.Background(), &testMockPanicInvoker{*protocol.NewBaseInvoker(url)}, inv)
+	})
+	assert.True(t, protocol.IsCircuitBreakerOpen(url, "GetUser"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(t, f.Invoke(context.Background(), &testMockSuccessInvoker{*protocol.NewBaseInvoker(url)}, inv).Error())
+	assert.False(t, protocol.IsCircuitBreakerOpen(url, "GetUser"))
+}
@@ -0,0 +1,199 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	otelInstrumentationName = "dubbo.apache.org/dubbo-go/v3"
+)
+
+// otelPropagator propagates the W3C trace context and baggage through the attachments
+var otelPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// the filters record the spans by the global TracerProvider and the durations by the global MeterProvider,
+// so please set your providers by otel.SetTracerProvider and global.SetMeterProvider
+func init() {
+	meter := metric.Must(global.Meter(otelInstrumentationName))
+	consumerFilter := &otelConsumerFilter{
+		duration: meter.NewFloat64Histogram("rpc.client.duration", metric.WithUnit(unit.Milliseconds),
+			metric.WithDescription("The duration of outbound RPC")),
+	}
+	providerFilter := &otelProviderFilter{
+		duration: meter.NewFloat64Histogram("rpc.server.duration", metric.WithUnit(unit.Milliseconds),
+			metric.WithDescription("The duration of inbound RPC")),
+	}
+
+	extension.SetFilter(constant.CONSUMER_OTEL_FILTER, func() filter.Filter {
+		return consumerFilter
+	})
+
+	extension.SetFilter(constant.PROVIDER_OTEL_FILTER, func() filter.Filter {
+		return providerFilter
+	})
+}
+
+// otelConsumerFilter creates the client span of OpenTelemetry and injects the trace context into the attachments
+type otelConsumerFilter struct {
+	duration metric.Float64Histogram
+}
+
+// Invoke starts a client span as the child of the span in @ctx
+func (f *otelConsumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	attrs := otelRPCAttributes(url, invocation)
+	spanAttrs := append(attrs, otelAddressAttribute(url.Ip, semconv.NetPeerIPKey, semconv.NetPeerNameKey))
+	if port, err := strconv.Atoi(url.Port); err == nil {
+		spanAttrs = append(spanAttrs, semconv.NetPeerPortKey.Int(port))
+	}
+
+	ctx, span := otel.Tracer(otelInstrumentationName).Start(ctx, otelSpanName(url, invocation),
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(spanAttrs...))
+	defer span.End()
+	otelPropagator.Inject(ctx, &attachmentsCarrier{invocation: invocation})
+
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	f.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attrs...)
+	otelRecordError(span, result)
+	return result
+}
+
+// OnResponse does nothing
+func (f *otelConsumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// otelProviderFilter extracts the trace context from the attachments and creates the server span of OpenTelemetry
+type otelProviderFilter struct {
+	duration metric.Float64Histogram
+}
+
+// Invoke starts a server span as the child of the remote span, the baggage is available in the ctx of the service
+func (f *otelProviderFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	attrs := otelRPCAttributes(url, invocation)
+	spanAttrs := append(attrs, otelAddressAttribute(url.Ip, semconv.NetHostIPKey, semconv.NetHostNameKey))
+	if port, err := strconv.Atoi(url.Port); err == nil {
+		spanAttrs = append(spanAttrs, semconv.NetHostPortKey.Int(port))
+	}
+
+	ctx = otelPropagator.Extract(ctx, &attachmentsCarrier{invocation: invocation})
+	ctx, span := otel.Tracer(otelInstrumentationName).Start(ctx, otelSpanName(url, invocation),
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(spanAttrs...))
+	defer span.End()
+
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	f.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), attrs...)
+	otelRecordError(span, result)
+	return result
+}
+
+// OnResponse does nothing
+func (f *otelProviderFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// otelSpanName returns the span name in the format of $service/$method
+func otelSpanName(url *common.URL, invocation protocol.Invocation) string {
+	return url.Service() + "/" + invocation.MethodName()
+}
+
+// otelRPCAttributes returns the attributes of the RPC semantic conventions
+func otelRPCAttributes(url *common.URL, invocation protocol.Invocation) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.RPCSystemKey.String(url.Protocol),
+		semconv.RPCServiceKey.String(url.Service()),
+		semconv.RPCMethodKey.String(invocation.MethodName()),
+	}
+}
+
+// otelAddressAttribute returns the attribute @ipKey if @host is an ip, or @nameKey if it's a host name
+func otelAddressAttribute(host string, ipKey, nameKey attribute.Key) attribute.KeyValue {
+	if net.ParseIP(host) != nil {
+		return ipKey.String(host)
+	}
+	return nameKey.String(host)
+}
+
+func otelRecordError(span trace.Span, result protocol.Result) {
+	if err := result.Error(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// attachmentsCarrier adapts the attachments of invocation to propagation.TextMapCarrier
+type attachmentsCarrier struct {
+	invocation protocol.Invocation
+}
+
+// Get returns the attachment of @key
+func (c *attachmentsCarrier) Get(key string) string {
+	switch v := c.invocation.Attachment(key).(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// Set sets the attachment of @key
+func (c *attachmentsCarrier) Set(key string, value string) {
+	c.invocation.SetAttachments(key, value)
+}
+
+// Keys returns the keys of all attachments
+func (c *attachmentsCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.invocation.Attachments()))
+	for k := range c.invocation.Attachments() {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/metrictest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// remoteInvoker transfers the attachments to the provider filter like the protocols
+type remoteInvoker struct {
+	protocol.BaseInvoker
+	provider *protocol.BaseInvoker
+	err      error
+	baggage  baggage.Baggage
+}
+
+func (ri *remoteInvoker) Invoke(_ context.Context, inv protocol.Invocation) protocol.Result {
+	attachments := make(map[string]interface{})
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v, ok := inv.Attachments()[k]; ok {
+			attachments[k] = v
+		}
+	}
+	providerInv := invocation.NewRPCInvocation(inv.MethodName(), inv.Arguments(), attachments)
+	providerFilter := extension.GetFilter(constant.PROVIDER_OTEL_FILTER)
+	return providerFilter.Invoke(context.Background(), &serviceInvoker{BaseInvoker: *ri.provider, remote: ri}, providerInv)
+}
+
+type serviceInvoker struct {
+	protocol.BaseInvoker
+	remote *remoteInvoker
+}
+
+func (si *serviceInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	si.remote.baggage = baggage.FromContext(ctx)
+	return &protocol.RPCResult{Err: si.remote.err}
+}
+
+func TestOtelFilter(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	meterProvider := metrictest.NewMeterProvider()
+	global.SetMeterProvider(meterProvider)
+
+	consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	invoker := &remoteInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(consumerURL),
+		provider:    protocol.NewBaseInvoker(providerURL),
+	}
+
+	member, _ := baggage.NewMember("user", "alice")
+	bag, _ := baggage.New(member)
+	ctx := baggage.ContextWithBaggage(context.Background(), bag)
+	consumerFilter := extension.GetFilter(constant.CONSUMER_OTEL_FILTER)
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil)
+	result := consumerFilter.Invoke(ctx, invoker, inv)
+	assert.Nil(t, result.Error())
+	assert.NotEmpty(t, inv.AttachmentsByKey(constant.TRACEPARENT_KEY, ""))
+	assert.Equal(t, "alice", invoker.baggage.Member("user").Value())
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	server, client := spans[0], spans[1]
+	assert.Equal(t, "com.ikurento.user.UserProvider/GetUser", client.Name)
+	assert.Equal(t, trace.SpanKindClient, client.SpanKind)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, client.SpanContext.TraceID(), server.SpanContext.TraceID())
+	assert.Equal(t, client.SpanContext.SpanID(), server.Parent.SpanID())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Contains(t, client.Attributes, semconv.RPCSystemKey.String("dubbo"))
+	assert.Contains(t, client.Attributes, semconv.RPCServiceKey.String("com.ikurento.user.UserProvider"))
+	assert.Contains(t, client.Attributes, semconv.RPCMethodKey.String("GetUser"))
+	assert.Contains(t, client.Attributes, semconv.NetPeerIPKey.String("127.0.0.1"))
+	assert.Contains(t, client.Attributes, semconv.NetPeerPortKey.Int(20000))
+	assert.Contains(t, server.Attributes, semconv.NetHostIPKey.String("127.0.0.1"))
+	assert.Equal(t, codes.Unset, client.Status.Code)
+
+	measurements := metrictest.AsStructs(meterProvider.MeasurementBatches)
+	assert.Len(t, measurements, 2)
+	assert.Equal(t, "rpc.server.duration", measurements[0].Name)
+	assert.Equal(t, "rpc.client.duration", measurements[1].Name)
+
+	// the error is recorded in the spans
+	exporter.Reset()
+	invoker.err = errors.New("error")
+	result = consumerFilter.Invoke(context.Background(), invoker,
+		invocation.NewRPCInvocation("GetUser", []interface{}{"1"}, nil))
+	assert.NotNil(t, result.Error())
+	spans = exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, codes.Error, span.Status.Code)
+		assert.Len(t, span.Events, 1)
+	}
+}
+
+func TestOtelAddressAttribute(t *testing.T) {
+	assert.Equal(t, semconv.NetPeerIPKey.String("::1"), otelAddressAttribute("::1", semconv.NetPeerIPKey, semconv.NetPeerNameKey))
+	assert.Equal(t, semconv.NetPeerNameKey.String("provider.local"),
+		otelAddressAttribute("provider.local", semconv.NetPeerIPKey, semconv.NetPeerNameKey))
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+func init() {
+	consumerFilter := &p2cConsumerFilter{}
+	providerFilter := &p2cProviderFilter{}
+
+	extension.SetFilter(constant.CONSUMER_P2C_FILTER, func() filter.Filter {
+		return consumerFilter
+	})
+
+	extension.SetFilter(constant.PROVIDER_P2C_FILTER, func() filter.Filter {
+		return providerFilter
+	})
+}
+
+// p2cConsumerFilter collects the latency, error rate and server load for p2c load balance
+type p2cConsumerFilter struct{}
+
+// Invoke records the request in the p2c status of the invoker
+func (f *p2cConsumerFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	status := protocol.GetP2CStatus(invoker.GetURL(), invocation.MethodName())
+	status.Begin()
+	start := time.Now()
+	result := invoker.Invoke(ctx, invocation)
+	status.End(time.Since(start), result.Error() == nil, getServerLoad(result))
+	return result
+}
+
+// OnResponse does nothing
+func (f *p2cConsumerFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// getServerLoad returns the server load in the attachments of @result, or -1 if there is not
+func getServerLoad(result protocol.Result) int64 {
+	var load string
+	switch v := result.Attachment(constant.P2C_SERVER_LOAD_KEY, nil).(type) {
+	case string:
+		load = v
+	case []string:
+		if len(v) > 0 {
+			load = v[0]
+		}
+	}
+	n, err := strconv.ParseInt(load, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// p2cProviderFilter reports the number of the requests being processed by provider as the server load
+type p2cProviderFilter struct {
+	inflight int64
+}
+
+// Invoke counts the request being processed
+func (f *p2cProviderFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	atomic.AddInt64(&f.inflight, 1)
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse adds the server load to the attachments of result
+func (f *p2cProviderFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker,
+	_ protocol.Invocation) protocol.Result {
+	load := atomic.AddInt64(&f.inflight, -1)
+	result.AddAttachment(constant.P2C_SERVER_LOAD_KEY, strconv.FormatInt(load, 10))
+	return result
+}
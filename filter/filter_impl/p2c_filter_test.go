@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type p2cTestInvoker struct {
+	*protocol.BaseInvoker
+	invoke func() protocol.Result
+}
+
+func (i *p2cTestInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	return i.invoke()
+}
+
+func TestP2CFilter(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.10.10:20000/com.ikurento.user.P2CProvider")
+	inv := invocation.NewRPCInvocation("test", []interface{}{"OK"}, make(map[string]interface{}))
+	consumerFilter := extension.GetFilter(constant.CONSUMER_P2C_FILTER)
+	providerFilter := extension.GetFilter(constant.PROVIDER_P2C_FILTER)
+	status := protocol.GetP2CStatus(url, inv.MethodName())
+
+	// the provider reports the requests being processed
+	provider := &p2cTestInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	var inner protocol.Result
+	provider.invoke = func() protocol.Result {
+		inner = providerFilter.Invoke(context.Background(), &p2cTestInvoker{
+			BaseInvoker: protocol.NewBaseInvoker(url),
+			invoke: func() protocol.Result {
+				return &protocol.RPCResult{}
+			},
+		}, inv)
+		// the consumer request is in flight
+		assert.Equal(t, int32(1), status.GetInflight())
+		return inner
+	}
+
+	result := consumerFilter.Invoke(context.Background(), provider, inv)
+	// the server load is reported in OnResponse of provider filter
+	assert.Equal(t, int64(-1), getServerLoad(result))
+	assert.Equal(t, int32(0), status.GetInflight())
+
+	// the request has finished when the load is reported
+	result = providerFilter.OnResponse(context.Background(), inner, provider, inv)
+	assert.Equal(t, "0", result.Attachment(constant.P2C_SERVER_LOAD_KEY, ""))
+	assert.Equal(t, int64(0), getServerLoad(result))
+
+	// the server load in response is recorded by consumer
+	provider.invoke = func() protocol.Result {
+		return &protocol.RPCResult{Attrs: map[string]interface{}{constant.P2C_SERVER_LOAD_KEY: "5"}}
+	}
+	consumerFilter.Invoke(context.Background(), provider, inv)
+	assert.Equal(t, int64(5), status.GetServerLoad())
+	assert.Equal(t, float64(0), status.GetErrorRate())
+
+	provider.invoke = func() protocol.Result {
+		return &protocol.RPCResult{Err: errors.New("error")}
+	}
+	consumerFilter.Invoke(context.Background(), provider, inv)
+	assert.Equal(t, int64(5), status.GetServerLoad())
+	assert.True(t, status.GetErrorRate() > 0)
+}
@@ -0,0 +1,124 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// the name of the counter of the timeout invocations reported to metrics.CounterReporter
+const timeoutCounter = "timeout_total"
+
+var (
+	providerTimeoutOnce   sync.Once
+	providerTimeoutFilter *ProviderTimeoutFilter
+)
+
+func init() {
+	extension.SetFilter(constant.PROVIDER_TIMEOUT_FILTER, GetProviderTimeoutFilter)
+}
+
+// ProviderTimeoutFilter enforces the timeout of the invocation on provider side.
+// The deadline of the context passed to the service is the earlier one of the remaining timeout
+// carried by the consumer and the timeout configured by provider.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   timeout: "3s" # the timeout of all methods
+ *   methods:
+ *     - name: "GetUser"
+ *       timeout: "1s"
+ * The invocations exceeding the timeout are counted as "timeout_total" by the metric reporters which implement
+ * metrics.CounterReporter, like prometheus.
+ */
+type ProviderTimeoutFilter struct {
+	reporters []metrics.CounterReporter
+}
+
+// Invoke rejects the expired invocation and invokes the service with the deadline
+func (f *ProviderTimeoutFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	timeout := protocol.GetMethodTimeout(url, methodName)
+	if remaining, ok := protocol.GetRemainingTimeout(invocation); ok {
+		if remaining <= 0 {
+			logger.Warnf("The invocation of %s.%s is expired on arrival", url.Service(), methodName)
+			return &protocol.RPCResult{
+				Err: perrors.Errorf("the invocation of %s.%s is expired on arrival", url.Service(), methodName),
+			}
+		}
+		if timeout <= 0 || remaining < timeout {
+			timeout = remaining
+		}
+	}
+	if timeout <= 0 {
+		return invoker.Invoke(ctx, invocation)
+	}
+
+	start := time.Now()
+	timeoutCtx, cancel := context.WithDeadline(ctx, start.Add(timeout))
+	defer cancel()
+	result := invoker.Invoke(timeoutCtx, invocation)
+	if elapsed := time.Since(start); elapsed > timeout {
+		logger.Warnf("The invocation of %s.%s is timeout, timeout: %v, elapsed: %v, remote: %v",
+			url.Service(), methodName, timeout, elapsed, invocation.Attachment(constant.REMOTE_ADDR))
+		for _, reporter := range f.reporters {
+			reporter.ReportCounter(timeoutCounter, url, 1)
+		}
+	}
+	return result
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *ProviderTimeoutFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// GetProviderTimeoutFilter returns the singleton ProviderTimeoutFilter
+// make sure that the configuration had been loaded before invoking this method.
+func GetProviderTimeoutFilter() filter.Filter {
+	providerTimeoutOnce.Do(func() {
+		reporters := make([]metrics.CounterReporter, 0)
+		for _, name := range config.GetMetricConfig().Reporters {
+			if reporter, ok := extension.GetMetricReporter(name).(metrics.CounterReporter); ok {
+				reporters = append(reporters, reporter)
+			}
+		}
+		providerTimeoutFilter = &ProviderTimeoutFilter{
+			reporters: reporters,
+		}
+	})
+	return providerTimeoutFilter
+}
@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/metrics"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type counterReporterMock struct {
+	values map[string]float64
+}
+
+func (reporter *counterReporterMock) ReportCounter(name string, _ *common.URL, value float64) {
+	reporter.values[name] += value
+}
+
+type deadlineInvoker struct {
+	*protocol.BaseInvoker
+	invoked     bool
+	deadline    time.Time
+	hasDeadline bool
+	sleep       time.Duration
+}
+
+func (di *deadlineInvoker) Invoke(ctx context.Context, _ protocol.Invocation) protocol.Result {
+	di.invoked = true
+	di.deadline, di.hasDeadline = ctx.Deadline()
+	time.Sleep(di.sleep)
+	return &protocol.RPCResult{}
+}
+
+func TestAttachRemainingTimeout(t *testing.T) {
+	inv := invocation.NewRPCInvocation("GetUser", nil, nil)
+	protocol.AttachRemainingTimeout(context.Background(), inv, 0)
+	_, ok := protocol.GetRemainingTimeout(inv)
+	assert.False(t, ok)
+
+	protocol.AttachRemainingTimeout(context.Background(), inv, time.Second)
+	remaining, ok := protocol.GetRemainingTimeout(inv)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, remaining)
+
+	// the deadline of context is earlier
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	protocol.AttachRemainingTimeout(ctx, inv, time.Second)
+	remaining, _ = protocol.GetRemainingTimeout(inv)
+	assert.True(t, remaining > 0 && remaining <= 100*time.Millisecond)
+
+	expiredCtx, expiredCancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer expiredCancel()
+	protocol.AttachRemainingTimeout(expiredCtx, inv, time.Second)
+	remaining, _ = protocol.GetRemainingTimeout(inv)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	// the remaining timeout under 1ms is rounded up
+	protocol.AttachRemainingTimeout(context.Background(), inv, 500*time.Microsecond)
+	remaining, _ = protocol.GetRemainingTimeout(inv)
+	assert.Equal(t, time.Millisecond, remaining)
+	protocol.AttachRemainingTimeout(context.Background(), inv, 1500*time.Microsecond)
+	remaining, _ = protocol.GetRemainingTimeout(inv)
+	assert.Equal(t, 2*time.Millisecond, remaining)
+
+	// the attachment decoded from java consumer
+	inv.SetAttachments(constant.TIMEOUT_ATTACHMENT_KEY, int64(200))
+	remaining, _ = protocol.GetRemainingTimeout(inv)
+	assert.Equal(t, 200*time.Millisecond, remaining)
+}
+
+func TestProviderTimeoutFilter_Invoke(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	invoker := &deadlineInvoker{BaseInvoker: protocol.NewBaseInvoker(url)}
+	reporter := &counterReporterMock{values: make(map[string]float64)}
+	timeoutFilter := &ProviderTimeoutFilter{reporters: []metrics.CounterReporter{reporter}}
+
+	// no timeout
+	result := timeoutFilter.Invoke(context.Background(), invoker, invocation.NewRPCInvocation("GetUser", nil, nil))
+	assert.Nil(t, result.Error())
+	assert.False(t, invoker.hasDeadline)
+
+	// the remaining timeout of consumer
+	start := time.Now()
+	inv := invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{constant.TIMEOUT_ATTACHMENT_KEY: "1000"})
+	timeoutFilter.Invoke(context.Background(), invoker, inv)
+	assert.True(t, invoker.hasDeadline)
+	assert.WithinDuration(t, start.Add(time.Second), invoker.deadline, 100*time.Millisecond)
+
+	// the timeout of provider is earlier
+	url.SetParam("methods.GetUser."+constant.TIMEOUT_KEY, "200ms")
+	timeoutFilter.Invoke(context.Background(), invoker, inv)
+	assert.WithinDuration(t, start.Add(200*time.Millisecond), invoker.deadline, 100*time.Millisecond)
+
+	assert.Equal(t, float64(0), reporter.values[timeoutCounter])
+
+	// the slow execution is still returned, and counted
+	invoker.sleep = 300 * time.Millisecond
+	result = timeoutFilter.Invoke(context.Background(), invoker, inv)
+	assert.Nil(t, result.Error())
+	assert.Equal(t, float64(1), reporter.values[timeoutCounter])
+
+	// expired on arrival
+	invoker.invoked = false
+	inv = invocation.NewRPCInvocation("GetUser", nil, map[string]interface{}{constant.TIMEOUT_ATTACHMENT_KEY: "0"})
+	result = timeoutFilter.Invoke(context.Background(), invoker, inv)
+	assert.NotNil(t, result.Error())
+	assert.False(t, invoker.invoked)
+}
@@ -0,0 +1,282 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/router/v3router/judger"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const (
+	// ActionAllow allows the request matched by the policy
+	ActionAllow = "allow"
+	// ActionDeny denies the request matched by the policy
+	ActionDeny = "deny"
+)
+
+// Rule is the rbac rule of a service.
+/**
+ * example:
+ * default_action: deny # the action if no policy matches, it's deny if there is any allow policy, otherwise allow
+ * policies:
+ *   - name: forbid-delete
+ *     action: deny
+ *     applications: # the application of consumer
+ *       - exact: user-info-client
+ *     methods:
+ *       - name_match:
+ *           exact: DeleteUser
+ *   - name: internal-read
+ *     action: allow
+ *     methods:
+ *       - name_match:
+ *           prefix: Get
+ *         args:
+ *           - index: 1
+ *             type: string
+ *             str_value:
+ *               oneof:
+ *                 - prefix: "internal-"
+ *     source_ips: # ip or cidr of consumer
+ *       - 10.0.0.0/8
+ * the deny policies are evaluated before the allow policies.
+ * the applications match the consumer application verified by the provider auth filter, and the source ips
+ * match the peer address of the connection provided by the dubbo, grpc, jsonrpc and rest servers. The request
+ * whose application or source ip is unavailable, e.g. without auth filter or over triple, is matched by the deny
+ * policies and never matched by the allow policies of these conditions.
+ */
+type Rule struct {
+	DefaultAction string    `yaml:"default_action" json:"default_action"`
+	Policies      []*Policy `yaml:"policies" json:"policies"`
+}
+
+// Policy matches the requests whose consumer application, method and source ip all match,
+// an empty condition matches any request.
+type Policy struct {
+	Name         string                     `yaml:"name" json:"name"`
+	Action       string                     `yaml:"action" json:"action"`
+	Applications []*config.StringMatch      `yaml:"applications" json:"applications"`
+	Methods      []*config.DubboMethodMatch `yaml:"methods" json:"methods"`
+	SourceIPs    []string                   `yaml:"source_ips" json:"source_ips"`
+}
+
+// ParseRule parses the rbac rule in yaml format
+func ParseRule(content []byte) (*Rule, error) {
+	rule := &Rule{}
+	if err := yaml.Unmarshal(content, rule); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return rule, nil
+}
+
+// Engine evaluates the requests against the policies of a rule
+type Engine struct {
+	defaultAllow bool
+	denies       []*policyMatcher
+	allows       []*policyMatcher
+}
+
+// NewEngine builds the Engine of @rule
+func NewEngine(rule *Rule) (*Engine, error) {
+	engine := &Engine{}
+	for i, policy := range rule.Policies {
+		matcher, err := newPolicyMatcher(policy)
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "invalid policy %d %s", i, policy.Name)
+		}
+		switch strings.ToLower(policy.Action) {
+		case ActionAllow:
+			engine.allows = append(engine.allows, matcher)
+		case ActionDeny:
+			engine.denies = append(engine.denies, matcher)
+		default:
+			return nil, perrors.Errorf("invalid action %s of policy %d %s", policy.Action, i, policy.Name)
+		}
+	}
+	switch strings.ToLower(rule.DefaultAction) {
+	case ActionAllow:
+		engine.defaultAllow = true
+	case ActionDeny:
+		engine.defaultAllow = false
+	case "":
+		engine.defaultAllow = len(engine.allows) == 0
+	default:
+		return nil, perrors.Errorf("invalid default action %s", rule.DefaultAction)
+	}
+	return engine, nil
+}
+
+// Evaluate returns whether @inv is allowed, and the name of the policy which decides it.
+// The policy name is empty if no policy matches.
+func (e *Engine) Evaluate(ctx context.Context, inv protocol.Invocation) (bool, string) {
+	if e == nil {
+		return true, ""
+	}
+	request := newRequest(ctx, inv)
+	for _, policy := range e.denies {
+		if policy.match(request, true) {
+			return false, policy.name
+		}
+	}
+	for _, policy := range e.allows {
+		if policy.match(request, false) {
+			return true, policy.name
+		}
+	}
+	return e.defaultAllow, ""
+}
+
+var nilArgType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// request is the attributes of the invocation used to match policies
+type request struct {
+	application string
+	ip          net.IP
+	invocation  protocol.Invocation
+}
+
+// newRequest reads the principal from @ctx rather than the attachments, which can be forged by any consumer
+func newRequest(ctx context.Context, inv protocol.Invocation) *request {
+	r := &request{invocation: inv}
+	if app, ok := ctx.Value(constant.AuthApplicationKey).(string); ok {
+		r.application = app
+	}
+	if addr, ok := ctx.Value(constant.RemoteAddrKey).(string); ok {
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		r.ip = net.ParseIP(addr)
+	}
+	// the invocation decoded by provider carries arguments only, the method judger matches parameter values
+	if len(inv.ParameterValues()) == 0 && len(inv.Arguments()) > 0 {
+		values := make([]reflect.Value, 0, len(inv.Arguments()))
+		for _, arg := range inv.Arguments() {
+			if arg == nil {
+				// the nil argument never matches the typed argument conditions
+				values = append(values, reflect.Zero(nilArgType))
+				continue
+			}
+			values = append(values, reflect.ValueOf(arg))
+		}
+		r.invocation = invocation.NewRPCInvocationWithOptions(
+			invocation.WithMethodName(inv.MethodName()),
+			invocation.WithArguments(inv.Arguments()),
+			invocation.WithParameterValues(values),
+			invocation.WithAttachments(inv.Attachments()))
+	}
+	return r
+}
+
+type policyMatcher struct {
+	name         string
+	applications []*judger.StringMatchJudger
+	methods      []*judger.MethodMatchJudger
+	networks     []*net.IPNet
+}
+
+func newPolicyMatcher(policy *Policy) (*policyMatcher, error) {
+	matcher := &policyMatcher{name: policy.Name}
+	for _, app := range policy.Applications {
+		matcher.applications = append(matcher.applications, judger.NewStringMatchJudger(app))
+	}
+	for _, method := range policy.Methods {
+		matcher.methods = append(matcher.methods, judger.NewMethodMatchJudger(method))
+	}
+	for _, source := range policy.SourceIPs {
+		if !strings.Contains(source, "/") {
+			ip := net.ParseIP(source)
+			if ip == nil {
+				return nil, perrors.Errorf("invalid source ip %s", source)
+			}
+			if ip.To4() != nil {
+				source += "/32"
+			} else {
+				source += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(source)
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		matcher.networks = append(matcher.networks, network)
+	}
+	return matcher, nil
+}
+
+// match returns true if @r matches all conditions, each condition matches if any item of it matches.
+// @unknown is the result of the application and source ip conditions if @r does not carry them.
+func (m *policyMatcher) match(r *request, unknown bool) bool {
+	return m.matchApplication(r, unknown) && m.matchMethod(r) && m.matchSourceIP(r, unknown)
+}
+
+func (m *policyMatcher) matchApplication(r *request, unknown bool) bool {
+	if len(m.applications) == 0 {
+		return true
+	}
+	if len(r.application) == 0 {
+		return unknown
+	}
+	for _, app := range m.applications {
+		if app.Judge(r.application) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *policyMatcher) matchMethod(r *request) bool {
+	if len(m.methods) == 0 {
+		return true
+	}
+	for _, method := range m.methods {
+		if method.Judge(r.invocation) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *policyMatcher) matchSourceIP(r *request, unknown bool) bool {
+	if len(m.networks) == 0 {
+		return true
+	}
+	if r.ip == nil {
+		return unknown
+	}
+	for _, network := range m.networks {
+		if network.Contains(r.ip) {
+			return true
+		}
+	}
+	return false
+}
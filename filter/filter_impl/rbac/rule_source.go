@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/fsnotify/fsnotify"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+var (
+	sourcesMutex sync.Mutex
+	fileSources  = make(map[string]*RuleSource)
+	ruleSources  = make(map[string]*RuleSource)
+)
+
+// RuleSource keeps the Engine in sync with the rule in a file or the config center,
+// the Engine in use is kept if the new rule is broken.
+type RuleSource struct {
+	mutex   sync.RWMutex
+	engine  *Engine
+	watcher *fsnotify.Watcher
+}
+
+// Evaluate returns whether @inv is allowed by the current rule, and the name of the policy which decides it
+func (s *RuleSource) Evaluate(ctx context.Context, inv protocol.Invocation) (bool, string) {
+	s.mutex.RLock()
+	engine := s.engine
+	s.mutex.RUnlock()
+	return engine.Evaluate(ctx, inv)
+}
+
+// update replaces the Engine with the one built from @content, an empty content removes the rule
+func (s *RuleSource) update(content []byte) error {
+	var engine *Engine
+	if len(strings.TrimSpace(string(content))) > 0 {
+		rule, err := ParseRule(content)
+		if err != nil {
+			return err
+		}
+		if engine, err = NewEngine(rule); err != nil {
+			return err
+		}
+	}
+	s.mutex.Lock()
+	s.engine = engine
+	s.mutex.Unlock()
+	return nil
+}
+
+// GetFileRuleSource returns the RuleSource of the rule file @path, it will be loaded and watched at the first time
+func GetFileRuleSource(path string) (*RuleSource, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+	if source, ok := fileSources[path]; ok {
+		return source, nil
+	}
+
+	source := &RuleSource{}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if err = source.update(content); err != nil {
+		return nil, perrors.WithMessagef(err, "parse rbac rule file %s", path)
+	}
+	// watch the directory since the file may be replaced by rename
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, perrors.WithStack(err)
+	}
+	source.watcher = watcher
+	go source.watch(path)
+	fileSources[path] = source
+	return source, nil
+}
+
+func (s *RuleSource) watch(path string) {
+	for {
+		select {
+		case event, ok := <-s.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			content, err := ioutil.ReadFile(path)
+			if err == nil {
+				err = s.update(content)
+			}
+			if err != nil {
+				logger.Warnf("Failed to reload the rbac rule file %s, error: %v", path, err)
+				continue
+			}
+			logger.Infof("The rbac rule file %s is reloaded", path)
+		case err, ok := <-s.watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("Failed to watch the rbac rule file %s, error: %v", path, err)
+		}
+	}
+}
+
+// GetConfigCenterRuleSource returns the RuleSource of the rule @key in @group of the config center,
+// it will be loaded and listened at the first time
+func GetConfigCenterRuleSource(group, key string) (*RuleSource, error) {
+	sourcesMutex.Lock()
+	defer sourcesMutex.Unlock()
+	ruleKey := group + "/" + key
+	if source, ok := ruleSources[ruleKey]; ok {
+		return source, nil
+	}
+
+	dynamicConfiguration := config.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfiguration == nil {
+		return nil, perrors.New("the dynamic configuration is nil, please config the config center")
+	}
+	source := &RuleSource{}
+	dynamicConfiguration.AddListener(key, source, config_center.WithGroup(group))
+	value, err := dynamicConfiguration.GetRule(key, config_center.WithGroup(group))
+	if err == nil {
+		err = source.update([]byte(value))
+	}
+	if err != nil {
+		dynamicConfiguration.RemoveListener(key, source, config_center.WithGroup(group))
+		return nil, perrors.WithMessagef(err, "get rbac rule fail, key{%s}", key)
+	}
+	ruleSources[ruleKey] = source
+	return source, nil
+}
+
+// Process updates the rule once it is changed in the config center
+func (s *RuleSource) Process(event *config_center.ConfigChangeEvent) {
+	logger.Infof("Notification of rbac rule %s, change type is:[%s]", event.Key, event.ConfigType)
+	if remoting.EventTypeDel == event.ConfigType {
+		_ = s.update(nil)
+		return
+	}
+	content, ok := event.Value.(string)
+	if !ok {
+		logger.Errorf("Convert event content fail, raw content:[%v]", event.Value)
+		return
+	}
+	if err := s.update([]byte(content)); err != nil {
+		logger.Errorf("Parse rbac rule %s fail, error:[%v]", event.Key, err)
+	}
+}
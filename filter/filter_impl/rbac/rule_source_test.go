@@ -0,0 +1,100 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/config"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+const denyAllRule = `
+default_action: deny
+`
+
+func TestGetFileRuleSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rbac.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testRule), 0o644))
+
+	source, err := GetFileRuleSource(path)
+	assert.Nil(t, err)
+	ctx, inv := newInvocation("user-info-client", "10.0.0.1:20000", "UpdateUser", "1")
+	allowed, _ := source.Evaluate(ctx, inv)
+	assert.False(t, allowed)
+	allowed, _ = source.Evaluate(newInvocation("admin-client", "10.0.0.1:20000", "UpdateUser", "1"))
+	assert.True(t, allowed)
+
+	// the rule in use is kept if the new file is broken
+	assert.Nil(t, ioutil.WriteFile(path, []byte("policies: ["), 0o644))
+	time.Sleep(100 * time.Millisecond)
+	allowed, _ = source.Evaluate(ctx, inv)
+	assert.False(t, allowed)
+
+	assert.Nil(t, ioutil.WriteFile(path, []byte("default_action: allow\n"), 0o644))
+	assert.Eventually(t, func() bool {
+		allowed, _ := source.Evaluate(ctx, inv)
+		return allowed
+	}, 3*time.Second, 50*time.Millisecond)
+
+	_, err = GetFileRuleSource(filepath.Join(dir, "absent.yml"))
+	assert.NotNil(t, err)
+}
+
+func TestGetConfigCenterRuleSource(t *testing.T) {
+	factory := &config_center.MockDynamicConfigurationFactory{Content: denyAllRule}
+	dynamicConfiguration, err := factory.GetDynamicConfiguration(nil)
+	assert.Nil(t, err)
+	config.GetEnvInstance().SetDynamicConfiguration(dynamicConfiguration)
+
+	key := "com.ikurento.user.UserProvider.rbac"
+	source, err := GetConfigCenterRuleSource(config_center.DEFAULT_GROUP, key)
+	assert.Nil(t, err)
+	ctx, inv := newInvocation("admin-client", "10.0.0.1:20000", "UpdateUser", "1")
+	allowed, _ := source.Evaluate(ctx, inv)
+	assert.False(t, allowed)
+
+	source.Process(&config_center.ConfigChangeEvent{Key: key, Value: testRule, ConfigType: remoting.EventTypeUpdate})
+	allowed, policy := source.Evaluate(ctx, inv)
+	assert.True(t, allowed)
+	assert.Equal(t, "admin", policy)
+
+	// the broken rule is ignored
+	source.Process(&config_center.ConfigChangeEvent{Key: key, Value: "policies: [", ConfigType: remoting.EventTypeUpdate})
+	_, policy = source.Evaluate(ctx, inv)
+	assert.Equal(t, "admin", policy)
+
+	// all requests are allowed once the rule is removed
+	source.Process(&config_center.ConfigChangeEvent{Key: key, ConfigType: remoting.EventTypeDel})
+	allowed, _ = source.Evaluate(newInvocation("user-info-client", "10.0.0.1:20000", "DeleteUser", "1"))
+	assert.True(t, allowed)
+}
@@ -0,0 +1,148 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+const testRule = `
+policies:
+  - name: forbid-delete
+    action: deny
+    applications:
+      - exact: user-info-client
+    methods:
+      - name_match:
+          exact: DeleteUser
+  - name: internal-read
+    action: allow
+    methods:
+      - name_match:
+          prefix: Get
+        args:
+          - index: 1
+            type: string
+            str_value:
+              oneof:
+                - prefix: "internal-"
+    source_ips:
+      - 10.0.0.0/8
+      - 192.168.1.1
+  - name: admin
+    action: allow
+    applications:
+      - exact: admin-client
+`
+
+// newInvocation returns the invocation with the context of the authenticated application @app and the peer @addr
+func newInvocation(app, addr, method string, args ...interface{}) (context.Context, protocol.Invocation) {
+	ctx := context.WithValue(context.Background(), constant.AuthApplicationKey, app)
+	ctx = context.WithValue(ctx, constant.RemoteAddrKey, addr)
+	return ctx, invocation.NewRPCInvocation(method, args, nil)
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	rule, err := ParseRule([]byte(testRule))
+	assert.Nil(t, err)
+	engine, err := NewEngine(rule)
+	assert.Nil(t, err)
+
+	allowed, policy := engine.Evaluate(newInvocation("admin-client", "10.0.0.1:20000", "UpdateUser", "1"))
+	assert.True(t, allowed)
+	assert.Equal(t, "admin", policy)
+
+	allowed, policy = engine.Evaluate(newInvocation("user-info-client", "10.0.0.1:20000", "DeleteUser", "1"))
+	assert.False(t, allowed)
+	assert.Equal(t, "forbid-delete", policy)
+
+	allowed, policy = engine.Evaluate(newInvocation("user-info-client", "10.1.2.3:20000", "GetUser", "internal-1"))
+	assert.True(t, allowed)
+	assert.Equal(t, "internal-read", policy)
+
+	allowed, _ = engine.Evaluate(newInvocation("user-info-client", "192.168.1.1:20000", "GetUser", "internal-1"))
+	assert.True(t, allowed)
+
+	// argument does not match
+	allowed, policy = engine.Evaluate(newInvocation("user-info-client", "10.1.2.3:20000", "GetUser", "external-1"))
+	assert.False(t, allowed)
+	assert.Equal(t, "", policy)
+
+	// source ip does not match
+	allowed, _ = engine.Evaluate(newInvocation("user-info-client", "172.16.0.1:20000", "GetUser", "internal-1"))
+	assert.False(t, allowed)
+
+	allowed, _ = engine.Evaluate(newInvocation("user-info-client", "10.1.2.3:20000", "GetUser", nil))
+	assert.False(t, allowed)
+
+	// only deny policies, the requests are allowed by default
+	engine, err = NewEngine(&Rule{Policies: rule.Policies[:1]})
+	assert.Nil(t, err)
+	allowed, _ = engine.Evaluate(newInvocation("user-info-client", "10.0.0.1:20000", "UpdateUser", "1"))
+	assert.True(t, allowed)
+
+	engine, err = NewEngine(&Rule{DefaultAction: "deny", Policies: rule.Policies[:1]})
+	assert.Nil(t, err)
+	allowed, _ = engine.Evaluate(newInvocation("user-info-client", "10.0.0.1:20000", "UpdateUser", "1"))
+	assert.False(t, allowed)
+}
+
+func TestEngine_EvaluateUnverifiedPrincipal(t *testing.T) {
+	rule, err := ParseRule([]byte(testRule))
+	assert.Nil(t, err)
+	engine, err := NewEngine(rule)
+	assert.Nil(t, err)
+
+	// the application and address in the attachments are ignored since any consumer can forge them
+	inv := invocation.NewRPCInvocation("GetUser", []interface{}{"internal-1"}, map[string]interface{}{
+		constant.CONSUMER:    "admin-client",
+		constant.REMOTE_ADDR: "10.0.0.1:20000",
+	})
+	allowed, _ := engine.Evaluate(context.Background(), inv)
+	assert.False(t, allowed)
+
+	// the deny policy matches the request whose application is unknown
+	allowed, policy := engine.Evaluate(context.Background(), invocation.NewRPCInvocation("DeleteUser", []interface{}{"1"}, nil))
+	assert.False(t, allowed)
+	assert.Equal(t, "forbid-delete", policy)
+
+	// the allow policy never matches the request whose source ip is unknown
+	ctx := context.WithValue(context.Background(), constant.AuthApplicationKey, "user-info-client")
+	allowed, _ = engine.Evaluate(ctx, invocation.NewRPCInvocation("GetUser", []interface{}{"internal-1"}, nil))
+	assert.False(t, allowed)
+}
+
+func TestNewEngine_Invalid(t *testing.T) {
+	_, err := NewEngine(&Rule{Policies: []*Policy{{Name: "p", Action: "reject"}}})
+	assert.NotNil(t, err)
+	_, err = NewEngine(&Rule{Policies: []*Policy{{Name: "p", Action: "allow", SourceIPs: []string{"10.0.0"}}}})
+	assert.NotNil(t, err)
+	_, err = NewEngine(&Rule{DefaultAction: "reject"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/filter_impl/rbac"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var (
+	rbacFilterOnce sync.Once
+	rbacFilter     *RBACFilter
+)
+
+func init() {
+	extension.SetFilter(constant.RBAC_FILTER, GetRBACFilter)
+}
+
+// RBACFilter authorizes the requests by the allow and deny policies of the service,
+// it should be placed after the auth filter which passes the verified consumer application by context.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   filter: "auth,rbac"
+ *   accesslog: "true" # the denied requests are logged into the access log
+ *   params:
+ *     rbac.file: "/etc/dubbo/rbac.yml" # load the rule from the file
+ *     rbac.group: "dubbo" # or load the rule with key "{interface}:[version]:[group].rbac" from the config center
+ * see rbac.Rule for the format of the rule.
+ */
+type RBACFilter struct {
+	accessLogFilter *AccessLogFilter
+}
+
+// Invoke rejects the invocation if it is denied by the rbac rule of the service
+func (f *RBACFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	source, err := getRBACRuleSource(url)
+	if err != nil {
+		// the requests are denied if the rule can not be loaded
+		logger.Errorf("Failed to load the rbac rule of %s, error: %v", url.ServiceKey(), err)
+		f.logDenial(url, invocation, "")
+		return &protocol.RPCResult{Err: perrors.Errorf("the rbac rule of %s is unavailable", url.ServiceKey())}
+	}
+	if allowed, policy := source.Evaluate(ctx, invocation); !allowed {
+		f.logDenial(url, invocation, policy)
+		return &protocol.RPCResult{
+			Err: perrors.Errorf("permission denied, method %s of %s is not allowed to call", invocation.MethodName(), url.ServiceKey()),
+		}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *RBACFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// logDenial logs the denied invocation into the access log if it's configured
+func (f *RBACFilter) logDenial(url *common.URL, invocation protocol.Invocation, policy string) {
+	accessLog := url.GetParam(constant.ACCESS_LOG_KEY, "")
+	if len(accessLog) == 0 {
+		logger.Warnf("The invocation %s of %s is denied by rbac policy %s", invocation.MethodName(), url.ServiceKey(), policy)
+		return
+	}
+	data := f.accessLogFilter.buildAccessLogData(nil, invocation)
+	data[Denied] = "rbac"
+	if len(policy) > 0 {
+		data[Denied] += ":" + policy
+	}
+	f.accessLogFilter.logIntoChannel(AccessLogData{data: data, accessLog: accessLog})
+}
+
+// getRBACRuleSource returns the RuleSource configured by the url
+func getRBACRuleSource(url *common.URL) (*rbac.RuleSource, error) {
+	if path := url.GetParam(constant.RBAC_RULE_FILE_KEY, ""); len(path) > 0 {
+		return rbac.GetFileRuleSource(path)
+	}
+	group := url.GetParam(constant.RBAC_RULE_GROUP_KEY, config_center.DEFAULT_GROUP)
+	return rbac.GetConfigCenterRuleSource(group, config_center.GetRuleKey(url)+constant.RBACRuleSuffix)
+}
+
+// GetRBACFilter returns the singleton RBACFilter
+func GetRBACFilter() filter.Filter {
+	rbacFilterOnce.Do(func() {
+		rbacFilter = &RBACFilter{accessLogFilter: GetAccessLogFilter().(*AccessLogFilter)}
+	})
+	return rbacFilter
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/filter_impl/auth"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestRBACFilter_Invoke(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rbac.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`
+policies:
+  - name: forbid-delete
+    action: deny
+    applications:
+      - exact: user-info-client
+    methods:
+      - name_match:
+          exact: DeleteUser
+`), 0o644))
+
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	url.SetParam(constant.RBAC_RULE_FILE_KEY, path)
+	invoker := protocol.NewBaseInvoker(url)
+	rbacFilter := GetRBACFilter()
+	attachments := map[string]interface{}{constant.CONSUMER: "user-info-client"}
+	ctx := context.WithValue(context.Background(), constant.AuthApplicationKey, "user-info-client")
+
+	result := rbacFilter.Invoke(ctx, invoker, invocation.NewRPCInvocation("UpdateUser", []interface{}{"1"}, attachments))
+	assert.Nil(t, result.Error())
+
+	result = rbacFilter.Invoke(ctx, invoker, invocation.NewRPCInvocation("DeleteUser", []interface{}{"1"}, attachments))
+	assert.NotNil(t, result.Error())
+
+	// the denial is logged into the access log
+	url.SetParam(constant.ACCESS_LOG_KEY, "true")
+	result = rbacFilter.Invoke(ctx, invoker, invocation.NewRPCInvocation("DeleteUser", []interface{}{"1"}, attachments))
+	assert.NotNil(t, result.Error())
+
+	// the requests are denied if the rule can not be loaded
+	url.SetParam(constant.RBAC_RULE_FILE_KEY, filepath.Join(dir, "absent.yml"))
+	result = rbacFilter.Invoke(ctx, invoker, invocation.NewRPCInvocation("UpdateUser", []interface{}{"1"}, attachments))
+	assert.NotNil(t, result.Error())
+}
+
+func TestRBACFilter_InvokeWithAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rbac")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	rulePath := filepath.Join(dir, "rbac.yml")
+	assert.Nil(t, ioutil.WriteFile(rulePath, []byte(`
+policies:
+  - name: forbid-delete
+    action: deny
+    applications:
+      - exact: user-info-client
+    methods:
+      - name_match:
+          exact: DeleteUser
+`), 0o644))
+	keyPath := filepath.Join(dir, "accesskeys.yml")
+	assert.Nil(t, ioutil.WriteFile(keyPath, []byte(`
+user-info-client:
+  - accessKey: "ak1"
+    secretKey: "sk1"
+other-client:
+  - accessKey: "ak2"
+    secretKey: "sk2"
+`), 0o600))
+
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	providerURL.SetParam(constant.SERVICE_AUTH_KEY, "true")
+	providerURL.SetParam(constant.ACCESS_KEY_STORAGE_KEY, constant.FILE_ACCESS_KEY_STORAGE)
+	providerURL.SetParam(constant.ACCESS_KEY_FILE_KEY, keyPath)
+	providerURL.SetParam(constant.RBAC_RULE_FILE_KEY, rulePath)
+	provider := &filterInvoker{
+		Invoker: &filterInvoker{Invoker: protocol.NewBaseInvoker(providerURL), filter: GetRBACFilter()},
+		filter:  &auth.ProviderAuthFilter{},
+	}
+	invoke := func(application, method string) protocol.Result {
+		// the consumer signs the request with its access key in the same file
+		consumerURL := providerURL.Clone()
+		consumerURL.SetParam(constant.APPLICATION_KEY, application)
+		inv := invocation.NewRPCInvocation(method, []interface{}{"1"}, nil)
+		assert.Nil(t, auth.GetDefaultAuthenticator().Sign(inv, consumerURL))
+		return provider.Invoke(context.Background(), inv)
+	}
+
+	// the application verified by the access key stored in file is checked by rbac
+	assert.Nil(t, invoke("user-info-client", "UpdateUser").Error())
+	assert.NotNil(t, invoke("user-info-client", "DeleteUser").Error())
+	assert.Nil(t, invoke("other-client", "DeleteUser").Error())
+}
+
+// filterInvoker invokes the embedded invoker through @filter
+type filterInvoker struct {
+	protocol.Invoker
+	filter filter.Filter
+}
+
+func (fi *filterInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	return fi.filter.Invoke(ctx, fi.Invoker, inv)
+}
+
+func TestAccessLogData_Denied(t *testing.T) {
+	data := AccessLogData{data: map[string]string{
+		constant.INTERFACE_KEY: "com.ikurento.user.UserProvider",
+		constant.METHOD_KEY:    "DeleteUser",
+		Denied:                 "rbac:forbid-delete",
+	}}
+	assert.Contains(t, data.toLogMessage(), "denied by rbac:forbid-delete")
+}
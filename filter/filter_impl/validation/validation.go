@@ -0,0 +1,199 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// errorPrefix marks the encoded ValidationError in the error message,
+// the message is kept when the error is passed to the consumer by any protocol.
+const errorPrefix = "dubbo validation failed: "
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+	regexps      sync.Map
+	namedRegexps sync.Map
+)
+
+// Violation is a constraint which is violated by an argument
+type Violation struct {
+	// Index is the index of the argument, starting from 0
+	Index int `json:"index"`
+	// Field is the path of the violated field in the argument
+	Field string `json:"field,omitempty"`
+	// Tag is the violated validation tag, such as required or min
+	Tag string `json:"tag"`
+	// Param is the parameter of the tag, such as 1 of min=1
+	Param string `json:"param,omitempty"`
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+// ValidationError is returned if the arguments of invocation violate the constraints of validate tags
+type ValidationError struct {
+	Method     string       `json:"method"`
+	Violations []*Violation `json:"violations"`
+}
+
+// Error returns the message carrying the violations in json format
+func (e *ValidationError) Error() string {
+	content, _ := json.Marshal(e)
+	return errorPrefix + string(content)
+}
+
+// DecodeValidationError decodes the ValidationError from the error returned by provider
+func DecodeValidationError(err error) (*ValidationError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	if validationErr, ok := err.(*ValidationError); ok {
+		return validationErr, true
+	}
+	message := err.Error()
+	index := strings.Index(message, errorPrefix)
+	if index < 0 {
+		return nil, false
+	}
+	validationErr := &ValidationError{}
+	// the message may be followed by the stack trace of exception
+	if err := json.NewDecoder(strings.NewReader(message[index+len(errorPrefix):])).Decode(validationErr); err != nil {
+		return nil, false
+	}
+	return validationErr, true
+}
+
+// RegisterRegexp registers the regular expression @expr by @name, which is referred by the tag "regexp={name}".
+// The tag parameter is split by "," and "|", so a pattern containing them should be registered, or be written
+// with "0x2C" for "," and "0x7C" for "|" in the tag.
+func RegisterRegexp(name, expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	namedRegexps.Store(name, re)
+	return nil
+}
+
+// getValidate returns the singleton validator which supports the regexp tag besides the builtin tags
+func getValidate() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New()
+		_ = validate.RegisterValidation("regexp", func(fl validator.FieldLevel) bool {
+			field := fl.Field()
+			if field.Kind() != reflect.String {
+				return false
+			}
+			re, err := getRegexp(fl.Param())
+			return err == nil && re.MatchString(field.String())
+		})
+	})
+	return validate
+}
+
+// getRegexp gets the regular expression registered by name @expr, or compiles @expr itself
+func getRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := namedRegexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	if re, ok := regexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	regexps.Store(expr, re)
+	return re, nil
+}
+
+// ValidateArguments validates the struct arguments of @method against their validate tags,
+// the nested structs are validated and the elements of slices are validated by the dive tag.
+/**
+ * example:
+ * type User struct {
+ *   ID    string   `validate:"required"`
+ *   Age   int      `validate:"min=0,max=150"`
+ *   Email string   `validate:"regexp=^[a-z0-9._%+-]+@[a-z0-9.-]+$"`
+ *   Phone string   `validate:"regexp=phone"` // registered by RegisterRegexp("phone", `^\d{3,4}-\d{7,8}$`)
+ *   Tags  []string `validate:"max=10,dive,required"`
+ * }
+ */
+func ValidateArguments(method string, arguments []interface{}) error {
+	var violations []*Violation
+	for i, arg := range arguments {
+		if !isStruct(arg) {
+			continue
+		}
+		err := getValidate().Struct(arg)
+		if err == nil {
+			continue
+		}
+		fieldErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, fieldError := range fieldErrors {
+			violations = append(violations, newViolation(i, fieldError))
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Method: method, Violations: violations}
+}
+
+func isStruct(arg interface{}) bool {
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct
+}
+
+func newViolation(index int, fieldError validator.FieldError) *Violation {
+	// the namespace starts with the name of struct type
+	field := fieldError.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	message := fmt.Sprintf("%s violates %s", field, fieldError.Tag())
+	if len(fieldError.Param()) > 0 {
+		message += "=" + fieldError.Param()
+	}
+	return &Violation{
+		Index:   index,
+		Field:   field,
+		Tag:     fieldError.Tag(),
+		Param:   fieldError.Param(),
+		Message: message,
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+type address struct {
+	City string `validate:"required"`
+}
+
+type user struct {
+	ID        string     `validate:"required"`
+	Age       int        `validate:"min=0,max=150"`
+	Email     string     `validate:"omitempty,regexp=^[a-z0-9.]+@[a-z0-9.]+$"`
+	Address   address    `validate:"required"`
+	Tags      []string   `validate:"max=2,dive,required"`
+	Addresses []*address `validate:"dive"`
+}
+
+func TestValidateArguments(t *testing.T) {
+	valid := &user{ID: "1", Age: 20, Email: "alice@example.com", Address: address{City: "hz"}, Tags: []string{"a"}}
+	assert.Nil(t, ValidateArguments("AddUser", []interface{}{valid, "any", nil}))
+
+	invalid := &user{Age: 200, Email: "alice", Tags: []string{"a", ""}, Addresses: []*address{{}}}
+	err := ValidateArguments("AddUser", []interface{}{"any", invalid})
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "AddUser", validationErr.Method)
+
+	fields := make(map[string]*Violation)
+	for _, violation := range validationErr.Violations {
+		assert.Equal(t, 1, violation.Index)
+		fields[violation.Field] = violation
+	}
+	assert.Equal(t, "required", fields["ID"].Tag)
+	assert.Equal(t, "max", fields["Age"].Tag)
+	assert.Equal(t, "150", fields["Age"].Param)
+	assert.Equal(t, "regexp", fields["Email"].Tag)
+	assert.Equal(t, "required", fields["Address.City"].Tag)
+	assert.Equal(t, "required", fields["Tags[1]"].Tag)
+	assert.Equal(t, "required", fields["Addresses[0].City"].Tag)
+}
+
+func TestDecodeValidationError(t *testing.T) {
+	err := ValidateArguments("AddUser", []interface{}{&user{ID: "1", Address: address{City: "hz"}, Age: -1}})
+	assert.NotNil(t, err)
+
+	// the error is passed to the consumer as a message, such as the java exception
+	decoded, ok := DecodeValidationError(errors.New("java exception: " + err.Error() + "\n\tat stack trace"))
+	assert.True(t, ok)
+	assert.Equal(t, err, decoded)
+
+	decoded, ok = DecodeValidationError(err)
+	assert.True(t, ok)
+	assert.Equal(t, err, decoded)
+
+	_, ok = DecodeValidationError(errors.New("other error"))
+	assert.False(t, ok)
+	_, ok = DecodeValidationError(nil)
+	assert.False(t, ok)
+}
+
+type phone struct {
+	Number string `validate:"regexp=phone"`
+	Code   string `validate:"regexp=^(860x7C1)0x2C?[0-9]*$"`
+}
+
+func TestValidateArgumentsWithRegisteredRegexp(t *testing.T) {
+	assert.NotNil(t, RegisterRegexp("invalid", "("))
+	assert.Nil(t, RegisterRegexp("phone", `^\d{3,4}-\d{7,8}$|^\d{11}$`))
+
+	assert.Nil(t, ValidateArguments("AddPhone", []interface{}{&phone{Number: "0571-8888888", Code: "86,1"}}))
+	assert.Nil(t, ValidateArguments("AddPhone", []interface{}{&phone{Number: "13800000000", Code: "1"}}))
+
+	err := ValidateArguments("AddPhone", []interface{}{&phone{Number: "0571", Code: "7"}})
+	validationErr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, validationErr.Violations, 2)
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/filter/filter_impl/validation"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+var validationFilter = &ValidationFilter{}
+
+func init() {
+	extension.SetFilter(constant.VALIDATION_FILTER, GetValidationFilter)
+}
+
+// ValidationFilter validates the struct arguments against their validate tags before invoking, it's a default filter
+// of both provider and consumer. The consumer could decode the returned error by validation.DecodeValidationError.
+/**
+ * example:
+ * "UserProvider":
+ *   ... # other configuration
+ *   validation: "true" # validate the arguments of all methods, in the service or the reference config
+ *   methods:
+ *     - name: "GetUser"
+ *       validation: "false" # or enable and disable it for a method
+ */
+type ValidationFilter struct{}
+
+// Invoke returns the validation error without invoking if any argument is invalid
+func (f *ValidationFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	url := invoker.GetURL()
+	methodName := invocation.MethodName()
+	enabled := url.GetMethodParam(methodName, constant.VALIDATION_KEY, url.GetParam(constant.VALIDATION_KEY, ""))
+	if len(enabled) == 0 || strings.EqualFold(enabled, "false") {
+		return invoker.Invoke(ctx, invocation)
+	}
+	if err := validation.ValidateArguments(methodName, invocation.Arguments()); err != nil {
+		logger.Debugf("The arguments of %s are invalid: %v", methodName, err)
+		return &protocol.RPCResult{Err: err}
+	}
+	return invoker.Invoke(ctx, invocation)
+}
+
+// OnResponse dummy process, returns the result directly
+func (f *ValidationFilter) OnResponse(_ context.Context, result protocol.Result, _ protocol.Invoker, _ protocol.Invocation) protocol.Result {
+	return result
+}
+
+// GetValidationFilter returns the singleton ValidationFilter
+func GetValidationFilter() filter.Filter {
+	return validationFilter
+}
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filter_impl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/filter/filter_impl/validation"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type validationTestUser struct {
+	ID   string `validate:"required"`
+	Name string `validate:"max=5"`
+}
+
+func TestValidationFilter_Invoke(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	invoker := protocol.NewBaseInvoker(url)
+	validationFilter := GetValidationFilter()
+	invalid := invocation.NewRPCInvocation("AddUser", []interface{}{&validationTestUser{Name: "too long"}}, nil)
+	valid := invocation.NewRPCInvocation("AddUser", []interface{}{&validationTestUser{ID: "1"}}, nil)
+
+	// the validation is disabled by default
+	assert.Nil(t, validationFilter.Invoke(context.Background(), invoker, invalid).Error())
+
+	url.SetParam(constant.VALIDATION_KEY, "true")
+	assert.Nil(t, validationFilter.Invoke(context.Background(), invoker, valid).Error())
+	result := validationFilter.Invoke(context.Background(), invoker, invalid)
+	validationErr, ok := validation.DecodeValidationError(result.Error())
+	assert.True(t, ok)
+	assert.Len(t, validationErr.Violations, 2)
+
+	url.SetParam("methods.AddUser."+constant.VALIDATION_KEY, "false")
+	assert.Nil(t, validationFilter.Invoke(context.Background(), invoker, invalid).Error())
+}
+
+func TestValidationFilter_InvokeOnConsumer(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&side=consumer&reference.filter=cshutdown%2Cvalidation&methods.AddUser.validation=true")
+	invoker := protocol.NewBaseInvoker(url)
+	invalid := invocation.NewRPCInvocation("AddUser", []interface{}{&validationTestUser{}}, nil)
+
+	// the invalid arguments are rejected before sent to the provider
+	result := GetValidationFilter().Invoke(context.Background(), invoker, invalid)
+	validationErr, ok := validation.DecodeValidationError(result.Error())
+	assert.True(t, ok)
+	assert.Equal(t, "AddUser", validationErr.Method)
+
+	other := invocation.NewRPCInvocation("GetUser", []interface{}{&validationTestUser{}}, nil)
+	assert.Nil(t, GetValidationFilter().Invoke(context.Background(), invoker, other).Error())
+}
@@ -57,10 +57,11 @@ const (
 )
 
 var (
-	labelNames       = []string{serviceKey, groupKey, versionKey, methodKey, timeoutKey}
-	namespace        = config.GetApplicationConfig().Name
-	reporterInstance *PrometheusReporter
-	reporterInitOnce sync.Once
+	labelNames        = []string{serviceKey, groupKey, versionKey, methodKey, timeoutKey}
+	serviceLabelNames = []string{serviceKey, groupKey, versionKey}
+	namespace         = config.GetApplicationConfig().Name
+	reporterInstance  *PrometheusReporter
+	reporterInitOnce  sync.Once
 )
 
 // should initialize after loading configuration
@@ -80,6 +81,14 @@ type PrometheusReporter struct {
 	providerHistogramVec *prometheus.HistogramVec
 	// report the consumer-side's histogram data
 	consumerHistogramVec *prometheus.HistogramVec
+
+	// the gauges which are created when they are reported at the first time
+	gaugeVecs  map[string]*prometheus.GaugeVec
+	gaugeMutex sync.Mutex
+
+	// the counters which are created when they are reported at the first time
+	counterVecs  map[string]*prometheus.CounterVec
+	counterMutex sync.Mutex
 }
 
 // Report reports the duration to Prometheus
@@ -114,6 +123,114 @@ func (reporter *PrometheusReporter) Report(ctx context.Context, invoker protocol
 	hisVec.With(labels).Observe(costMs)
 }
 
+// ReportGauge reports the value of gauge @name to Prometheus
+// the role in url must be consumer or provider
+// or it will be ignored
+func (reporter *PrometheusReporter) ReportGauge(name string, url *common.URL, value float64) {
+	var side string
+	if isProvider(url) {
+		side = providerKey
+	} else if isConsumer(url) {
+		side = consumerKey
+	} else {
+		logger.Warnf("The url belongs neither the consumer nor the provider, "+
+			"so the gauge %s will be ignored. url: %s", name, url.String())
+		return
+	}
+
+	gaugeVec, err := reporter.getGaugeVec(side, name)
+	if err != nil {
+		logger.Warnf("Failed to register the gauge %s, error: %v", name, err)
+		return
+	}
+	gaugeVec.With(prometheus.Labels{
+		serviceKey: url.Service(),
+		groupKey:   url.GetParam(groupKey, ""),
+		versionKey: url.GetParam(versionKey, ""),
+	}).Set(value)
+}
+
+// ReportCounter adds @value to the counter @name of Prometheus
+// the role in url must be consumer or provider
+// or it will be ignored
+func (reporter *PrometheusReporter) ReportCounter(name string, url *common.URL, value float64) {
+	var side string
+	if isProvider(url) {
+		side = providerKey
+	} else if isConsumer(url) {
+		side = consumerKey
+	} else {
+		logger.Warnf("The url belongs neither the consumer nor the provider, "+
+			"so the counter %s will be ignored. url: %s", name, url.String())
+		return
+	}
+
+	counterVec, err := reporter.getCounterVec(side, name)
+	if err != nil {
+		logger.Warnf("Failed to register the counter %s, error: %v", name, err)
+		return
+	}
+	counterVec.With(prometheus.Labels{
+		serviceKey: url.Service(),
+		groupKey:   url.GetParam(groupKey, ""),
+		versionKey: url.GetParam(versionKey, ""),
+	}).Add(value)
+}
+
+// getCounterVec returns the CounterVec of @name, it will be created and registered if not existing
+func (reporter *PrometheusReporter) getCounterVec(side, name string) (*prometheus.CounterVec, error) {
+	reporter.counterMutex.Lock()
+	defer reporter.counterMutex.Unlock()
+	key := side + "_" + name
+	if counterVec, ok := reporter.counterVecs[key]; ok {
+		return counterVec, nil
+	}
+	counterVec := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: side,
+			Name:      name,
+			Help:      "This is the dubbo's counter metrics",
+		},
+		serviceLabelNames)
+	if err := prometheus.Register(counterVec); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		counterVec = are.ExistingCollector.(*prometheus.CounterVec)
+	}
+	reporter.counterVecs[key] = counterVec
+	return counterVec, nil
+}
+
+// getGaugeVec returns the GaugeVec of @name, it will be created and registered if not existing
+func (reporter *PrometheusReporter) getGaugeVec(side, name string) (*prometheus.GaugeVec, error) {
+	reporter.gaugeMutex.Lock()
+	defer reporter.gaugeMutex.Unlock()
+	key := side + "_" + name
+	if gaugeVec, ok := reporter.gaugeVecs[key]; ok {
+		return gaugeVec, nil
+	}
+	gaugeVec := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: side,
+			Name:      name,
+			Help:      "This is the dubbo's gauge metrics",
+		},
+		serviceLabelNames)
+	if err := prometheus.Register(gaugeVec); err != nil {
+		are, ok := err.(prometheus.AlreadyRegisteredError)
+		if !ok {
+			return nil, err
+		}
+		gaugeVec = are.ExistingCollector.(*prometheus.GaugeVec)
+	}
+	reporter.gaugeVecs[key] = gaugeVec
+	return gaugeVec, nil
+}
+
 func newHistogramVec(side string) *prometheus.HistogramVec {
 	mc := config.GetMetricConfig()
 	return prometheus.NewHistogramVec(
@@ -172,6 +289,9 @@ func newPrometheusReporter() metrics.Reporter {
 
 				consumerHistogramVec: newHistogramVec(consumerKey),
 				providerHistogramVec: newHistogramVec(providerKey),
+
+				gaugeVecs:   make(map[string]*prometheus.GaugeVec),
+				counterVecs: make(map[string]*prometheus.CounterVec),
 			}
 			prometheus.MustRegister(reporterInstance.consumerSummaryVec, reporterInstance.providerSummaryVec,
 				reporterInstance.consumerHistogramVec, reporterInstance.providerHistogramVec)
@@ -24,6 +24,7 @@ import (
 )
 
 import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,3 +72,41 @@ func TestPrometheusReporter_Report(t *testing.T) {
 	invoker = protocol.NewBaseInvoker(url)
 	reporter.Report(ctx, invoker, inv, 100*time.Millisecond, nil)
 }
+
+func TestPrometheusReporter_ReportGauge(t *testing.T) {
+	reporter := extension.GetMetricReporter(reporterName).(*PrometheusReporter)
+	url, _ := common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&group=test&version=1.0.0&registry.role=3")
+
+	reporter.ReportGauge("concurrency_limit", url, 20)
+	reporter.ReportGauge("concurrency_limit", url, 30)
+	gaugeVec, err := reporter.getGaugeVec(providerKey, "concurrency_limit")
+	assert.Nil(t, err)
+	gauge, err := gaugeVec.GetMetricWithLabelValues("com.ikurento.user.UserProvider", "test", "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(30), testutil.ToFloat64(gauge))
+
+	// invalid role
+	url, _ = common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider&registry.role=9")
+	reporter.ReportGauge("concurrency_limit", url, 10)
+	assert.Len(t, reporter.gaugeVecs, 1)
+}
+
+func TestPrometheusReporter_ReportCounter(t *testing.T) {
+	reporter := extension.GetMetricReporter(reporterName).(*PrometheusReporter)
+	url, _ := common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider" +
+		"&group=test&version=1.0.0&registry.role=3")
+
+	reporter.ReportCounter("timeout_total", url, 1)
+	reporter.ReportCounter("timeout_total", url, 2)
+	counterVec, err := reporter.getCounterVec(providerKey, "timeout_total")
+	assert.Nil(t, err)
+	counter, err := counterVec.GetMetricWithLabelValues("com.ikurento.user.UserProvider", "test", "1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), testutil.ToFloat64(counter))
+
+	// invalid role
+	url, _ = common.NewURL("dubbo://:20000/UserProvider?interface=com.ikurento.user.UserProvider&registry.role=9")
+	reporter.ReportCounter("timeout_total", url, 1)
+	assert.Len(t, reporter.counterVecs, 1)
+}
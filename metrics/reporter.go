@@ -23,6 +23,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
@@ -36,3 +37,17 @@ type Reporter interface {
 	Report(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation,
 		cost time.Duration, res protocol.Result)
 }
+
+// GaugeReporter will be used to report the current value of a gauge, like the concurrency limit of a service.
+// It is optional for the Reporter implementations.
+type GaugeReporter interface {
+	// report the value of the gauge @name of the service which the url belongs to
+	ReportGauge(name string, url *common.URL, value float64)
+}
+
+// CounterReporter will be used to report the increment of a counter, like the number of the timeout invocations.
+// It is optional for the Reporter implementations.
+type CounterReporter interface {
+	// add @value to the counter @name of the service which the url belongs to
+	ReportCounter(name string, url *common.URL, value float64)
+}
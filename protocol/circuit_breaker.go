@@ -0,0 +1,464 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+)
+
+const (
+	// CircuitScopeInvoker shares one circuit breaker among all methods of an invoker
+	CircuitScopeInvoker = "invoker"
+	// CircuitScopeMethod creates a circuit breaker for each method of an invoker
+	CircuitScopeMethod = "method"
+	// CircuitCountWindow aggregates the last N calls
+	CircuitCountWindow = "count"
+	// CircuitTimeWindow aggregates the calls of the last N seconds
+	CircuitTimeWindow = "time"
+
+	defaultCircuitCountWindowSize = 100
+	defaultCircuitTimeWindowSize  = 60
+	defaultCircuitMinimumCalls    = 20
+	defaultCircuitErrorRate       = 50
+	defaultCircuitSlowRate        = 100
+	defaultCircuitSlowDuration    = "60s"
+	defaultCircuitOpenDuration    = "30s"
+	defaultCircuitHalfOpenCalls   = 10
+)
+
+// ErrCircuitBreakerOpen is the cause of the error returned when a call is rejected by an open circuit breaker
+var ErrCircuitBreakerOpen = perrors.New("circuit breaker is open")
+
+var circuitBreakers sync.Map // url key [+ "#" + method name] -> *CircuitBreaker
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int32
+
+const (
+	// CircuitClosed permits all calls and records their outcomes
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all calls until the open duration elapses
+	CircuitOpen
+	// CircuitHalfOpen permits a limited number of calls to probe whether the invoker has recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig is the config of a circuit breaker.
+type CircuitBreakerConfig struct {
+	WindowType string
+	// WindowSize is the number of calls of count window or the seconds of time window
+	WindowSize int
+	// MinimumCalls is the number of calls in the window required before the rates are calculated
+	MinimumCalls int
+	// ErrorRateThreshold is the error rate in percentage over which the breaker opens, 0 disables it
+	ErrorRateThreshold float64
+	// SlowRateThreshold is the slow call rate in percentage over which the breaker opens, 0 disables it
+	SlowRateThreshold float64
+	// SlowDuration is the duration over which a call is slow
+	SlowDuration time.Duration
+	// OpenDuration is the duration the breaker stays open before half-open
+	OpenDuration time.Duration
+	// HalfOpenCalls is the number of calls permitted in half-open state
+	HalfOpenCalls int
+}
+
+// NewCircuitBreakerConfig reads the config of circuit breaker from @url, the method parameters of @methodName
+// override the service parameters if @methodName is not empty.
+func NewCircuitBreakerConfig(url *common.URL, methodName string) CircuitBreakerConfig {
+	get := func(key, def string) string {
+		v := url.GetParam(key, def)
+		if len(methodName) > 0 {
+			v = url.GetMethodParam(methodName, key, v)
+		}
+		return v
+	}
+	getInt := func(key string, def int) int {
+		n, err := strconv.Atoi(get(key, ""))
+		if err != nil || n <= 0 {
+			return def
+		}
+		return n
+	}
+	getRate := func(key string, def float64) float64 {
+		n, err := strconv.ParseFloat(get(key, ""), 64)
+		if err != nil || n < 0 || n > 100 {
+			return def
+		}
+		return n
+	}
+	getDuration := func(key, def string) time.Duration {
+		d, err := time.ParseDuration(get(key, def))
+		if err != nil || d <= 0 {
+			d, _ = time.ParseDuration(def)
+		}
+		return d
+	}
+
+	cfg := CircuitBreakerConfig{
+		WindowType:         get(constant.CIRCUIT_BREAKER_WINDOW_TYPE_KEY, CircuitCountWindow),
+		MinimumCalls:       getInt(constant.CIRCUIT_BREAKER_MINIMUM_CALLS_KEY, defaultCircuitMinimumCalls),
+		ErrorRateThreshold: getRate(constant.CIRCUIT_BREAKER_ERROR_RATE_KEY, defaultCircuitErrorRate),
+		SlowRateThreshold:  getRate(constant.CIRCUIT_BREAKER_SLOW_RATE_KEY, defaultCircuitSlowRate),
+		SlowDuration:       getDuration(constant.CIRCUIT_BREAKER_SLOW_DURATION_KEY, defaultCircuitSlowDuration),
+		OpenDuration:       getDuration(constant.CIRCUIT_BREAKER_OPEN_DURATION_KEY, defaultCircuitOpenDuration),
+		HalfOpenCalls:      getInt(constant.CIRCUIT_BREAKER_HALF_OPEN_CALLS_KEY, defaultCircuitHalfOpenCalls),
+	}
+	if cfg.WindowType == CircuitTimeWindow {
+		cfg.WindowSize = getInt(constant.CIRCUIT_BREAKER_WINDOW_SIZE_KEY, defaultCircuitTimeWindowSize)
+	} else {
+		cfg.WindowType = CircuitCountWindow
+		cfg.WindowSize = getInt(constant.CIRCUIT_BREAKER_WINDOW_SIZE_KEY, defaultCircuitCountWindowSize)
+		// the count window can never hold more calls than its size
+		if cfg.MinimumCalls > cfg.WindowSize {
+			cfg.MinimumCalls = cfg.WindowSize
+		}
+	}
+	return cfg
+}
+
+// CircuitBreakerStateChangedEvent is published when the state of a circuit breaker changes.
+type CircuitBreakerStateChangedEvent struct {
+	observer.BaseEvent
+	Key  string
+	URL  *common.URL
+	From CircuitState
+	To   CircuitState
+}
+
+// NewCircuitBreakerStateChangedEvent creates an event of @breaker changing from @from to @to.
+func NewCircuitBreakerStateChangedEvent(breaker *CircuitBreaker, from, to CircuitState) *CircuitBreakerStateChangedEvent {
+	return &CircuitBreakerStateChangedEvent{
+		BaseEvent: observer.BaseEvent{
+			Source:    breaker,
+			Timestamp: time.Now(),
+		},
+		Key:  breaker.key,
+		URL:  breaker.url,
+		From: from,
+		To:   to,
+	}
+}
+
+// CircuitStateListener is notified when the state of a circuit breaker changes.
+type CircuitStateListener func(event *CircuitBreakerStateChangedEvent)
+
+// CircuitBreaker is a circuit breaker of closed, open and half-open states.
+// It opens when the error rate or slow call rate in the sliding window exceeds the thresholds,
+// turns to half-open after the open duration, and closes if the calls in half-open state are healthy.
+type CircuitBreaker struct {
+	key      string
+	url      *common.URL
+	config   CircuitBreakerConfig
+	listener CircuitStateListener
+
+	mutex          sync.Mutex
+	state          CircuitState
+	openedAt       time.Time
+	window         slidingWindow
+	halfOpenCalls  int
+	halfOpenWindow slidingWindow
+}
+
+// NewCircuitBreaker creates a closed circuit breaker, @listener may be nil.
+func NewCircuitBreaker(key string, url *common.URL, config CircuitBreakerConfig, listener CircuitStateListener) *CircuitBreaker {
+	return &CircuitBreaker{
+		key:      key,
+		url:      url,
+		config:   config,
+		listener: listener,
+		state:    CircuitClosed,
+		window:   newSlidingWindow(config.WindowType, config.WindowSize),
+	}
+}
+
+// GetCircuitBreaker gets the circuit breaker of @methodName of @url, which is shared by all methods of the invoker
+// unless the scope is method. @listener is only used when the circuit breaker is created.
+func GetCircuitBreaker(url *common.URL, methodName string, listener CircuitStateListener) *CircuitBreaker {
+	key := url.Key()
+	scope := url.GetMethodParam(methodName, constant.CIRCUIT_BREAKER_SCOPE_KEY,
+		url.GetParam(constant.CIRCUIT_BREAKER_SCOPE_KEY, CircuitScopeInvoker))
+	if scope == CircuitScopeMethod {
+		key += "#" + methodName
+	} else {
+		methodName = ""
+	}
+	breaker, found := circuitBreakers.Load(key)
+	if !found {
+		breaker, _ = circuitBreakers.LoadOrStore(key, NewCircuitBreaker(key, url, NewCircuitBreakerConfig(url, methodName), listener))
+	}
+	return breaker.(*CircuitBreaker)
+}
+
+// IsCircuitBreakerOpen checks whether the invoker or the @methodName of the invoker of @url is broken.
+func IsCircuitBreakerOpen(url *common.URL, methodName string) bool {
+	key := url.Key()
+	for _, k := range []string{key, key + "#" + methodName} {
+		if breaker, found := circuitBreakers.Load(k); found && breaker.(*CircuitBreaker).IsOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveCircuitBreakers removes the circuit breakers of the invoker and all its methods of @url
+func RemoveCircuitBreakers(url *common.URL) {
+	key := url.Key()
+	circuitBreakers.Range(func(k, _ interface{}) bool {
+		if k == key || strings.HasPrefix(k.(string), key+"#") {
+			circuitBreakers.Delete(k)
+		}
+		return true
+	})
+}
+
+// CleanAllCircuitBreakers cleans all circuit breakers
+func CleanAllCircuitBreakers() {
+	circuitBreakers.Range(func(key, _ interface{}) bool {
+		circuitBreakers.Delete(key)
+		return true
+	})
+}
+
+// Key gets the key of the circuit breaker.
+func (cb *CircuitBreaker) Key() string {
+	return cb.key
+}
+
+// State gets the current state.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.state
+}
+
+// IsOpen checks whether the breaker is open and still rejects calls.
+func (cb *CircuitBreaker) IsOpen() bool {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.state == CircuitOpen && time.Since(cb.openedAt) < cb.config.OpenDuration
+}
+
+// Allow checks whether a call is permitted. The open breaker turns to half-open after the open duration,
+// and only the configured number of calls are permitted in half-open state.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mutex.Lock()
+	var event *CircuitBreakerStateChangedEvent
+	allowed := true
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cb.config.OpenDuration {
+			allowed = false
+			break
+		}
+		event = cb.transition(CircuitHalfOpen)
+		cb.halfOpenCalls = 1
+	case CircuitHalfOpen:
+		if cb.halfOpenCalls >= cb.config.HalfOpenCalls {
+			allowed = false
+			break
+		}
+		cb.halfOpenCalls++
+	}
+	cb.mutex.Unlock()
+	cb.notify(event)
+	return allowed
+}
+
+// OnResult records the outcome of a permitted call.
+func (cb *CircuitBreaker) OnResult(elapsed time.Duration, failed bool) {
+	slow := elapsed >= cb.config.SlowDuration
+	now := time.Now()
+
+	cb.mutex.Lock()
+	var event *CircuitBreakerStateChangedEvent
+	switch cb.state {
+	case CircuitClosed:
+		stats := cb.window.record(now, failed, slow)
+		if stats.calls >= cb.config.MinimumCalls && cb.exceeded(stats) {
+			event = cb.transition(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		stats := cb.halfOpenWindow.record(now, failed, slow)
+		if !cb.tolerable(stats) {
+			event = cb.transition(CircuitOpen)
+		} else if stats.calls >= cb.config.HalfOpenCalls {
+			event = cb.transition(CircuitClosed)
+		}
+	}
+	// the outcomes of the calls finishing after the breaker opens are ignored
+	cb.mutex.Unlock()
+	cb.notify(event)
+}
+
+// exceeded checks whether the error rate or slow call rate of @stats reaches the thresholds
+func (cb *CircuitBreaker) exceeded(stats windowStats) bool {
+	if stats.calls == 0 {
+		return false
+	}
+	if cb.config.ErrorRateThreshold > 0 &&
+		float64(stats.failures)*100/float64(stats.calls) >= cb.config.ErrorRateThreshold {
+		return true
+	}
+	return cb.config.SlowRateThreshold > 0 &&
+		float64(stats.slowCalls)*100/float64(stats.calls) >= cb.config.SlowRateThreshold
+}
+
+// tolerable checks whether the rates of half-open calls may still stay under the thresholds
+// if all the remaining permitted calls succeed quickly
+func (cb *CircuitBreaker) tolerable(stats windowStats) bool {
+	best := stats
+	best.calls = cb.config.HalfOpenCalls
+	return !cb.exceeded(best)
+}
+
+// transition changes the state and resets the windows, it must be called with the lock held
+func (cb *CircuitBreaker) transition(to CircuitState) *CircuitBreakerStateChangedEvent {
+	from := cb.state
+	cb.state = to
+	switch to {
+	case CircuitOpen:
+		cb.openedAt = time.Now()
+	case CircuitHalfOpen:
+		cb.halfOpenCalls = 0
+		cb.halfOpenWindow = newSlidingWindow(CircuitCountWindow, cb.config.HalfOpenCalls)
+	case CircuitClosed:
+		cb.window = newSlidingWindow(cb.config.WindowType, cb.config.WindowSize)
+	}
+	if cb.listener == nil {
+		return nil
+	}
+	return NewCircuitBreakerStateChangedEvent(cb, from, to)
+}
+
+func (cb *CircuitBreaker) notify(event *CircuitBreakerStateChangedEvent) {
+	if event != nil {
+		cb.listener(event)
+	}
+}
+
+// windowStats is the aggregation of the calls in a sliding window
+type windowStats struct {
+	calls     int
+	failures  int
+	slowCalls int
+}
+
+func (s *windowStats) add(failed, slow bool, delta int) {
+	s.calls += delta
+	if failed {
+		s.failures += delta
+	}
+	if slow {
+		s.slowCalls += delta
+	}
+}
+
+// slidingWindow records the outcomes of calls and returns the aggregation of the window
+type slidingWindow interface {
+	record(now time.Time, failed, slow bool) windowStats
+}
+
+func newSlidingWindow(windowType string, size int) slidingWindow {
+	if windowType == CircuitTimeWindow {
+		return &timeWindow{buckets: make([]timeBucket, size)}
+	}
+	return &countWindow{outcomes: make([]callOutcome, size)}
+}
+
+type callOutcome struct {
+	failed bool
+	slow   bool
+}
+
+// countWindow aggregates the last len(outcomes) calls by a ring buffer
+type countWindow struct {
+	outcomes []callOutcome
+	next     int
+	full     bool
+	stats    windowStats
+}
+
+func (w *countWindow) record(_ time.Time, failed, slow bool) windowStats {
+	if w.full {
+		evicted := w.outcomes[w.next]
+		w.stats.add(evicted.failed, evicted.slow, -1)
+	}
+	w.outcomes[w.next] = callOutcome{failed: failed, slow: slow}
+	w.stats.add(failed, slow, 1)
+	w.next = (w.next + 1) % len(w.outcomes)
+	if w.next == 0 {
+		w.full = true
+	}
+	return w.stats
+}
+
+type timeBucket struct {
+	second int64
+	stats  windowStats
+}
+
+// timeWindow aggregates the calls of the last len(buckets) seconds by a bucket per second
+type timeWindow struct {
+	buckets []timeBucket
+}
+
+func (w *timeWindow) record(now time.Time, failed, slow bool) windowStats {
+	second := now.Unix()
+	size := int64(len(w.buckets))
+	bucket := &w.buckets[second%size]
+	if bucket.second != second {
+		*bucket = timeBucket{second: second}
+	}
+	bucket.stats.add(failed, slow, 1)
+
+	var stats windowStats
+	for _, b := range w.buckets {
+		if b.second > second-size {
+			stats.calls += b.stats.calls
+			stats.failures += b.stats.failures
+			stats.slowCalls += b.stats.slowCalls
+		}
+	}
+	return stats
+}
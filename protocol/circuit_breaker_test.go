@@ -0,0 +1,187 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func newTestCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		WindowType:         CircuitCountWindow,
+		WindowSize:         10,
+		MinimumCalls:       4,
+		ErrorRateThreshold: 50,
+		SlowRateThreshold:  100,
+		SlowDuration:       time.Second,
+		OpenDuration:       50 * time.Millisecond,
+		HalfOpenCalls:      2,
+	}
+}
+
+func TestNewCircuitBreakerConfig(t *testing.T) {
+	url, _ := common.NewURL("dubbo://192.168.10.10:20000/com.ikurento.user.UserProvider?" +
+		"circuitbreaker.window.size=10&circuitbreaker.minimum.calls=50&circuitbreaker.error.rate=30&" +
+		"methods.GetUser.circuitbreaker.window.type=time&methods.GetUser.circuitbreaker.open.duration=5s&" +
+		"methods.GetUser.circuitbreaker.slow.rate=200")
+
+	cfg := NewCircuitBreakerConfig(url, "")
+	assert.Equal(t, CircuitCountWindow, cfg.WindowType)
+	assert.Equal(t, 10, cfg.WindowSize)
+	assert.Equal(t, 10, cfg.MinimumCalls)
+	assert.Equal(t, float64(30), cfg.ErrorRateThreshold)
+	assert.Equal(t, 30*time.Second, cfg.OpenDuration)
+
+	cfg = NewCircuitBreakerConfig(url, "GetUser")
+	assert.Equal(t, CircuitTimeWindow, cfg.WindowType)
+	assert.Equal(t, 10, cfg.WindowSize)
+	assert.Equal(t, 50, cfg.MinimumCalls)
+	assert.Equal(t, float64(defaultCircuitSlowRate), cfg.SlowRateThreshold)
+	assert.Equal(t, 5*time.Second, cfg.OpenDuration)
+	assert.Equal(t, 60*time.Second, cfg.SlowDuration)
+	assert.Equal(t, defaultCircuitHalfOpenCalls, cfg.HalfOpenCalls)
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	var events []*CircuitBreakerStateChangedEvent
+	breaker := NewCircuitBreaker("test", nil, newTestCircuitBreakerConfig(), func(event *CircuitBreakerStateChangedEvent) {
+		events = append(events, event)
+	})
+
+	// not enough calls
+	for i := 0; i < 3; i++ {
+		assert.True(t, breaker.Allow())
+		breaker.OnResult(0, true)
+	}
+	assert.Equal(t, CircuitClosed, breaker.State())
+	assert.True(t, breaker.Allow())
+	breaker.OnResult(0, false)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.True(t, breaker.IsOpen())
+	assert.False(t, breaker.Allow())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, breaker.IsOpen())
+	assert.True(t, breaker.Allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow())
+	breaker.OnResult(0, true)
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, breaker.Allow())
+	assert.True(t, breaker.Allow())
+	breaker.OnResult(0, false)
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	breaker.OnResult(0, false)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	expected := [][2]CircuitState{
+		{CircuitClosed, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitOpen},
+		{CircuitOpen, CircuitHalfOpen},
+		{CircuitHalfOpen, CircuitClosed},
+	}
+	assert.Len(t, events, len(expected))
+	for i, e := range expected {
+		assert.Equal(t, "test", events[i].Key)
+		assert.Equal(t, breaker, events[i].GetSource())
+		assert.Equal(t, e[0], events[i].From)
+		assert.Equal(t, e[1], events[i].To)
+	}
+
+	// the window is reset after closed
+	for i := 0; i < 3; i++ {
+		breaker.OnResult(0, true)
+	}
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerSlowCalls(t *testing.T) {
+	cfg := newTestCircuitBreakerConfig()
+	cfg.ErrorRateThreshold = 0
+	cfg.SlowRateThreshold = 80
+	breaker := NewCircuitBreaker("test", nil, cfg, nil)
+	breaker.OnResult(time.Second, true)
+	breaker.OnResult(2*time.Second, false)
+	breaker.OnResult(time.Millisecond, true)
+	breaker.OnResult(2*time.Second, false)
+	assert.Equal(t, CircuitClosed, breaker.State())
+	breaker.OnResult(2*time.Second, false)
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func TestCountWindow(t *testing.T) {
+	w := newSlidingWindow(CircuitCountWindow, 3)
+	now := time.Now()
+	assert.Equal(t, windowStats{calls: 1, failures: 1}, w.record(now, true, false))
+	assert.Equal(t, windowStats{calls: 2, failures: 1, slowCalls: 1}, w.record(now, false, true))
+	assert.Equal(t, windowStats{calls: 3, failures: 1, slowCalls: 1}, w.record(now, false, false))
+	assert.Equal(t, windowStats{calls: 3, slowCalls: 1}, w.record(now, false, false))
+	assert.Equal(t, windowStats{calls: 3}, w.record(now, false, false))
+}
+
+func TestTimeWindow(t *testing.T) {
+	w := newSlidingWindow(CircuitTimeWindow, 3)
+	now := time.Unix(1000, 0)
+	assert.Equal(t, windowStats{calls: 1, failures: 1}, w.record(now, true, false))
+	assert.Equal(t, windowStats{calls: 2, failures: 2}, w.record(now.Add(time.Second), true, false))
+	assert.Equal(t, windowStats{calls: 3, failures: 2}, w.record(now.Add(2*time.Second), false, false))
+	// the calls of the first second slide out
+	assert.Equal(t, windowStats{calls: 3, failures: 1, slowCalls: 1}, w.record(now.Add(3*time.Second), false, true))
+	// the calls long ago are not counted though their buckets are not reused
+	assert.Equal(t, windowStats{calls: 1}, w.record(now.Add(10*time.Second), false, false))
+}
+
+func TestGetCircuitBreaker(t *testing.T) {
+	defer CleanAllCircuitBreakers()
+	url, _ := common.NewURL("dubbo://192.168.10.10:20000/com.ikurento.user.UserProvider?" +
+		"circuitbreaker.minimum.calls=1&methods.GetUser.circuitbreaker.scope=method")
+
+	breaker := GetCircuitBreaker(url, "Save", nil)
+	assert.Same(t, breaker, GetCircuitBreaker(url, "Delete", nil))
+	methodBreaker := GetCircuitBreaker(url, "GetUser", nil)
+	assert.NotSame(t, breaker, methodBreaker)
+	assert.Equal(t, url.Key()+"#GetUser", methodBreaker.Key())
+
+	methodBreaker.OnResult(0, true)
+	assert.True(t, IsCircuitBreakerOpen(url, "GetUser"))
+	assert.False(t, IsCircuitBreakerOpen(url, "Save"))
+
+	breaker.OnResult(0, true)
+	assert.True(t, IsCircuitBreakerOpen(url, "Save"))
+	assert.True(t, IsCircuitBreakerOpen(url, "Delete"))
+
+	// the breakers are removed with the destroyed invoker
+	NewBaseInvoker(url).Destroy()
+	assert.False(t, IsCircuitBreakerOpen(url, "Save"))
+	assert.False(t, IsCircuitBreakerOpen(url, "GetUser"))
+	_, found := circuitBreakers.Load(url.Key() + "#GetUser")
+	assert.False(t, found)
+}
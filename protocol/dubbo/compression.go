@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"strconv"
+	"strings"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	_ "dubbo.apache.org/dubbo-go/v3/remoting/compression/compressor_impl"
+)
+
+// compressionAttachmentKey is the key of compressionOption of the response in the result attachments,
+// it is removed before the response is encoded
+const compressionAttachmentKey = "_compression"
+
+// compressionOption is the compressor of the message body negotiated with the peer
+type compressionOption struct {
+	name      string
+	threshold int
+}
+
+// getCompressionOption returns the compressor configured in @url if it is accepted by the peer
+func getCompressionOption(url *common.URL, accepted string) (compressionOption, bool) {
+	name := url.GetParam(constant.COMPRESSION_KEY, "")
+	if len(name) == 0 || !isCompressorAccepted(accepted, name) {
+		return compressionOption{}, false
+	}
+	threshold, err := strconv.Atoi(url.GetParam(constant.COMPRESSION_THRESHOLD_KEY, ""))
+	if err != nil || threshold < 0 {
+		threshold = constant.DEFAULT_COMPRESSION_THRESHOLD
+	}
+	return compressionOption{name: name, threshold: threshold}, true
+}
+
+// isCompressorAccepted checks whether @name is in the comma separated @accepted
+func isCompressorAccepted(accepted string, name string) bool {
+	for _, n := range strings.Split(accepted, ",") {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptedCompressors returns the names of compressors which could decompress the received bodies
+func acceptedCompressors() string {
+	return strings.Join(extension.GetCompressorNames(), ",")
+}
+
+// negotiateRequestCompression advertises the compressors accepted by consumer, and compresses the request
+// by the compressor configured in consumer if the provider has accepted it in previous responses
+func (di *DubboInvoker) negotiateRequestCompression(inv *invocation_impl.RPCInvocation) {
+	accepted := acceptedCompressors()
+	if len(accepted) == 0 {
+		return
+	}
+	inv.SetAttachments(constant.ACCEPT_COMPRESSION_KEY, accepted)
+	if option, ok := getCompressionOption(di.GetURL(), di.providerCompressors.Load()); ok {
+		inv.SetAttribute(compressionAttachmentKey, option)
+	}
+}
+
+// updateProviderCompressors records the compressors accepted by provider in the response @attachments
+func (di *DubboInvoker) updateProviderCompressors(attachments map[string]interface{}) {
+	if accepted, ok := attachments[constant.ACCEPT_COMPRESSION_KEY].(string); ok {
+		di.providerCompressors.Store(accepted)
+	}
+}
+
+// negotiateResponseCompression advertises the compressors accepted by provider in the response @attachments,
+// and compresses the response by the compressor configured in provider @url if the consumer accepts it
+func negotiateResponseCompression(attachments map[string]interface{}, url *common.URL, consumerAccepted string) {
+	if accepted := acceptedCompressors(); len(accepted) > 0 {
+		attachments[constant.ACCEPT_COMPRESSION_KEY] = accepted
+	}
+	if option, ok := getCompressionOption(url, consumerAccepted); ok {
+		attachments[compressionAttachmentKey] = option
+	}
+}
+
+// setCodecCompression sets the compressor negotiated in @option to @codec
+func setCodecCompression(codec *impl.ProtocolCodec, option interface{}) {
+	if o, ok := option.(compressionOption); ok {
+		codec.SetCompressor(o.name, o.threshold)
+	}
+}
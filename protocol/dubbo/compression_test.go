@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestGetCompressionOption(t *testing.T) {
+	url, _ := common.NewURL("dubbo://127.0.0.1:20000/UserProvider?compression=zstd")
+	_, ok := getCompressionOption(url, "")
+	assert.False(t, ok)
+	_, ok = getCompressionOption(url, "gzip,snappy")
+	assert.False(t, ok)
+	option, ok := getCompressionOption(url, "gzip, zstd")
+	assert.True(t, ok)
+	assert.Equal(t, compressionOption{name: "zstd", threshold: constant.DEFAULT_COMPRESSION_THRESHOLD}, option)
+
+	url.SetParam(constant.COMPRESSION_THRESHOLD_KEY, "0")
+	option, _ = getCompressionOption(url, "zstd")
+	assert.Equal(t, 0, option.threshold)
+}
+
+func TestDubboInvokerCompression(t *testing.T) {
+	proto, url := InitTest(t)
+	defer proto.Destroy()
+
+	_, err := common.ServiceMap.Register("com.ikurento.user.UserProvider", "dubbo", "compression", "", &UserProvider{})
+	assert.NoError(t, err)
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20702/UserProvider?interface=com.ikurento.user.UserProvider&" +
+		"group=compression&compression=gzip&compression.threshold=0&side=provider&bean.name=UserProvider")
+	proto.Export(&proxy_factory.ProxyInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(providerURL),
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20702/UserProvider?interface=com.ikurento.user.UserProvider&" +
+		"group=compression&compression=snappy&compression.threshold=0&timeout=3000")
+	invoker := NewDubboInvoker(consumerURL, getExchangeClient(url))
+	name := strings.Repeat("username", 200)
+
+	// the request is not compressed before the provider tells its compressors, and the response is compressed by gzip
+	user := &User{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"1", name}), invocation.WithReply(user))
+	res := invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, User{ID: "1", Name: name}, *user)
+	assert.Nil(t, inv.AttributeByKey(compressionAttachmentKey, nil))
+	assert.Equal(t, "gzip,snappy,zstd", invoker.providerCompressors.Load())
+	_, ok := res.Attachments()[compressionAttachmentKey]
+	assert.False(t, ok)
+
+	// the request is compressed by snappy
+	user = &User{}
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"2", name}), invocation.WithReply(user))
+	res = invoker.Invoke(context.Background(), inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, User{ID: "2", Name: name}, *user)
+	assert.Equal(t, compressionOption{name: "snappy"}, inv.AttributeByKey(compressionAttachmentKey, nil))
+}
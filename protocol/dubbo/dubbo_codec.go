@@ -76,11 +76,13 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 	svc.Timeout = time.Duration(timeout)
 
 	header := impl.DubboHeader{}
-	serialization := invocation.AttachmentsByKey(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION)
-	if serialization == constant.PROTOBUF_SERIALIZATION {
-		header.SerialID = constant.S_Proto
-	} else {
-		header.SerialID = constant.S_Hessian2
+	// the serialization in attachments is preferred to the one of reference
+	serialization := invocation.AttachmentsByKey(constant.SERIALIZATION_KEY, "")
+	if serialization == "" {
+		serialization, _ = invocation.AttributeByKey(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION).(string)
+	}
+	if header.SerialID, err = impl.GetSerialIdByName(serialization); err != nil {
+		return nil, err
 	}
 	header.ID = request.ID
 	if request.TwoWay {
@@ -100,6 +102,7 @@ func (c *DubboCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer, er
 	if err := impl.LoadSerializer(pkg); err != nil {
 		return nil, perrors.WithStack(err)
 	}
+	setCodecCompression(pkg.Codec, invocation.AttributeByKey(compressionAttachmentKey, nil))
 
 	return pkg.Marshal()
 }
@@ -140,17 +143,44 @@ func (c *DubboCodec) EncodeResponse(response *remoting.Response) (*bytes.Buffer,
 			ResponseStatus: response.Status,
 		},
 	}
-	if !response.IsHeartbeat() {
+	codec := impl.NewDubboCodec(nil)
+	if serializer, err := impl.GetSerializerById(response.SerialID); err == nil {
+		codec.SetSerializer(serializer.(impl.Serializer))
+	} else {
+		// the serialization of request is unknown, reply in hessian2
+		resp.Header.SerialID = constant.S_Hessian2
+	}
+	if _, ok := response.Result.(protocol.RPCResult); !ok && !response.IsHeartbeat() {
+		// the request failed before being invoked
+		resp.Body = &impl.ResponsePayload{Exception: response.Error}
+	} else if !response.IsHeartbeat() {
+		attachments := response.Result.(protocol.RPCResult).Attrs
+		if option, ok := attachments[compressionAttachmentKey]; ok {
+			setCodecCompression(codec, option)
+			// the compression option is only for the codec
+			attachments = make(map[string]interface{}, len(attachments))
+			for k, v := range response.Result.(protocol.RPCResult).Attrs {
+				if k != compressionAttachmentKey {
+					attachments[k] = v
+				}
+			}
+		}
 		resp.Body = &impl.ResponsePayload{
 			RspObj:      response.Result.(protocol.RPCResult).Rest,
 			Exception:   response.Result.(protocol.RPCResult).Err,
-			Attachments: response.Result.(protocol.RPCResult).Attrs,
+			Attachments: encodableErrorDetails(attachments, resp.Header.SerialID),
 		}
 	}
 
-	codec := impl.NewDubboCodec(nil)
-
 	pkg, err := codec.Encode(*resp)
+	if payload, ok := resp.Body.(*impl.ResponsePayload); ok && err != nil {
+		// the response is replied without the details of error which may not be encoded
+		if attachments := withoutErrorDetails(payload.Attachments); len(attachments) < len(payload.Attachments) {
+			logger.Warnf("The details of rpc error are dropped since the response can not be encoded, error: %v", err)
+			payload.Attachments = attachments
+			pkg, err = codec.Encode(*resp)
+		}
+	}
 	if err != nil {
 		return nil, perrors.WithStack(err)
 	}
@@ -193,6 +223,16 @@ func (c *DubboCodec) decodeRequest(data []byte) (*remoting.Request, int, error)
 			return nil, 0, originErr
 		}
 		logger.Errorf("pkg.Unmarshal(len(@data):%d) = error:%+v", buf.Len(), err)
+		if originErr == impl.ErrUnknownSerialization {
+			// the body is skipped and an error response will be replied
+			request = &remoting.Request{
+				ID:       pkg.Header.ID,
+				SerialID: pkg.Header.SerialID,
+				TwoWay:   pkg.Header.Type&impl.PackageRequest_TwoWay != 0x00,
+				Data:     err,
+			}
+			return request, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
+		}
 
 		return request, 0, perrors.WithStack(err)
 	}
@@ -274,7 +314,9 @@ func (c *DubboCodec) decodeResponse(data []byte) (*remoting.Response, int, error
 		if pkg.Err != nil {
 			rpcResult.Err = pkg.Err
 		} else if pkg.Body.(*impl.ResponsePayload).Exception != nil {
-			rpcResult.Err = pkg.Body.(*impl.ResponsePayload).Exception
+			// the code carried by the attachments is removed from them
+			rpcResult.Err = toRPCError(pkg.Header.ResponseStatus, pkg.Body.(*impl.ResponsePayload).Exception,
+				pkg.Body.(*impl.ResponsePayload).Attachments)
 			response.Error = rpcResult.Err
 		}
 		rpcResult.Attrs = pkg.Body.(*impl.ResponsePayload).Attachments
@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"testing"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestDubboCodecUnknownSerialization(t *testing.T) {
+	codec := &DubboCodec{}
+	var inv protocol.Invocation = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"1"}))
+	request := remoting.NewRequest("2.0.2")
+	request.TwoWay = true
+	request.Data = &inv
+	buf, err := codec.EncodeRequest(request)
+	assert.NoError(t, err)
+
+	// the serialization id of request is unknown for provider
+	data := buf.Bytes()
+	data[2] = data[2]&^impl.SERIAL_MASK | 31
+	result, length, err := codec.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), length)
+	req := result.Result.(*remoting.Request)
+	assert.Equal(t, request.ID, req.ID)
+	assert.True(t, req.TwoWay)
+	assert.Equal(t, impl.ErrUnknownSerialization, perrors.Cause(req.Data.(error)))
+
+	// the error response is replied in hessian2
+	response := remoting.NewResponse(req.ID, req.Version)
+	response.SerialID = req.SerialID
+	response.Status = hessian.Response_BAD_REQUEST
+	response.Error = req.Data.(error)
+	buf, err = codec.EncodeResponse(response)
+	assert.NoError(t, err)
+	result, _, err = codec.Decode(buf.Bytes())
+	assert.NoError(t, err)
+	resp := result.Result.(*remoting.Response)
+	assert.Equal(t, constant.S_Hessian2, resp.SerialID)
+	assert.Equal(t, hessian.Response_BAD_REQUEST, resp.Status)
+	assert.Contains(t, resp.Error.Error(), "serialId 31 not found")
+
+	// the unknown serialization is rejected by consumer
+	inv.SetAttachments(constant.SERIALIZATION_KEY, "unknown")
+	_, err = codec.EncodeRequest(request)
+	assert.Equal(t, impl.ErrUnknownSerialization, perrors.Cause(err))
+}
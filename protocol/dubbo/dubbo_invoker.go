@@ -19,6 +19,7 @@ package dubbo
 
 import (
 	"context"
+	"io"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,6 +28,7 @@ import (
 
 import (
 	"github.com/opentracing/opentracing-go"
+	uberAtomic "go.uber.org/atomic"
 )
 
 import (
@@ -53,6 +55,8 @@ type DubboInvoker struct {
 	quitOnce    sync.Once
 	// timeout for service(interface) level.
 	timeout time.Duration
+	// the compressors accepted by provider, which are told in the response attachments
+	providerCompressors *uberAtomic.String
 }
 
 // NewDubboInvoker constructor
@@ -68,6 +72,8 @@ func NewDubboInvoker(url *common.URL, client *remoting.ExchangeClient) *DubboInv
 		clientGuard: &sync.RWMutex{},
 		client:      client,
 		timeout:     requestTimeout,
+
+		providerCompressors: uberAtomic.NewString(""),
 	}
 
 	return di
@@ -129,12 +135,19 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 
 	// put the ctx into attachment
 	di.appendCtx(ctx, inv)
+	// the stream is sent in chunks after the request rather than in attachments
+	if stream, ok := inv.Attachment(constant.STREAM_KEY).(io.Reader); ok {
+		delete(inv.Attachments(), constant.STREAM_KEY)
+		inv.SetAttribute(constant.STREAM_KEY, stream)
+	}
+	di.negotiateRequestCompression(inv)
 
 	url := di.GetURL()
 	// default hessian2 serialization, compatible
 	if url.GetParam(constant.SERIALIZATION_KEY, "") == "" {
 		url.SetParam(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION)
 	}
+	inv.SetAttribute(constant.SERIALIZATION_KEY, url.GetParam(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION))
 	// async
 	async, err := strconv.ParseBool(inv.AttachmentsByKey(constant.ASYNC_KEY, "false"))
 	if err != nil {
@@ -144,6 +157,7 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	// response := NewResponse(inv.Reply(), nil)
 	rest := &protocol.RPCResult{}
 	timeout := di.getTimeout(inv)
+	protocol.AttachRemainingTimeout(ctx, inv, timeout)
 	if async {
 		if callBack, ok := inv.CallBack().(func(response common.CallbackResponse)); ok {
 			result.Err = di.client.AsyncRequest(&invocation, url, timeout, callBack, rest)
@@ -160,6 +174,7 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 	if result.Err == nil {
 		result.Rest = inv.Reply()
 		result.Attrs = rest.Attrs
+		di.updateProviderCompressors(rest.Attrs)
 	}
 	logger.Debugf("result.Err: %v, result.Rest: %v", result.Err, result.Rest)
 
@@ -20,6 +20,8 @@ package dubbo
 import (
 	"bytes"
 	"context"
+	"errors"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -30,6 +32,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	perrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 )
 
 import (
@@ -93,6 +96,61 @@ func TestDubboInvokerInvoke(t *testing.T) {
 	proto.Destroy()
 }
 
+func TestDubboInvokerSerialization(t *testing.T) {
+	proto, url := InitTest(t)
+	defer proto.Destroy()
+
+	for _, serialization := range []string{constant.FASTJSON_SERIALIZATION, constant.MSGPACK_SERIALIZATION, constant.PROTOBUF_JSON_SERIALIZATION} {
+		consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20702/UserProvider?interface=com.ikurento.user.UserProvider&" +
+			"timeout=3000&serialization=" + serialization)
+		invoker := NewDubboInvoker(consumerURL, getExchangeClient(url))
+		user := &User{}
+		inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser0"),
+			invocation.WithArguments([]interface{}{"1", &User{ID: "2"}, "username"}), invocation.WithReply(user))
+		res := invoker.Invoke(context.Background(), inv)
+		assert.NoError(t, res.Error(), serialization)
+		assert.Equal(t, User{ID: "1", Name: "username"}, *user, serialization)
+	}
+
+	// the unknown serialization is rejected by the consumer
+	consumerURL, _ := common.NewURL("dubbo://127.0.0.1:20702/UserProvider?interface=com.ikurento.user.UserProvider&" +
+		"timeout=3000&serialization=unknown")
+	invoker := NewDubboInvoker(consumerURL, getExchangeClient(url))
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser0"),
+		invocation.WithArguments([]interface{}{"1", nil, "username"}), invocation.WithReply(&User{}))
+	assert.Error(t, invoker.Invoke(context.Background(), inv).Error())
+}
+
+func TestDubboInvokerRPCError(t *testing.T) {
+	proto, url := InitTest(t)
+	defer proto.Destroy()
+
+	invoker := NewDubboInvoker(url, getExchangeClient(url))
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser6"),
+		invocation.WithArguments([]interface{}{int64(-1)}), invocation.WithReply(&User{}))
+	res := invoker.Invoke(context.Background(), inv)
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(res.Error(), &rpcErr))
+	assert.Equal(t, codes.InvalidArgument, rpcErr.Code)
+	assert.Equal(t, "invalid id", rpcErr.Message)
+	assert.Equal(t, []interface{}{&User{ID: "-1"}}, rpcErr.Details)
+	assert.NotContains(t, res.Attachments(), constant.RPC_ERROR_CODE_KEY)
+
+	// the details can not be encoded are dropped
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser6"),
+		invocation.WithArguments([]interface{}{int64(-2)}), invocation.WithReply(&User{}))
+	res = invoker.Invoke(context.Background(), inv)
+	assert.True(t, errors.As(res.Error(), &rpcErr))
+	assert.Equal(t, codes.InvalidArgument, rpcErr.Code)
+	assert.Equal(t, []interface{}{&User{ID: "-2"}}, rpcErr.Details)
+
+	// the error which isn't RPCError is mapped to codes.Unknown
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser2"), invocation.WithReply(&User{}))
+	res = invoker.Invoke(context.Background(), inv)
+	assert.True(t, errors.As(res.Error(), &rpcErr))
+	assert.Equal(t, codes.Unknown, rpcErr.Code)
+}
+
 func InitTest(t *testing.T) (protocol.Protocol, *common.URL) {
 	hessian.RegisterPOJO(&User{})
 
@@ -217,6 +275,13 @@ func (u *UserProvider) GetUser6(id int64) (*User, error) {
 	if id == 0 {
 		return nil, nil
 	}
+	if id < 0 {
+		user := &User{ID: strconv.FormatInt(id, 10)}
+		if id == -2 {
+			return nil, protocol.NewRPCError(codes.InvalidArgument, "invalid id", user, &unencodableDetail{})
+		}
+		return nil, protocol.NewRPCError(codes.InvalidArgument, "invalid id", user)
+	}
 	return &User{ID: "1"}, nil
 }
 
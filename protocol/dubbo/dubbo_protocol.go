@@ -20,6 +20,7 @@ package dubbo
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 )
@@ -35,6 +36,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
@@ -159,8 +161,31 @@ func doHandleRequest(rpcInvocation *invocation.RPCInvocation) protocol.RPCResult
 		ctx := rebuildCtx(rpcInvocation)
 
 		invokeResult := invoker.Invoke(ctx, rpcInvocation)
+		consumerCompressors := rpcInvocation.AttachmentsByKey(constant.ACCEPT_COMPRESSION_KEY, "")
+		if attachments := invokeResult.Attachments(); len(attachments) > 0 || len(consumerCompressors) > 0 {
+			result.Attrs = make(map[string]interface{}, len(attachments)+3)
+			for k, v := range attachments {
+				result.Attrs[k] = v
+			}
+			// the response attachments are encoded only if the dubbo version of consumer supports them
+			result.Attrs[impl.DUBBO_VERSION_KEY] = rpcInvocation.AttachmentsByKey(impl.DUBBO_VERSION_KEY, "")
+		}
+		// only the consumer accepting compressors knows the compressed responses
+		if len(consumerCompressors) > 0 {
+			negotiateResponseCompression(result.Attrs, invoker.GetURL(), consumerCompressors)
+		}
 		if err := invokeResult.Error(); err != nil {
 			result.Err = invokeResult.Error()
+			// the code and details of RPCError are carried by the response attachments
+			attachments := result.Attrs
+			if attachments == nil {
+				attachments = map[string]interface{}{
+					impl.DUBBO_VERSION_KEY: rpcInvocation.AttachmentsByKey(impl.DUBBO_VERSION_KEY, ""),
+				}
+			}
+			if setRPCErrorAttachments(attachments, err) {
+				result.Attrs = attachments
+			}
 			// p.Header.ResponseStatus = hessian.Response_OK
 			// p.Body = hessian.NewResponse(nil, err, result.Attachments())
 		} else {
@@ -223,6 +248,13 @@ func getExchangeClient(url *common.URL) *remoting.ExchangeClient {
 // now we only support rebuild the tracing context
 func rebuildCtx(inv *invocation.RPCInvocation) context.Context {
 	ctx := context.WithValue(context.Background(), constant.DubboCtxKey("attachment"), inv.Attachments())
+	if stream, ok := inv.AttributeByKey(constant.STREAM_KEY, nil).(io.Reader); ok {
+		ctx = context.WithValue(ctx, constant.StreamKey, stream)
+	}
+	// the remote address is always overwritten by the getty server
+	if addr, ok := inv.Attachment(constant.REMOTE_ADDR).(string); ok {
+		ctx = context.WithValue(ctx, constant.RemoteAddrKey, addr)
+	}
 
 	// actually, if user do not use any opentracing framework, the err will not be nil.
 	spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.TextMap,
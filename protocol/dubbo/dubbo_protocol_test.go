@@ -18,6 +18,7 @@
 package dubbo
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/remoting/getty"
 )
 
@@ -151,3 +153,20 @@ func TestDubboProtocol_Refer(t *testing.T) {
 	invokersLen = len(proto.(*DubboProtocol).Invokers())
 	assert.Equal(t, 0, invokersLen)
 }
+
+func TestRebuildCtxStream(t *testing.T) {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("Upload"))
+	assert.Nil(t, rebuildCtx(inv).Value(constant.StreamKey))
+
+	stream := strings.NewReader("stream")
+	inv.SetAttribute(constant.STREAM_KEY, stream)
+	assert.Equal(t, stream, rebuildCtx(inv).Value(constant.StreamKey))
+}
+
+func TestRebuildCtxRemoteAddr(t *testing.T) {
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"))
+	assert.Nil(t, rebuildCtx(inv).Value(constant.RemoteAddrKey))
+
+	inv.SetAttachments(constant.REMOTE_ADDR, "10.0.0.1:20000")
+	assert.Equal(t, "10.0.0.1:20000", rebuildCtx(inv).Value(constant.RemoteAddrKey))
+}
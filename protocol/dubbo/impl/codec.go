@@ -29,6 +29,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/remoting"
 )
@@ -39,6 +40,9 @@ type ProtocolCodec struct {
 	bodyLen    int
 	serializer Serializer
 	headerRead bool
+	// compressor is the name of compressor of the bodies larger than compressThreshold
+	compressor        string
+	compressThreshold int
 }
 
 func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
@@ -69,6 +73,7 @@ func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 	if flag != Zero {
 		header.Type |= PackageHeartbeat
 	}
+	header.Compressed = buf[3]&FLAG_COMPRESSED != Zero
 	flag = buf[2] & FLAG_REQUEST
 	if flag != Zero {
 		header.Type |= PackageRequest
@@ -78,7 +83,7 @@ func (c *ProtocolCodec) ReadHeader(header *DubboHeader) error {
 		}
 	} else {
 		header.Type |= PackageResponse
-		header.ResponseStatus = buf[3]
+		header.ResponseStatus = buf[3] &^ FLAG_COMPRESSED
 		if header.ResponseStatus != Response_OK {
 			header.Type |= PackageResponse_Exception
 		}
@@ -135,15 +140,15 @@ func (c *ProtocolCodec) Encode(p DubboPackage) ([]byte, error) {
 	switch header.Type {
 	case PackageHeartbeat:
 		if header.ResponseStatus == Zero {
-			return packRequest(p, c.serializer)
+			return c.packRequest(p)
 		}
-		return packResponse(p, c.serializer)
+		return c.packResponse(p)
 
 	case PackageRequest, PackageRequest_TwoWay:
-		return packRequest(p, c.serializer)
+		return c.packRequest(p)
 
 	case PackageResponse:
-		return packResponse(p, c.serializer)
+		return c.packResponse(p)
 
 	default:
 		return nil, perrors.Errorf("Unrecognized message type: %v", header.Type)
@@ -160,7 +165,20 @@ func (c *ProtocolCodec) Decode(p *DubboPackage) error {
 	if err != nil {
 		return err
 	}
-	if p.IsResponseWithException() {
+	if p.Header.Compressed {
+		if body, err = decompressBody(body); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+	if !p.IsHeartBeat() {
+		// the body is serialized by the serialization in header
+		serializer, err := GetSerializerById(p.Header.SerialID)
+		if err != nil {
+			return err
+		}
+		c.serializer = serializer.(Serializer)
+	}
+	if p.IsResponseWithException() && p.Header.SerialID == constant.S_Hessian2 {
 		logger.Infof("response with exception: %+v", p.Header)
 		decoder := hessian.NewDecoder(body)
 		p.Body = &ResponsePayload{}
@@ -189,7 +207,47 @@ func (c *ProtocolCodec) SetSerializer(serializer Serializer) {
 	c.serializer = serializer
 }
 
-func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
+// SetCompressor sets the compressor of the bodies larger than @threshold bytes,
+// the compressor must be accepted by the peer.
+func (c *ProtocolCodec) SetCompressor(name string, threshold int) {
+	c.compressor = name
+	c.compressThreshold = threshold
+}
+
+// compressBody compresses @body if it is larger than the threshold, the compressed body is
+// {name length(1 byte), name of compressor, compressed data}
+func (c *ProtocolCodec) compressBody(body []byte) ([]byte, bool, error) {
+	if len(c.compressor) == 0 || len(body) < c.compressThreshold {
+		return body, false, nil
+	}
+	compressor, ok := extension.GetCompressor(c.compressor)
+	if !ok {
+		return nil, false, perrors.Errorf("compressor %s is not found", c.compressor)
+	}
+	compressed, err := compressor.Compress(body)
+	if err != nil {
+		return nil, false, perrors.WithStack(err)
+	}
+	out := make([]byte, 0, 1+len(c.compressor)+len(compressed))
+	out = append(out, byte(len(c.compressor)))
+	out = append(out, c.compressor...)
+	return append(out, compressed...), true, nil
+}
+
+// decompressBody decompresses the @body compressed by compressBody
+func decompressBody(body []byte) ([]byte, error) {
+	if len(body) == 0 || len(body) < 1+int(body[0]) {
+		return nil, hessian.ErrIllegalPackage
+	}
+	name := string(body[1 : 1+body[0]])
+	compressor, ok := extension.GetCompressor(name)
+	if !ok {
+		return nil, perrors.Errorf("compressor %s is not found", name)
+	}
+	return compressor.Decompress(body[1+body[0]:], DEFAULT_LEN)
+}
+
+func (c *ProtocolCodec) packRequest(p DubboPackage) ([]byte, error) {
 	var (
 		byteArray []byte
 		pkgLen    int
@@ -223,10 +281,17 @@ func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
 		byteArray = append(byteArray, byte('N'))
 		pkgLen = 1
 	} else {
-		body, err := serializer.Marshal(p)
+		body, err := c.serializer.Marshal(p)
 		if err != nil {
 			return nil, err
 		}
+		body, compressed, err := c.compressBody(body)
+		if err != nil {
+			return nil, err
+		}
+		if compressed {
+			byteArray[3] |= FLAG_COMPRESSED
+		}
 		pkgLen = len(body)
 		if pkgLen > int(DEFAULT_LEN) { // 8M
 			return nil, perrors.Errorf("Data length %d too large, max payload %d", pkgLen, DEFAULT_LEN)
@@ -237,7 +302,7 @@ func packRequest(p DubboPackage, serializer Serializer) ([]byte, error) {
 	return byteArray, nil
 }
 
-func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
+func (c *ProtocolCodec) packResponse(p DubboPackage) ([]byte, error) {
 	var byteArray []byte
 	header := p.Header
 	hb := p.IsHeartBeat()
@@ -259,10 +324,19 @@ func packResponse(p DubboPackage, serializer Serializer) ([]byte, error) {
 	binary.BigEndian.PutUint64(byteArray[4:], uint64(header.ID))
 
 	// body
-	body, err := serializer.Marshal(p)
+	body, err := c.serializer.Marshal(p)
 	if err != nil {
 		return nil, err
 	}
+	if !hb {
+		var compressed bool
+		if body, compressed, err = c.compressBody(body); err != nil {
+			return nil, err
+		}
+		if compressed {
+			byteArray[3] |= FLAG_COMPRESSED
+		}
+	}
 
 	pkgLen := len(body)
 	if pkgLen > int(DEFAULT_LEN) { // 8M
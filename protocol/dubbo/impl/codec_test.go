@@ -18,6 +18,8 @@
 package impl
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,6 +30,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	_ "dubbo.apache.org/dubbo-go/v3/remoting/compression/compressor_impl"
 )
 
 func TestDubboPackage_MarshalAndUnmarshal(t *testing.T) {
@@ -87,3 +90,64 @@ func TestDubboPackage_MarshalAndUnmarshal(t *testing.T) {
 	}
 	assert.Equal(t, tmpData, reassembleBody["attachments"])
 }
+
+func TestDubboPackage_Compression(t *testing.T) {
+	arg := strings.Repeat("compressed argument ", 100)
+	newRequest := func() *DubboPackage {
+		pkg := NewDubboPackage(nil)
+		pkg.Body = []interface{}{arg}
+		pkg.Header.Type = PackageRequest_TwoWay
+		pkg.Header.SerialID = constant.S_Hessian2
+		pkg.Header.ID = 10086
+		pkg.Service.Path = "path"
+		pkg.Service.Method = "Method"
+		pkg.SetSerializer(HessianSerializer{})
+		return pkg
+	}
+
+	plain, err := newRequest().Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, Zero, plain.Bytes()[3]&FLAG_COMPRESSED)
+
+	// the body under the threshold is not compressed
+	pkg := newRequest()
+	pkg.Codec.SetCompressor(constant.GZIP_COMPRESSION, plain.Len())
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, Zero, data.Bytes()[3]&FLAG_COMPRESSED)
+	assert.Equal(t, plain.Len(), data.Len())
+
+	for _, name := range []string{constant.GZIP_COMPRESSION, constant.ZSTD_COMPRESSION, constant.SNAPPY_COMPRESSION} {
+		pkg = newRequest()
+		pkg.Codec.SetCompressor(name, 0)
+		data, err = pkg.Marshal()
+		assert.NoError(t, err)
+		assert.Equal(t, FLAG_COMPRESSED, data.Bytes()[3]&FLAG_COMPRESSED)
+		assert.Less(t, data.Len(), plain.Len())
+
+		pkgres := NewDubboPackage(data)
+		pkgres.SetSerializer(HessianSerializer{})
+		pkgres.Body = make([]interface{}, 7)
+		assert.NoError(t, pkgres.Unmarshal(), name)
+		assert.True(t, pkgres.Header.Compressed)
+		assert.Equal(t, "Method", pkgres.Service.Method)
+		assert.Equal(t, []interface{}{arg}, pkgres.GetBody().(map[string]interface{})["args"])
+	}
+
+	// the status of response is kept
+	pkg = NewDubboPackage(nil)
+	pkg.Header.Type = PackageResponse
+	pkg.Header.SerialID = constant.S_Hessian2
+	pkg.Header.ResponseStatus = Response_SERVER_ERROR
+	pkg.Body = &ResponsePayload{Exception: errors.New(arg)}
+	pkg.SetSerializer(HessianSerializer{})
+	pkg.Codec.SetCompressor(constant.SNAPPY_COMPRESSION, 0)
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+
+	pkgres := NewDubboPackage(data)
+	pkgres.SetSerializer(HessianSerializer{})
+	assert.NoError(t, pkgres.Unmarshal())
+	assert.Equal(t, Response_SERVER_ERROR, pkgres.Header.ResponseStatus)
+	assert.Equal(t, "java exception:"+arg, pkgres.Body.(*ResponsePayload).Exception.Error())
+}
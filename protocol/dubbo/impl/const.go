@@ -195,6 +195,9 @@ const (
 	FLAG_TWOWAY  = byte(0x40)
 	FLAG_EVENT   = byte(0x20) // for heartbeat
 	SERIAL_MASK  = 0x1f
+	// FLAG_COMPRESSED is set in the status byte if the body is compressed,
+	// it is only sent to the peer which accepts the compressor
+	FLAG_COMPRESSED = byte(0x80)
 
 	DUBBO_VERSION                          = "2.5.4"
 	DUBBO_VERSION_KEY                      = "dubbo"
@@ -64,22 +64,7 @@ func marshalResponse(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 		if p.IsHeartBeat() {
 			_ = encoder.Encode(nil)
 		} else {
-			var version string
-			if attachmentVersion, ok := response.Attachments[DUBBO_VERSION_KEY]; ok {
-				version = attachmentVersion.(string)
-			}
-			atta := isSupportResponseAttachment(version)
-
-			var resWithException, resValue, resNullValue int32
-			if atta {
-				resWithException = RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS
-				resValue = RESPONSE_VALUE_WITH_ATTACHMENTS
-				resNullValue = RESPONSE_NULL_VALUE_WITH_ATTACHMENTS
-			} else {
-				resWithException = RESPONSE_WITH_EXCEPTION
-				resValue = RESPONSE_VALUE
-				resNullValue = RESPONSE_NULL_VALUE
-			}
+			atta, resWithException, resValue, resNullValue := getResponseTypes(response)
 
 			if response.Exception != nil { // throw error
 				_ = encoder.Encode(resWithException)
@@ -137,6 +122,13 @@ func marshalRequest(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 		_ = encoder.Encode(v)
 	}
 
+	setRequestAttachments(service, request)
+	_ = encoder.Encode(request.Attachments)
+	return encoder.Buffer(), nil
+}
+
+// setRequestAttachments puts the service info into the attachments of @request
+func setRequestAttachments(service Service, request *RequestPayload) {
 	request.Attachments[PATH_KEY] = service.Path
 	request.Attachments[VERSION_KEY] = service.Version
 	if len(service.Group) > 0 {
@@ -148,9 +140,19 @@ func marshalRequest(encoder *hessian.Encoder, p DubboPackage) ([]byte, error) {
 	if service.Timeout != 0 {
 		request.Attachments[TIMEOUT_KEY] = strconv.Itoa(int(service.Timeout / time.Millisecond))
 	}
+}
 
-	_ = encoder.Encode(request.Attachments)
-	return encoder.Buffer(), nil
+// getResponseTypes returns whether the attachments can be written into @response,
+// and the response types of exception, value and null value
+func getResponseTypes(response *ResponsePayload) (atta bool, resWithException, resValue, resNullValue int32) {
+	var version string
+	if attachmentVersion, ok := response.Attachments[DUBBO_VERSION_KEY]; ok {
+		version, _ = attachmentVersion.(string)
+	}
+	if atta = isSupportResponseAttachment(version); atta {
+		return atta, RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS, RESPONSE_VALUE_WITH_ATTACHMENTS, RESPONSE_NULL_VALUE_WITH_ATTACHMENTS
+	}
+	return atta, RESPONSE_WITH_EXCEPTION, RESPONSE_VALUE, RESPONSE_NULL_VALUE
 }
 
 var versionInt = make(map[string]int)
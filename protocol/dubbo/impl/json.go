@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+	perrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+func init() {
+	SetSerializer(constant.FASTJSON_SERIALIZATION, objectSerializer{codec: jsonCodec{}})
+	SetSerializer(constant.PROTOBUF_JSON_SERIALIZATION, objectSerializer{codec: jsonCodec{protobuf: true}})
+}
+
+// jsonCodec is compatible with the fastjson and protobuf-json serializations of dubbo java,
+// every value is a json text followed by a line feed.
+type jsonCodec struct {
+	// protobuf means the protobuf messages are written in the json mapping of protobuf
+	protobuf bool
+}
+
+func (c jsonCodec) NewOutput() objectOutput {
+	return &jsonOutput{codec: c}
+}
+
+func (c jsonCodec) NewInput(body []byte) objectInput {
+	return &jsonInput{codec: c, data: body}
+}
+
+func (c jsonCodec) marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok && c.protobuf {
+		return protojson.Marshal(proto.MessageV2(m))
+	}
+	return json.Marshal(v)
+}
+
+func (c jsonCodec) unmarshal(data []byte, v interface{}) error {
+	if m, ok := v.(proto.Message); ok && c.protobuf {
+		return protojson.Unmarshal(data, proto.MessageV2(m))
+	}
+	return json.Unmarshal(data, v)
+}
+
+type jsonOutput struct {
+	codec jsonCodec
+	buf   bytes.Buffer
+}
+
+func (o *jsonOutput) WriteObject(v interface{}) error {
+	data, err := o.codec.marshal(v)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	o.buf.Write(data)
+	o.buf.WriteByte('\n')
+	return nil
+}
+
+func (o *jsonOutput) Bytes() []byte {
+	return o.buf.Bytes()
+}
+
+type jsonInput struct {
+	codec jsonCodec
+	data  []byte
+}
+
+// next returns the next line
+func (i *jsonInput) next() ([]byte, error) {
+	if len(i.data) == 0 {
+		return nil, perrors.New("no more json value to read")
+	}
+	line := i.data
+	if idx := bytes.IndexByte(i.data, '\n'); idx >= 0 {
+		line, i.data = i.data[:idx], i.data[idx+1:]
+	} else {
+		i.data = nil
+	}
+	return line, nil
+}
+
+func (i *jsonInput) ReadObject(v interface{}) error {
+	line, err := i.next()
+	if err != nil {
+		return err
+	}
+	return perrors.WithStack(i.codec.unmarshal(line, v))
+}
+
+func (i *jsonInput) ReadRaw() (rawObject, error) {
+	line, err := i.next()
+	if err != nil {
+		return nil, err
+	}
+	return jsonRaw{codec: i.codec, data: line}, nil
+}
+
+type jsonRaw struct {
+	codec jsonCodec
+	data  []byte
+}
+
+func (r jsonRaw) DecodeTo(v interface{}) error {
+	return perrors.WithStack(r.codec.unmarshal(r.data, v))
+}
@@ -0,0 +1,90 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/ugorji/go/codec"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+var msgpackHandle = &codec.MsgpackHandle{}
+
+func init() {
+	msgpackHandle.RawToString = true
+	msgpackHandle.WriteExt = true
+	msgpackHandle.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	SetSerializer(constant.MSGPACK_SERIALIZATION, objectSerializer{codec: msgpackCodec{}})
+}
+
+// msgpackCodec is compatible with the msgpack serialization of dubbo java,
+// the values are written one after another.
+type msgpackCodec struct{}
+
+func (c msgpackCodec) NewOutput() objectOutput {
+	o := &msgpackOutput{}
+	o.encoder = codec.NewEncoder(&o.buf, msgpackHandle)
+	return o
+}
+
+func (c msgpackCodec) NewInput(body []byte) objectInput {
+	return &msgpackInput{decoder: codec.NewDecoderBytes(body, msgpackHandle)}
+}
+
+type msgpackOutput struct {
+	buf     bytes.Buffer
+	encoder *codec.Encoder
+}
+
+func (o *msgpackOutput) WriteObject(v interface{}) error {
+	return perrors.WithStack(o.encoder.Encode(v))
+}
+
+func (o *msgpackOutput) Bytes() []byte {
+	return o.buf.Bytes()
+}
+
+type msgpackInput struct {
+	decoder *codec.Decoder
+}
+
+func (i *msgpackInput) ReadObject(v interface{}) error {
+	return perrors.WithStack(i.decoder.Decode(v))
+}
+
+func (i *msgpackInput) ReadRaw() (rawObject, error) {
+	var raw codec.Raw
+	if err := i.decoder.Decode(&raw); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return msgpackRaw(raw), nil
+}
+
+type msgpackRaw []byte
+
+func (r msgpackRaw) DecodeTo(v interface{}) error {
+	return perrors.WithStack(codec.NewDecoderBytes(r, msgpackHandle).Decode(v))
+}
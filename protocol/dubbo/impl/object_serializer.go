@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"reflect"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// objectOutput writes the values of a dubbo package one by one, like ObjectOutput of dubbo java
+type objectOutput interface {
+	WriteObject(v interface{}) error
+	Bytes() []byte
+}
+
+// objectInput reads the values written by objectOutput one by one
+type objectInput interface {
+	// ReadObject reads the next value into @v, which must be a pointer
+	ReadObject(v interface{}) error
+	// ReadRaw reads the next value without decoding it
+	ReadRaw() (rawObject, error)
+}
+
+// rawObject is a value which is decoded after its type is known
+type rawObject interface {
+	DecodeTo(v interface{}) error
+}
+
+// objectCodec creates the objectOutput and objectInput of a serialization
+type objectCodec interface {
+	NewOutput() objectOutput
+	NewInput(body []byte) objectInput
+}
+
+// objectSerializer is the Serializer of the serializations which write
+// the fields of a package in sequence, e.g. fastjson and msgpack
+type objectSerializer struct {
+	codec objectCodec
+}
+
+// exceptionObject is the serialized form of exceptions
+type exceptionObject struct {
+	Message string `json:"message" codec:"message"`
+}
+
+func (s objectSerializer) Marshal(p DubboPackage) ([]byte, error) {
+	out := s.codec.NewOutput()
+	var err error
+	if p.IsRequest() {
+		err = s.marshalRequest(out, p)
+	} else {
+		err = s.marshalResponse(out, p)
+	}
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return out.Bytes(), nil
+}
+
+func (s objectSerializer) Unmarshal(input []byte, p *DubboPackage) error {
+	if p.IsHeartBeat() {
+		return nil
+	}
+	in := s.codec.NewInput(input)
+	if p.IsRequest() {
+		return perrors.WithStack(s.unmarshalRequest(in, p))
+	}
+	return perrors.WithStack(s.unmarshalResponse(in, p))
+}
+
+func (s objectSerializer) marshalRequest(out objectOutput, p DubboPackage) error {
+	service := p.Service
+	request := EnsureRequestPayload(p.Body)
+	if p.IsHeartBeat() {
+		return out.WriteObject(nil)
+	}
+	args, ok := request.Params.([]interface{})
+	if !ok {
+		return perrors.Errorf("@params is not of type: []interface{}")
+	}
+	types, err := getArgsTypeList(args)
+	if err != nil {
+		return perrors.Wrapf(err, " PackRequest(args:%+v)", args)
+	}
+	for _, v := range []interface{}{DEFAULT_DUBBO_PROTOCOL_VERSION, service.Path, service.Version, service.Method, types} {
+		if err = out.WriteObject(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range args {
+		if err = out.WriteObject(v); err != nil {
+			return err
+		}
+	}
+	setRequestAttachments(service, request)
+	return out.WriteObject(request.Attachments)
+}
+
+func (s objectSerializer) marshalResponse(out objectOutput, p DubboPackage) error {
+	response := EnsureResponsePayload(p.Body)
+	if p.IsHeartBeat() {
+		return out.WriteObject(nil)
+	}
+	if p.Header.ResponseStatus != Response_OK {
+		if response.Exception != nil {
+			return out.WriteObject(response.Exception.Error())
+		}
+		return out.WriteObject(response.RspObj)
+	}
+
+	atta, resWithException, resValue, resNullValue := getResponseTypes(response)
+	var err error
+	switch {
+	case response.Exception != nil:
+		if err = out.WriteObject(resWithException); err == nil {
+			err = out.WriteObject(exceptionObject{Message: response.Exception.Error()})
+		}
+	case response.RspObj == nil:
+		err = out.WriteObject(resNullValue)
+	default:
+		if err = out.WriteObject(resValue); err == nil {
+			err = out.WriteObject(response.RspObj)
+		}
+	}
+	if err != nil || !atta {
+		return err
+	}
+	return out.WriteObject(response.Attachments)
+}
+
+func (s objectSerializer) unmarshalRequest(in objectInput, p *DubboPackage) error {
+	var dubboVersion, path, version, method, argsTypes string
+	for _, v := range []*string{&dubboVersion, &path, &version, &method, &argsTypes} {
+		if err := in.ReadObject(v); err != nil {
+			return err
+		}
+	}
+	raws := make([]rawObject, len(hessian.DescRegex.FindAllString(argsTypes, -1)))
+	for i := range raws {
+		raw, err := in.ReadRaw()
+		if err != nil {
+			return err
+		}
+		raws[i] = raw
+	}
+	var attachments map[string]interface{}
+	if err := in.ReadObject(&attachments); err != nil {
+		return err
+	}
+	if attachments == nil {
+		attachments = map[string]interface{}{constant.INTERFACE_KEY: path}
+	}
+	attachments[DUBBO_VERSION_KEY] = dubboVersion
+
+	// the types of arguments are known only after the service is found
+	argTypes := getMethodArgsTypes(attachments, path, version, method)
+	args := make([]interface{}, len(raws))
+	for i, raw := range raws {
+		var argType reflect.Type
+		if i < len(argTypes) {
+			argType = argTypes[i]
+		}
+		arg, err := decodeArg(raw, argType)
+		if err != nil {
+			return perrors.Wrapf(err, "decode argument %d of method %s", i, method)
+		}
+		args[i] = arg
+	}
+
+	p.SetBody([]interface{}{dubboVersion, path, version, method, argsTypes, args, attachments})
+	buildServerSidePackageBody(p)
+	return nil
+}
+
+func (s objectSerializer) unmarshalResponse(in objectInput, p *DubboPackage) error {
+	if p.Body == nil {
+		p.SetBody(&ResponsePayload{})
+	}
+	response := EnsureResponsePayload(p.Body)
+	if p.Header.ResponseStatus != Response_OK {
+		var message string
+		if err := in.ReadObject(&message); err != nil {
+			return err
+		}
+		response.Exception = perrors.Errorf("java exception:%s", message)
+		return nil
+	}
+
+	var rspType int32
+	if err := in.ReadObject(&rspType); err != nil {
+		return err
+	}
+	var err error
+	switch rspType {
+	case RESPONSE_WITH_EXCEPTION, RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS:
+		expt := exceptionObject{}
+		if err = in.ReadObject(&expt); err == nil {
+			response.Exception = perrors.Errorf("got exception: %s", expt.Message)
+		}
+	case RESPONSE_VALUE, RESPONSE_VALUE_WITH_ATTACHMENTS:
+		rsp := response.RspObj
+		if rsp == nil {
+			rsp = new(interface{})
+		}
+		err = in.ReadObject(rsp)
+	case RESPONSE_NULL_VALUE, RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+	default:
+		return perrors.Errorf("unknown response type %d", rspType)
+	}
+	if err != nil {
+		return err
+	}
+	switch rspType {
+	case RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS, RESPONSE_VALUE_WITH_ATTACHMENTS, RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+		return in.ReadObject(&response.Attachments)
+	}
+	return nil
+}
+
+// getMethodArgsTypes returns the types of arguments of the exported method, the reply argument is excluded
+func getMethodArgsTypes(attachments map[string]interface{}, path, version, method string) []reflect.Type {
+	iface, _ := attachments[constant.INTERFACE_KEY].(string)
+	if iface == "" {
+		iface = path
+	}
+	group, _ := attachments[constant.GROUP_KEY].(string)
+	svc := common.ServiceMap.GetService(DUBBO, iface, group, version)
+	if svc == nil {
+		return nil
+	}
+	mt := svc.Method()[method]
+	if mt == nil {
+		return nil
+	}
+	types := mt.ArgsType()
+	if mt.ReplyType() == nil && len(types) > 0 {
+		types = types[:len(types)-1]
+	}
+	if len(types) == 1 && types[0].String() == "[]interface {}" {
+		return nil
+	}
+	return types
+}
+
+// decodeArg decodes @raw into a value of @typ, or a generic value if @typ is nil
+func decodeArg(raw rawObject, typ reflect.Type) (interface{}, error) {
+	if typ == nil {
+		var v interface{}
+		err := raw.DecodeTo(&v)
+		return v, err
+	}
+	if typ.Kind() == reflect.Ptr {
+		v := reflect.New(typ.Elem())
+		err := raw.DecodeTo(v.Interface())
+		return v.Interface(), err
+	}
+	v := reflect.New(typ)
+	err := raw.DecodeTo(v.Interface())
+	return v.Elem().Interface(), err
+}
@@ -0,0 +1,164 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type SerializationUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SerializationProvider struct{}
+
+func (s *SerializationProvider) GetUser(ctx context.Context, user *SerializationUser, name string) (*SerializationUser, error) {
+	return &SerializationUser{ID: user.ID, Name: name}, nil
+}
+
+func (s *SerializationProvider) Reference() string {
+	return "SerializationProvider"
+}
+
+func newSerializationRequest(serialID byte, args []interface{}) *DubboPackage {
+	pkg := NewDubboPackage(nil)
+	pkg.Body = args
+	pkg.Header.Type = PackageRequest_TwoWay
+	pkg.Header.SerialID = serialID
+	pkg.Header.ID = 10087
+	pkg.Service.Interface = "com.test.SerializationProvider"
+	pkg.Service.Path = "SerializationProvider"
+	pkg.Service.Group = "serialization"
+	pkg.Service.Method = "GetUser"
+	return pkg
+}
+
+func TestObjectSerializer_Request(t *testing.T) {
+	_, err := common.ServiceMap.Register("com.test.SerializationProvider", DUBBO, "serialization", "", &SerializationProvider{})
+	assert.NoError(t, err)
+	defer common.ServiceMap.UnRegister("com.test.SerializationProvider", DUBBO,
+		common.ServiceKey("com.test.SerializationProvider", "serialization", ""))
+
+	for _, serialID := range []byte{constant.S_FastJson, constant.S_Proto, constant.S_Msgpack} {
+		pkg := newSerializationRequest(serialID, []interface{}{&SerializationUser{ID: "1"}, "name"})
+		assert.NoError(t, LoadSerializer(pkg))
+		data, err := pkg.Marshal()
+		assert.NoError(t, err)
+
+		pkgres := NewDubboPackage(data)
+		assert.NoError(t, pkgres.Unmarshal())
+		assert.Equal(t, serialID, pkgres.Header.SerialID)
+		assert.Equal(t, "GetUser", pkgres.Service.Method)
+		assert.Equal(t, "serialization", pkgres.Service.Group)
+		body := pkgres.GetBody().(map[string]interface{})
+		assert.NotNil(t, body["service"])
+		// the arguments are decoded into the types of method
+		assert.Equal(t, []interface{}{&SerializationUser{ID: "1"}, "name"}, body["args"])
+		assert.Equal(t, "2.0.2", body["attachments"].(map[string]interface{})[DUBBO_VERSION_KEY])
+	}
+
+	// the arguments of unknown method are decoded as generic values
+	pkg := newSerializationRequest(constant.S_FastJson, []interface{}{map[string]interface{}{"id": "1"}})
+	pkg.Service.Method = "Unknown"
+	assert.NoError(t, LoadSerializer(pkg))
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres := NewDubboPackage(data)
+	assert.NoError(t, pkgres.Unmarshal())
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "1"}}, pkgres.GetBody().(map[string]interface{})["args"])
+}
+
+func TestObjectSerializer_Response(t *testing.T) {
+	newResponse := func(serialID byte, body *ResponsePayload) *DubboPackage {
+		pkg := NewDubboPackage(nil)
+		pkg.Header.Type = PackageResponse
+		pkg.Header.SerialID = serialID
+		pkg.Header.ID = 10088
+		pkg.Header.ResponseStatus = Response_OK
+		pkg.Body = body
+		assert.NoError(t, LoadSerializer(pkg))
+		return pkg
+	}
+	unmarshal := func(pkg *DubboPackage, reply interface{}) *DubboPackage {
+		data, err := pkg.Marshal()
+		assert.NoError(t, err)
+		pending := remoting.NewPendingResponse(pkg.Header.ID)
+		pending.Reply = reply
+		remoting.AddPendingResponse(pending)
+		defer remoting.GetPendingResponse(remoting.SequenceType(pkg.Header.ID))
+		pkgres := NewDubboPackage(data)
+		assert.NoError(t, pkgres.Unmarshal())
+		return pkgres
+	}
+
+	attachments := map[string]interface{}{DUBBO_VERSION_KEY: "2.0.2", "key": "value"}
+	for _, serialID := range []byte{constant.S_FastJson, constant.S_Proto, constant.S_Msgpack} {
+		user := &SerializationUser{}
+		pkgres := unmarshal(newResponse(serialID, &ResponsePayload{RspObj: &SerializationUser{ID: "1", Name: "name"}, Attachments: attachments}), user)
+		assert.Equal(t, SerializationUser{ID: "1", Name: "name"}, *user)
+		assert.Equal(t, "value", pkgres.Body.(*ResponsePayload).Attachments["key"])
+
+		pkgres = unmarshal(newResponse(serialID, &ResponsePayload{Exception: perrors.New("error"), Attachments: attachments}), user)
+		assert.EqualError(t, pkgres.Body.(*ResponsePayload).Exception, "got exception: error")
+
+		pkg := newResponse(serialID, &ResponsePayload{Exception: perrors.New("bad request")})
+		pkg.Header.ResponseStatus = Response_BAD_REQUEST
+		pkgres = unmarshal(pkg, user)
+		assert.EqualError(t, pkgres.Body.(*ResponsePayload).Exception, "java exception:bad request")
+	}
+
+	// the protobuf messages are written in the json mapping of protobuf
+	reply := &wrapperspb.StringValue{}
+	unmarshal(newResponse(constant.S_Proto, &ResponsePayload{RspObj: wrapperspb.String("value")}), reply)
+	assert.Equal(t, "value", reply.GetValue())
+}
+
+func TestGetSerializerById(t *testing.T) {
+	for _, name := range []string{constant.HESSIAN2_SERIALIZATION, constant.FASTJSON_SERIALIZATION,
+		constant.PROTOBUF_JSON_SERIALIZATION, constant.MSGPACK_SERIALIZATION} {
+		id, err := GetSerialIdByName(name)
+		assert.NoError(t, err)
+		_, err = GetSerializerById(id)
+		assert.NoError(t, err)
+	}
+
+	_, err := GetSerialIdByName("unknown")
+	assert.Equal(t, ErrUnknownSerialization, perrors.Cause(err))
+	// the binary protobuf serialization is not supported by dubbo protocol
+	_, err = GetSerialIdByName(constant.PROTOBUF_SERIALIZATION)
+	assert.Equal(t, ErrUnknownSerialization, perrors.Cause(err))
+	_, err = GetSerializerById(31)
+	assert.Equal(t, ErrUnknownSerialization, perrors.Cause(err))
+
+	pkg := newSerializationRequest(31, []interface{}{"a"})
+	assert.Equal(t, ErrUnknownSerialization, perrors.Cause(LoadSerializer(pkg)))
+}
@@ -47,6 +47,7 @@ type DubboHeader struct {
 	ID             int64
 	BodyLen        int
 	ResponseStatus byte
+	Compressed     bool
 }
 
 // Service defines service instance
@@ -18,13 +18,16 @@
 package impl
 
 import (
-	"fmt"
+	perrors "github.com/pkg/errors"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 )
 
+// ErrUnknownSerialization is returned if the serialization id or name is not supported
+var ErrUnknownSerialization = perrors.New("unknown serialization")
+
 var (
 	serializers = make(map[string]interface{})
 	nameMaps    = make(map[byte]string)
@@ -33,7 +36,9 @@ var (
 func init() {
 	nameMaps = map[byte]string{
 		constant.S_Hessian2: constant.HESSIAN2_SERIALIZATION,
-		constant.S_Proto:    constant.PROTOBUF_SERIALIZATION,
+		constant.S_FastJson: constant.FASTJSON_SERIALIZATION,
+		constant.S_Proto:    constant.PROTOBUF_JSON_SERIALIZATION,
+		constant.S_Msgpack:  constant.MSGPACK_SERIALIZATION,
 	}
 }
 
@@ -44,11 +49,24 @@ func SetSerializer(name string, serializer interface{}) {
 func GetSerializerById(id byte) (interface{}, error) {
 	name, ok := nameMaps[id]
 	if !ok {
-		panic(fmt.Sprintf("serialId %d not found", id))
+		return nil, perrors.Wrapf(ErrUnknownSerialization, "serialId %d not found", id)
 	}
 	serializer, ok := serializers[name]
 	if !ok {
-		panic(fmt.Sprintf("serialization %s not found", name))
+		return nil, perrors.Wrapf(ErrUnknownSerialization, "serialization %s not found", name)
 	}
 	return serializer, nil
 }
+
+// GetSerialIdByName returns the id of serialization @name, which is same as dubbo java
+func GetSerialIdByName(name string) (byte, error) {
+	for id, n := range nameMaps {
+		if n == name {
+			if _, ok := serializers[name]; ok {
+				return id, nil
+			}
+			break
+		}
+	}
+	return 0, perrors.Wrapf(ErrUnknownSerialization, "serialization %s not found", name)
+}
@@ -33,7 +33,7 @@ func LoadSerializer(p *DubboPackage) error {
 	}
 	serializer, err := GetSerializerById(serialID)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	p.SetSerializer(serializer.(Serializer))
 	return nil
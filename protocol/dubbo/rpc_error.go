@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	"google.golang.org/grpc/codes"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+)
+
+// setRPCErrorAttachments puts the code, message and details of the protocol.RPCError in the chain of @err into
+// @attachments, it returns false if there isn't a RPCError. The exception of response is still encoded as
+// java.lang.Throwable, so the consumers unaware of these attachments get the message as before.
+func setRPCErrorAttachments(attachments map[string]interface{}, err error) bool {
+	if attachments == nil || err == nil {
+		return false
+	}
+	var rpcErr *protocol.RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	attachments[constant.RPC_ERROR_CODE_KEY] = strconv.Itoa(int(rpcErr.Code))
+	attachments[constant.RPC_ERROR_MESSAGE_KEY] = rpcErr.Message
+	if len(rpcErr.Details) > 0 {
+		attachments[constant.RPC_ERROR_DETAILS_KEY] = rpcErr.Details
+	}
+	return true
+}
+
+// withoutErrorDetails returns the copy of @attachments without the details of RPCError,
+// or @attachments itself if there aren't details
+func withoutErrorDetails(attachments map[string]interface{}) map[string]interface{} {
+	if _, ok := attachments[constant.RPC_ERROR_DETAILS_KEY]; !ok {
+		return attachments
+	}
+	copied := make(map[string]interface{}, len(attachments))
+	for k, v := range attachments {
+		if k != constant.RPC_ERROR_DETAILS_KEY {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// encodableErrorDetails returns the copy of @attachments with only the details of RPCError which can be encoded
+// by the serialization @serialID. The hessian2 serialization doesn't fail on the values can not be encoded, so
+// they are checked one by one, and the other serializations fail to encode the response with them.
+func encodableErrorDetails(attachments map[string]interface{}, serialID byte) map[string]interface{} {
+	details, ok := attachments[constant.RPC_ERROR_DETAILS_KEY].([]interface{})
+	if !ok || serialID != constant.S_Hessian2 {
+		return attachments
+	}
+	encodable := make([]interface{}, 0, len(details))
+	for _, detail := range details {
+		if err := hessian.NewEncoder().Encode(detail); err != nil {
+			logger.Warnf("The detail %T of rpc error is dropped since it can not be encoded, error: %v", detail, err)
+			continue
+		}
+		encodable = append(encodable, detail)
+	}
+	if len(encodable) == len(details) {
+		return attachments
+	}
+	copied := withoutErrorDetails(attachments)
+	if len(encodable) > 0 {
+		copied[constant.RPC_ERROR_DETAILS_KEY] = encodable
+	}
+	return copied
+}
+
+// toRPCError maps @exception of the response with @status to protocol.RPCError. The code, message and details are
+// taken from @attachments if the provider returns a RPCError, and they are removed from @attachments, otherwise
+// the code is mapped from @status.
+func toRPCError(status byte, exception error, attachments map[string]interface{}) *protocol.RPCError {
+	if exception == nil {
+		return nil
+	}
+	if v, ok := attachments[constant.RPC_ERROR_CODE_KEY]; ok {
+		delete(attachments, constant.RPC_ERROR_CODE_KEY)
+		message, _ := attachments[constant.RPC_ERROR_MESSAGE_KEY].(string)
+		delete(attachments, constant.RPC_ERROR_MESSAGE_KEY)
+		details, _ := attachments[constant.RPC_ERROR_DETAILS_KEY].([]interface{})
+		delete(attachments, constant.RPC_ERROR_DETAILS_KEY)
+		if code, err := strconv.Atoi(fmt.Sprint(v)); err == nil {
+			return protocol.WrapRPCError(exception, codes.Code(code), message, details...)
+		}
+	}
+	var rpcErr *protocol.RPCError
+	if errors.As(exception, &rpcErr) {
+		return rpcErr
+	}
+	return protocol.WrapRPCError(exception, codeFromResponseStatus(status), exception.Error())
+}
+
+// codeFromResponseStatus maps the status of dubbo response to code
+func codeFromResponseStatus(status byte) codes.Code {
+	switch status {
+	case impl.Response_CLIENT_TIMEOUT, impl.Response_SERVER_TIMEOUT:
+		return codes.DeadlineExceeded
+	case impl.Response_BAD_REQUEST:
+		return codes.InvalidArgument
+	case impl.Response_SERVICE_NOT_FOUND:
+		return codes.Unimplemented
+	case impl.Response_BAD_RESPONSE, impl.Response_SERVER_ERROR, impl.Response_CLIENT_ERROR:
+		return codes.Internal
+	default:
+		// the exception thrown by service is replied with Response_OK or Response_SERVICE_ERROR
+		return codes.Unknown
+	}
+}
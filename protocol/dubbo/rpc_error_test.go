@@ -0,0 +1,85 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo/impl"
+)
+
+type unencodableDetail struct {
+	C chan int
+}
+
+func TestSetRPCErrorAttachments(t *testing.T) {
+	attachments := map[string]interface{}{}
+	assert.False(t, setRPCErrorAttachments(attachments, perrors.New("error")))
+	assert.Empty(t, attachments)
+
+	err := perrors.WithStack(protocol.NewRPCError(codes.NotFound, "no such user", &User{ID: "1"}, &unencodableDetail{}))
+	assert.True(t, setRPCErrorAttachments(attachments, err))
+	assert.Equal(t, "5", attachments[constant.RPC_ERROR_CODE_KEY])
+	assert.Equal(t, "no such user", attachments[constant.RPC_ERROR_MESSAGE_KEY])
+
+	// only the details can be encoded by hessian2 are kept
+	encodable := encodableErrorDetails(attachments, constant.S_Hessian2)
+	assert.Equal(t, []interface{}{&User{ID: "1"}}, encodable[constant.RPC_ERROR_DETAILS_KEY])
+	assert.Len(t, attachments[constant.RPC_ERROR_DETAILS_KEY], 2)
+	assert.Equal(t, attachments, encodableErrorDetails(attachments, constant.S_FastJson))
+
+	attachments[constant.RPC_ERROR_DETAILS_KEY] = []interface{}{&unencodableDetail{}}
+	assert.NotContains(t, encodableErrorDetails(attachments, constant.S_Hessian2), constant.RPC_ERROR_DETAILS_KEY)
+	assert.NotContains(t, withoutErrorDetails(attachments), constant.RPC_ERROR_DETAILS_KEY)
+	assert.Contains(t, attachments, constant.RPC_ERROR_DETAILS_KEY)
+}
+
+func TestToRPCError(t *testing.T) {
+	assert.Nil(t, toRPCError(impl.Response_OK, nil, nil))
+
+	exception := perrors.New("java exception:timeout")
+	rpcErr := toRPCError(impl.Response_SERVER_TIMEOUT, exception, nil)
+	assert.Equal(t, codes.DeadlineExceeded, rpcErr.Code)
+	assert.Equal(t, "java exception:timeout", rpcErr.Message)
+	assert.EqualError(t, rpcErr, "java exception:timeout")
+	assert.True(t, perrors.Is(rpcErr, exception))
+	assert.Same(t, exception, perrors.Cause(rpcErr))
+
+	assert.Equal(t, codes.Unknown, toRPCError(impl.Response_OK, exception, nil).Code)
+	assert.Equal(t, codes.InvalidArgument, toRPCError(impl.Response_BAD_REQUEST, exception, nil).Code)
+	assert.Equal(t, codes.Unimplemented, toRPCError(impl.Response_SERVICE_NOT_FOUND, exception, nil).Code)
+
+	attachments := map[string]interface{}{
+		constant.RPC_ERROR_CODE_KEY:    "7",
+		constant.RPC_ERROR_MESSAGE_KEY: "denied",
+	}
+	rpcErr = toRPCError(impl.Response_OK, exception, attachments)
+	assert.Equal(t, codes.PermissionDenied, rpcErr.Code)
+	assert.Equal(t, "denied", rpcErr.Message)
+	assert.Empty(t, attachments)
+}
@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// attachmentCtxKey is the key of the propagated attachments in ctx, which are written and read as headers by
+// headerHandler
+type attachmentCtxKey struct{}
+
+// injectPropagatedAttachments puts the propagated attachments of @invocation into the ctx of triple client
+func injectPropagatedAttachments(ctx context.Context, invocation protocol.Invocation) context.Context {
+	attachments := make(map[string]string)
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := invocation.AttachmentsByKey(k, ""); len(v) > 0 {
+			attachments[k] = v
+		}
+	}
+	if len(attachments) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, attachmentCtxKey{}, attachments)
+}
+
+// extractPropagatedAttachments puts the propagated attachments in the ctx of triple server into @invocation
+func extractPropagatedAttachments(ctx context.Context, invocation protocol.Invocation) {
+	attachments, ok := ctx.Value(attachmentCtxKey{}).(map[string]string)
+	if !ok {
+		return
+	}
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v, ok := attachments[k]; ok {
+			invocation.SetAttachments(k, v)
+		}
+	}
+}
+
+// attachmentInvoker fills the attachments of invocation with the propagated attachments in the ctx of triple server
+type attachmentInvoker struct {
+	protocol.Invoker
+}
+
+// Invoke extracts the propagated attachments and invokes the service
+func (ti *attachmentInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	extractPropagatedAttachments(ctx, invocation)
+	return ti.Invoker.Invoke(ctx, invocation)
+}
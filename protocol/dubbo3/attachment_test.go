@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
+	triConfig "github.com/dubbogo/triple/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestTraceAttachments(t *testing.T) {
+	inv := invocation.NewRPCInvocation("SayHello", nil, map[string]interface{}{
+		constant.TRACEPARENT_KEY:        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		constant.BAGGAGE_KEY:            "user=alice,tenant=a%20b",
+		constant.TIMEOUT_ATTACHMENT_KEY: "100",
+		"other":                         "value",
+	})
+	option := triConfig.NewTripleOption(triConfig.WithProtocol(headerProtocol))
+
+	// the attachments are written as the standard headers by client
+	clientHandler := newHeaderHandler(option, injectPropagatedAttachments(context.Background(), inv))
+	header := clientHandler.WriteTripleReqHeaderField(http.Header{})
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get("traceparent"))
+	assert.Equal(t, "user=alice,tenant=a%20b", header.Get("baggage"))
+	assert.Empty(t, header.Get(tripleConstant.TripleTraceProtoBin))
+	assert.Empty(t, header.Get("other"))
+
+	// and read from the headers by server
+	r := httptest.NewRequest(http.MethodPost, "/com.foo.Greeter/SayHello", nil)
+	r.Header = header
+	serverHandler := newHeaderHandler(option, context.Background())
+	serverInv := invocation.NewRPCInvocation("SayHello", nil, nil)
+	extractPropagatedAttachments(serverHandler.ReadFromTripleReqHeader(r).FieldToCtx(), serverInv)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		serverInv.AttachmentsByKey(constant.TRACEPARENT_KEY, ""))
+	assert.Equal(t, "user=alice,tenant=a%20b", serverInv.AttachmentsByKey(constant.BAGGAGE_KEY, ""))
+	assert.Equal(t, "100", serverInv.AttachmentsByKey(constant.TIMEOUT_ATTACHMENT_KEY, ""))
+	assert.Nil(t, serverInv.Attachment(constant.TRACESTATE_KEY))
+	assert.Nil(t, serverInv.Attachment("other"))
+
+	// no propagated attachments
+	ctx := context.Background()
+	assert.Equal(t, ctx, injectPropagatedAttachments(ctx, invocation.NewRPCInvocation("SayHello", nil, nil)))
+	r = httptest.NewRequest(http.MethodPost, "/com.foo.Greeter/SayHello", nil)
+	serverInv = invocation.NewRPCInvocation("SayHello", nil, nil)
+	extractPropagatedAttachments(serverHandler.ReadFromTripleReqHeader(r).FieldToCtx(), serverInv)
+	assert.Empty(t, serverInv.Attachments())
+}
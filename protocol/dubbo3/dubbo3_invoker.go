@@ -30,6 +30,7 @@ import (
 	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
 	triConfig "github.com/dubbogo/triple/pkg/config"
 	"github.com/dubbogo/triple/pkg/triple"
+	perrors "github.com/pkg/errors"
 )
 
 import (
@@ -75,6 +76,7 @@ func NewDubboInvoker(url *common.URL) (*DubboInvoker, error) {
 		triConfig.WithHeaderAppVersion(url.GetParam(constant.APP_VERSION_KEY, "")),
 		triConfig.WithHeaderGroup(url.GetParam(constant.GROUP_KEY, "")),
 		triConfig.WithLogger(logger.GetLogger()),
+		triConfig.WithProtocol(headerProtocol),
 	)
 	client, err := triple.NewTripleClient(consumerService, triOption)
 
@@ -82,6 +84,16 @@ func NewDubboInvoker(url *common.URL) (*DubboInvoker, error) {
 		return nil, err
 	}
 
+	if url.GetParamBool(constant.SSL_ENABLED_KEY, false) {
+		tlsConfig, err := buildTlsConfig(config.GetClientTlsConfigBuilderByURL(url))
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "build tls config of %s", url.Location)
+		}
+		if err = setClientTlsConfig(client, tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	return &DubboInvoker{
 		BaseInvoker: *protocol.NewBaseInvoker(url),
 		client:      client,
@@ -136,6 +148,13 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 
 	// append interface id to ctx
 	ctx = context.WithValue(ctx, tripleConstant.InterfaceKey, di.BaseInvoker.GetURL().GetParam(constant.INTERFACE_KEY, ""))
+	if inv, ok := invocation.(*invocation_impl.RPCInvocation); ok {
+		if stub := di.getStreamStub(inv.MethodName()); stub.IsValid() {
+			return di.invokeStream(ctx, stub, inv)
+		}
+		protocol.AttachRemainingTimeout(ctx, inv, di.getTimeout(inv))
+	}
+	ctx = injectPropagatedAttachments(ctx, invocation)
 	in := make([]reflect.Value, 0, 16)
 	in = append(in, reflect.ValueOf(ctx))
 
@@ -145,11 +164,52 @@ func (di *DubboInvoker) Invoke(ctx context.Context, invocation protocol.Invocati
 
 	methodName := invocation.MethodName()
 
-	result.Err = di.client.Invoke(methodName, in, invocation.Reply())
+	result.Err = fromUnaryError(di.client.Invoke(methodName, in, invocation.Reply()))
 	result.Rest = invocation.Reply()
 	return &result
 }
 
+// getStreamStub returns the method of stub if @methodName is a streaming method, which returns grpc.ClientStream
+func (di *DubboInvoker) getStreamStub(methodName string) reflect.Value {
+	if !di.client.StubInvoker.IsValid() {
+		return reflect.Value{}
+	}
+	stub := di.client.StubInvoker.MethodByName(methodName)
+	if !stub.IsValid() || stub.Type().NumOut() != 2 || !stub.Type().Out(0).Implements(clientStreamType) {
+		return reflect.Value{}
+	}
+	return stub
+}
+
+// invokeStream opens the stream by @stub and sets it as the reply of @invocation. The stream isn't limited by the
+// timeout of invoker, and it's canceled once @ctx is done.
+func (di *DubboInvoker) invokeStream(ctx context.Context, stub reflect.Value, invocation *invocation_impl.RPCInvocation) protocol.Result {
+	var result protocol.RPCResult
+
+	state := protocol.NewStreamState()
+	invocation.SetAttribute(constant.STREAM_STATE_KEY, state)
+	ctx = context.WithValue(ctx, streamStateCtxKey{}, state)
+	ctx = injectPropagatedAttachments(ctx, invocation)
+	in := make([]reflect.Value, 0, len(invocation.ParameterValues())+1)
+	in = append(in, reflect.ValueOf(ctx))
+	in = append(in, invocation.ParameterValues()...)
+
+	res := stub.Call(in)
+	if err, ok := res[1].Interface().(error); ok && err != nil {
+		state.Close(err)
+		result.Err = err
+		return &result
+	}
+	reply := reflect.ValueOf(invocation.Reply())
+	if reply.Kind() == reflect.Ptr && res[0].Type().AssignableTo(reply.Type().Elem()) {
+		reply.Elem().Set(res[0])
+		result.Rest = invocation.Reply()
+	} else {
+		result.Rest = res[0].Interface()
+	}
+	return &result
+}
+
 // get timeout including methodConfig
 func (di *DubboInvoker) getTimeout(invocation *invocation_impl.RPCInvocation) time.Duration {
 	timeout := di.GetURL().GetParam(strings.Join([]string{constant.METHOD_KEYS, invocation.MethodName(), constant.TIMEOUT_KEY}, "."), "")
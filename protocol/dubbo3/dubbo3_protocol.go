@@ -55,15 +55,15 @@ var (
 type DubboProtocol struct {
 	protocol.BaseProtocol
 	serverLock sync.Mutex
-	serviceMap *sync.Map                       // serviceMap is used to export multiple service by one server
-	serverMap  map[string]*triple.TripleServer // serverMap stores all exported server
+	serviceMap *sync.Map               // serviceMap is used to export multiple service by one server
+	serverMap  map[string]tripleServer // serverMap stores all exported server
 }
 
 // NewDubboProtocol create a dubbo protocol.
 func NewDubboProtocol() *DubboProtocol {
 	return &DubboProtocol{
 		BaseProtocol: protocol.NewBaseProtocol(),
-		serverMap:    make(map[string]*triple.TripleServer),
+		serverMap:    make(map[string]tripleServer),
 		serviceMap:   &sync.Map{},
 	}
 }
@@ -92,14 +92,14 @@ func (dp *DubboProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
 			panic(fmt.Sprintf("no invoker found for servicekey: %v", url.ServiceKey()))
 		}
 		in := []reflect.Value{reflect.ValueOf(service)}
-		in = append(in, reflect.ValueOf(invoker))
+		in = append(in, reflect.ValueOf(&statusInvoker{Invoker: &attachmentInvoker{Invoker: invoker}}))
 		m.Func.Call(in)
 		triSerializationType = tripleConstant.PBCodecName
 	} else {
 		valueOf := reflect.ValueOf(service)
 		typeOf := valueOf.Type()
 		numField := valueOf.NumMethod()
-		tripleService := &Dubbo3HessianService{proxyImpl: invoker}
+		tripleService := &Dubbo3HessianService{proxyImpl: &statusInvoker{Invoker: &attachmentInvoker{Invoker: invoker}}}
 		for i := 0; i < numField; i++ {
 			ft := typeOf.Method(i)
 			if ft.Name == "Reference" {
@@ -209,8 +209,18 @@ func (dp *DubboProtocol) openServer(url *common.URL, tripleCodecType tripleConst
 		triConfig.WithCodecType(tripleCodecType),
 		triConfig.WithLocation(url.Location),
 		triConfig.WithLogger(logger.GetLogger()),
+		triConfig.WithProtocol(headerProtocol),
 	)
-	srv := triple.NewTripleServer(dp.serviceMap, triOption)
+	var srv tripleServer
+	if url.GetParamBool(constant.SSL_ENABLED_KEY, false) {
+		tlsConfig, err := buildTlsConfig(config.GetServerTlsConfigBuilderByURL(url))
+		if err != nil {
+			panic(fmt.Sprintf("[DubboProtocol] build tls config of %s error: %v", url.Location, err))
+		}
+		srv = newTlsTripleServer(dp.serviceMap, triOption, tlsConfig)
+	} else {
+		srv = triple.NewTripleServer(dp.serviceMap, triOption)
+	}
 	dp.serverMap[url.Location] = srv
 
 	srv.Start()
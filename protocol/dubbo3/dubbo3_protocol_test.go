@@ -18,6 +18,19 @@
 package dubbo3
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -28,7 +41,10 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo3/internal"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 const (
@@ -85,3 +101,78 @@ func TestDubboProtocolRefer(t *testing.T) {
 	invokersLen = len(proto.(*DubboProtocol).Invokers())
 	assert.Equal(t, 0, invokersLen)
 }
+
+func TestDubboProtocolTls(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dubbo3-tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	writeTestCert(t, dir)
+
+	addService()
+	proto := NewDubboProtocol()
+	defer proto.Destroy()
+	url, err := common.NewURL(strings.Replace(mockDubbo3CommonUrl, "20002", "20004", 1))
+	assert.NoError(t, err)
+	url.SetParam(constant.SSL_ENABLED_KEY, "true")
+	url.SetParam(constant.TLS_CERT_FILE_KEY, filepath.Join(dir, "cert.pem"))
+	url.SetParam(constant.TLS_KEY_FILE_KEY, filepath.Join(dir, "key.pem"))
+	assert.NotNil(t, proto.Export(&helloInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}))
+	time.Sleep(time.Second)
+
+	clientURL := url.Clone()
+	clientURL.DelParam(constant.TLS_CERT_FILE_KEY)
+	clientURL.DelParam(constant.TLS_KEY_FILE_KEY)
+	clientURL.SetParam(constant.TLS_CA_FILE_KEY, filepath.Join(dir, "cert.pem"))
+	clientURL.SetParam(constant.TLS_SERVER_NAME_KEY, "localhost")
+	invoker, err := NewDubboInvoker(clientURL)
+	assert.NoError(t, err)
+	reply := &internal.HelloReply{}
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("SayHello"),
+		invocation.WithParameterValues([]reflect.Value{reflect.ValueOf(&internal.HelloRequest{Name: "tls"})}),
+		invocation.WithReply(reply)))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "Hello tls", reply.Message)
+
+	// the certificate of server is not trusted by the client without ca
+	clientURL.DelParam(constant.TLS_CA_FILE_KEY)
+	invoker, err = NewDubboInvoker(clientURL)
+	assert.NoError(t, err)
+	res = invoker.Invoke(context.Background(), invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("SayHello"),
+		invocation.WithParameterValues([]reflect.Value{reflect.ValueOf(&internal.HelloRequest{Name: "tls"})}),
+		invocation.WithReply(&internal.HelloReply{})))
+	assert.Error(t, res.Error())
+}
+
+type helloInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (i *helloInvoker) Invoke(_ context.Context, invocation protocol.Invocation) protocol.Result {
+	req := invocation.Arguments()[0].(*internal.HelloRequest)
+	return &protocol.RPCResult{Rest: &internal.HelloReply{Message: "Hello " + req.Name}}
+}
+
+// writeTestCert writes a self-signed certificate of localhost and its key into @dir
+func writeTestCert(t *testing.T, dir string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cert.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "key.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+}
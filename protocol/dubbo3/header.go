@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+	"net/http"
+)
+
+import (
+	h2Triple "github.com/dubbogo/net/http2/triple"
+	"github.com/dubbogo/triple/pkg/common"
+	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
+	triConfig "github.com/dubbogo/triple/pkg/config"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// headerProtocol is the triple protocol used by both client and server of dubbo-go, it is the same as triple on
+// the wire. Besides the headers of triple, the propagated attachments are carried by the headers of their own
+// names, such as the W3C "traceparent", and the status of the errors replied by dubbo-go is carried by trailers.
+const headerProtocol = "tri-dubbo-go"
+
+func init() {
+	common.SetProtocolHeaderHandler(headerProtocol, newHeaderHandler)
+	common.SetPackageHandler(headerProtocol, func() common.PackageHandler {
+		handler, _ := common.GetPackagerHandler(&triConfig.Option{Protocol: tripleConstant.TRIPLE, Logger: logger.GetLogger()})
+		return handler
+	})
+}
+
+// headerHandler is the header handler of triple, which writes and reads the propagated attachments as headers
+type headerHandler struct {
+	h2Triple.ProtocolHeaderHandler
+	ctx context.Context
+}
+
+func newHeaderHandler(opt *triConfig.Option, ctx context.Context) h2Triple.ProtocolHeaderHandler {
+	tripleOpt := *opt
+	tripleOpt.Protocol = tripleConstant.TRIPLE
+	handler, _ := common.GetProtocolHeaderHandler(&tripleOpt, ctx)
+	return &headerHandler{ProtocolHeaderHandler: handler, ctx: ctx}
+}
+
+// WriteTripleReqHeaderField writes the headers of triple and the propagated attachments in the ctx of client
+func (h *headerHandler) WriteTripleReqHeaderField(header http.Header) http.Header {
+	header = h.ProtocolHeaderHandler.WriteTripleReqHeaderField(header)
+	if attachments, ok := h.ctx.Value(attachmentCtxKey{}).(map[string]string); ok {
+		for k, v := range attachments {
+			header.Set(k, v)
+		}
+	}
+	return header
+}
+
+// ReadFromTripleReqHeader reads the headers of triple and the propagated attachments of request @r
+func (h *headerHandler) ReadFromTripleReqHeader(r *http.Request) h2Triple.ProtocolHeader {
+	header := h.ProtocolHeaderHandler.ReadFromTripleReqHeader(r)
+	attachments := make(map[string]string)
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := r.Header.Get(k); len(v) > 0 {
+			attachments[k] = v
+		}
+	}
+	if len(attachments) == 0 {
+		return header
+	}
+	return &attachmentHeader{ProtocolHeader: header, attachments: attachments}
+}
+
+// attachmentHeader puts the propagated attachments into the ctx of server besides the headers of triple
+type attachmentHeader struct {
+	h2Triple.ProtocolHeader
+	attachments map[string]string
+}
+
+// FieldToCtx returns the ctx of server with the propagated attachments
+func (h *attachmentHeader) FieldToCtx() context.Context {
+	return context.WithValue(h.ProtocolHeader.FieldToCtx(), attachmentCtxKey{}, h.attachments)
+}
@@ -0,0 +1,525 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: stream.proto
+
+package stream
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	dgrpc "dubbo.apache.org/dubbo-go/v3/protocol/dubbo3"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
+	dubbo3 "github.com/dubbogo/triple/pkg/triple"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// The request message containing the user's name.
+type StreamRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamRequest) Reset()         { *m = StreamRequest{} }
+func (m *StreamRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamRequest) ProtoMessage()    {}
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{0}
+}
+
+func (m *StreamRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamRequest.Unmarshal(m, b)
+}
+func (m *StreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamRequest.Merge(m, src)
+}
+func (m *StreamRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamRequest.Size(m)
+}
+func (m *StreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamRequest proto.InternalMessageInfo
+
+func (m *StreamRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// The response message containing the greetings
+type StreamReply struct {
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamReply) Reset()         { *m = StreamReply{} }
+func (m *StreamReply) String() string { return proto.CompactTextString(m) }
+func (*StreamReply) ProtoMessage()    {}
+func (*StreamReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bb17ef3f514bfe54, []int{1}
+}
+
+func (m *StreamReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamReply.Unmarshal(m, b)
+}
+func (m *StreamReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamReply.Marshal(b, m, deterministic)
+}
+func (m *StreamReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamReply.Merge(m, src)
+}
+func (m *StreamReply) XXX_Size() int {
+	return xxx_messageInfo_StreamReply.Size(m)
+}
+func (m *StreamReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamReply proto.InternalMessageInfo
+
+func (m *StreamReply) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*StreamRequest)(nil), "stream.StreamRequest")
+	proto.RegisterType((*StreamReply)(nil), "stream.StreamReply")
+}
+
+func init() { proto.RegisterFile("stream.proto", fileDescriptor_bb17ef3f514bfe54) }
+
+var fileDescriptor_bb17ef3f514bfe54 = []byte{
+	// 174 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x29, 0x2e, 0x29, 0x4a,
+	0x4d, 0xcc, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x83, 0xf0, 0x94, 0x94, 0xb9, 0x78,
+	0x83, 0xc1, 0xac, 0xa0, 0xd4, 0xc2, 0xd2, 0xd4, 0xe2, 0x12, 0x21, 0x21, 0x2e, 0x96, 0xbc, 0xc4,
+	0xdc, 0x54, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x30, 0x5b, 0x49, 0x9d, 0x8b, 0x1b, 0xa6,
+	0xa8, 0x20, 0xa7, 0x52, 0x48, 0x82, 0x8b, 0x3d, 0x37, 0xb5, 0xb8, 0x38, 0x31, 0x1d, 0xa6, 0x0a,
+	0xc6, 0x35, 0x7a, 0xcc, 0x08, 0x33, 0xce, 0xbd, 0x28, 0x35, 0xb5, 0x24, 0xb5, 0x48, 0xc8, 0x89,
+	0x8b, 0x2f, 0x38, 0xb1, 0xd2, 0x23, 0x35, 0x27, 0x27, 0x1f, 0x22, 0x21, 0x24, 0xaa, 0x07, 0x75,
+	0x08, 0x8a, 0xbd, 0x52, 0xc2, 0xe8, 0xc2, 0x05, 0x39, 0x95, 0x4a, 0x0c, 0x1a, 0x8c, 0x06, 0x8c,
+	0x42, 0x6e, 0x5c, 0x22, 0x70, 0x33, 0x52, 0x8b, 0xca, 0x52, 0x8b, 0xc8, 0x31, 0x09, 0xd5, 0x1c,
+	0xe7, 0x9c, 0xcc, 0xd4, 0xbc, 0x12, 0xf2, 0x5c, 0xe4, 0xc4, 0x11, 0x05, 0x0d, 0xbd, 0x24, 0x36,
+	0x70, 0x60, 0x1a, 0x03, 0x06, 0x00, 0x47, 0x36, 0x41, 0xd0, 0x5c, 0x01, 0x00, 0x00,
+}
+
+type streamgreeterDubbo3Client struct {
+	cc *dubbo3.TripleConn
+}
+
+func NewStreamGreeterDubbo3Client(cc *dubbo3.TripleConn) StreamGreeterClient {
+	return &streamgreeterDubbo3Client{cc}
+}
+func (c *streamgreeterDubbo3Client) SayHelloStream(ctx context.Context, opt ...grpc.CallOption) (StreamGreeter_SayHelloStreamClient, error) {
+	interfaceKey := ctx.Value(tripleConstant.InterfaceKey).(string)
+	desc := &grpc.StreamDesc{StreamName: "SayHelloStream", ServerStreams: true, ClientStreams: true}
+	stream, err := dgrpc.NewClientStream(ctx, desc, c.cc, "/"+interfaceKey+"/SayHelloStream", opt...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloStreamClient{stream}
+	return x, nil
+}
+func (c *streamgreeterDubbo3Client) SayHelloServerStream(ctx context.Context, in *StreamRequest, opt ...grpc.CallOption) (StreamGreeter_SayHelloServerStreamClient, error) {
+	interfaceKey := ctx.Value(tripleConstant.InterfaceKey).(string)
+	desc := &grpc.StreamDesc{StreamName: "SayHelloServerStream", ServerStreams: true, ClientStreams: false}
+	stream, err := dgrpc.NewClientStream(ctx, desc, c.cc, "/"+interfaceKey+"/SayHelloServerStream", opt...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloServerStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+func (c *streamgreeterDubbo3Client) SayHelloClientStream(ctx context.Context, opt ...grpc.CallOption) (StreamGreeter_SayHelloClientStreamClient, error) {
+	interfaceKey := ctx.Value(tripleConstant.InterfaceKey).(string)
+	desc := &grpc.StreamDesc{StreamName: "SayHelloClientStream", ServerStreams: false, ClientStreams: true}
+	stream, err := dgrpc.NewClientStream(ctx, desc, c.cc, "/"+interfaceKey+"/SayHelloClientStream", opt...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloClientStreamClient{stream}
+	return x, nil
+}
+
+// StreamGreeterClientImpl is the client API for StreamGreeter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamGreeterClientImpl struct {
+	// Sends and receives greetings in both directions
+	SayHelloStream func(ctx context.Context) (StreamGreeter_SayHelloStreamClient, error)
+	// Receives greetings for a request
+	SayHelloServerStream func(ctx context.Context, in *StreamRequest) (StreamGreeter_SayHelloServerStreamClient, error)
+	// Sends greetings and receives a summary
+	SayHelloClientStream func(ctx context.Context) (StreamGreeter_SayHelloClientStreamClient, error)
+}
+
+func (c *StreamGreeterClientImpl) Reference() string {
+	return "streamGreeterImpl"
+}
+
+func (c *StreamGreeterClientImpl) GetDubboStub(cc *dubbo3.TripleConn) StreamGreeterClient {
+	return NewStreamGreeterDubbo3Client(cc)
+}
+
+type StreamGreeterProviderBase struct {
+	proxyImpl protocol.Invoker
+}
+
+func (s *StreamGreeterProviderBase) SetProxyImpl(impl protocol.Invoker) {
+	s.proxyImpl = impl
+}
+
+func (s *StreamGreeterProviderBase) GetProxyImpl() protocol.Invoker {
+	return s.proxyImpl
+}
+
+func (c *StreamGreeterProviderBase) Reference() string {
+	return "streamGreeterImpl"
+}
+
+func _DUBBO_StreamGreeter_SayHelloStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	base := srv.(dgrpc.Dubbo3GrpcService)
+	ss := dgrpc.NewServerStream(stream)
+	args := []interface{}{}
+	args = append(args, &streamGreeterSayHelloStreamServer{ss})
+	invo := invocation.NewRPCInvocation("SayHelloStream", args, nil)
+	return ss.Invoke(base.GetProxyImpl(), invo)
+}
+
+func _DUBBO_StreamGreeter_SayHelloServerStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	base := srv.(dgrpc.Dubbo3GrpcService)
+	ss := dgrpc.NewServerStream(stream)
+	args := []interface{}{}
+	in := new(StreamRequest)
+	if err := ss.RecvMsg(in); err != nil {
+		return ss.Close(err)
+	}
+	args = append(args, in)
+	args = append(args, &streamGreeterSayHelloServerStreamServer{ss})
+	invo := invocation.NewRPCInvocation("SayHelloServerStream", args, nil)
+	return ss.Invoke(base.GetProxyImpl(), invo)
+}
+
+func _DUBBO_StreamGreeter_SayHelloClientStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	base := srv.(dgrpc.Dubbo3GrpcService)
+	ss := dgrpc.NewServerStream(stream)
+	args := []interface{}{}
+	args = append(args, &streamGreeterSayHelloClientStreamServer{ss})
+	invo := invocation.NewRPCInvocation("SayHelloClientStream", args, nil)
+	return ss.Invoke(base.GetProxyImpl(), invo)
+}
+
+func (s *StreamGreeterProviderBase) ServiceDesc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "stream.StreamGreeter",
+		HandlerType: (*StreamGreeterServer)(nil),
+		Methods:     []grpc.MethodDesc{},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "SayHelloStream",
+				Handler:       _DUBBO_StreamGreeter_SayHelloStream_Handler,
+				ServerStreams: true,
+				ClientStreams: true,
+			},
+			{
+				StreamName:    "SayHelloServerStream",
+				Handler:       _DUBBO_StreamGreeter_SayHelloServerStream_Handler,
+				ServerStreams: true,
+			},
+			{
+				StreamName:    "SayHelloClientStream",
+				Handler:       _DUBBO_StreamGreeter_SayHelloClientStream_Handler,
+				ClientStreams: true,
+			},
+		},
+		Metadata: "stream.proto",
+	}
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// StreamGreeterClient is the client API for StreamGreeter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamGreeterClient interface {
+	// Sends and receives greetings in both directions
+	SayHelloStream(ctx context.Context, opts ...grpc.CallOption) (StreamGreeter_SayHelloStreamClient, error)
+	// Receives greetings for a request
+	SayHelloServerStream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (StreamGreeter_SayHelloServerStreamClient, error)
+	// Sends greetings and receives a summary
+	SayHelloClientStream(ctx context.Context, opts ...grpc.CallOption) (StreamGreeter_SayHelloClientStreamClient, error)
+}
+
+type streamGreeterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStreamGreeterClient(cc grpc.ClientConnInterface) StreamGreeterClient {
+	return &streamGreeterClient{cc}
+}
+
+func (c *streamGreeterClient) SayHelloStream(ctx context.Context, opts ...grpc.CallOption) (StreamGreeter_SayHelloStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamGreeter_serviceDesc.Streams[0], "/stream.StreamGreeter/SayHelloStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloStreamClient{stream}
+	return x, nil
+}
+
+type StreamGreeter_SayHelloStreamClient interface {
+	Send(*StreamRequest) error
+	Recv() (*StreamReply, error)
+	grpc.ClientStream
+}
+
+type streamGreeterSayHelloStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamGreeterSayHelloStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamGreeterSayHelloStreamClient) Recv() (*StreamReply, error) {
+	m := new(StreamReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamGreeterClient) SayHelloServerStream(ctx context.Context, in *StreamRequest, opts ...grpc.CallOption) (StreamGreeter_SayHelloServerStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamGreeter_serviceDesc.Streams[1], "/stream.StreamGreeter/SayHelloServerStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloServerStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamGreeter_SayHelloServerStreamClient interface {
+	Recv() (*StreamReply, error)
+	grpc.ClientStream
+}
+
+type streamGreeterSayHelloServerStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamGreeterSayHelloServerStreamClient) Recv() (*StreamReply, error) {
+	m := new(StreamReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamGreeterClient) SayHelloClientStream(ctx context.Context, opts ...grpc.CallOption) (StreamGreeter_SayHelloClientStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamGreeter_serviceDesc.Streams[2], "/stream.StreamGreeter/SayHelloClientStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamGreeterSayHelloClientStreamClient{stream}
+	return x, nil
+}
+
+type StreamGreeter_SayHelloClientStreamClient interface {
+	Send(*StreamRequest) error
+	CloseAndRecv() (*StreamReply, error)
+	grpc.ClientStream
+}
+
+type streamGreeterSayHelloClientStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamGreeterSayHelloClientStreamClient) Send(m *StreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamGreeterSayHelloClientStreamClient) CloseAndRecv() (*StreamReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StreamReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamGreeterServer is the server API for StreamGreeter service.
+type StreamGreeterServer interface {
+	// Sends and receives greetings in both directions
+	SayHelloStream(StreamGreeter_SayHelloStreamServer) error
+	// Receives greetings for a request
+	SayHelloServerStream(*StreamRequest, StreamGreeter_SayHelloServerStreamServer) error
+	// Sends greetings and receives a summary
+	SayHelloClientStream(StreamGreeter_SayHelloClientStreamServer) error
+}
+
+// UnimplementedStreamGreeterServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamGreeterServer struct {
+}
+
+func (*UnimplementedStreamGreeterServer) SayHelloStream(srv StreamGreeter_SayHelloStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayHelloStream not implemented")
+}
+func (*UnimplementedStreamGreeterServer) SayHelloServerStream(req *StreamRequest, srv StreamGreeter_SayHelloServerStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayHelloServerStream not implemented")
+}
+func (*UnimplementedStreamGreeterServer) SayHelloClientStream(srv StreamGreeter_SayHelloClientStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SayHelloClientStream not implemented")
+}
+
+func RegisterStreamGreeterServer(s *grpc.Server, srv StreamGreeterServer) {
+	s.RegisterService(&_StreamGreeter_serviceDesc, srv)
+}
+
+func _StreamGreeter_SayHelloStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamGreeterServer).SayHelloStream(&streamGreeterSayHelloStreamServer{stream})
+}
+
+type StreamGreeter_SayHelloStreamServer interface {
+	Send(*StreamReply) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type streamGreeterSayHelloStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamGreeterSayHelloStreamServer) Send(m *StreamReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamGreeterSayHelloStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamGreeter_SayHelloServerStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamGreeterServer).SayHelloServerStream(m, &streamGreeterSayHelloServerStreamServer{stream})
+}
+
+type StreamGreeter_SayHelloServerStreamServer interface {
+	Send(*StreamReply) error
+	grpc.ServerStream
+}
+
+type streamGreeterSayHelloServerStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamGreeterSayHelloServerStreamServer) Send(m *StreamReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamGreeter_SayHelloClientStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamGreeterServer).SayHelloClientStream(&streamGreeterSayHelloClientStreamServer{stream})
+}
+
+type StreamGreeter_SayHelloClientStreamServer interface {
+	SendAndClose(*StreamReply) error
+	Recv() (*StreamRequest, error)
+	grpc.ServerStream
+}
+
+type streamGreeterSayHelloClientStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamGreeterSayHelloClientStreamServer) SendAndClose(m *StreamReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamGreeterSayHelloClientStreamServer) Recv() (*StreamRequest, error) {
+	m := new(StreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _StreamGreeter_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "stream.StreamGreeter",
+	HandlerType: (*StreamGreeterServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SayHelloStream",
+			Handler:       _StreamGreeter_SayHelloStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SayHelloServerStream",
+			Handler:       _StreamGreeter_SayHelloServerStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SayHelloClientStream",
+			Handler:       _StreamGreeter_SayHelloClientStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "stream.proto",
+}
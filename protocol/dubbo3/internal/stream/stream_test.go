@@ -0,0 +1,277 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/filter"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/dubbo3"
+	"dubbo.apache.org/dubbo-go/v3/protocol/protocolwrapper"
+)
+
+const (
+	streamProviderURL = "tri://127.0.0.1:20005/org.apache.dubbo.StreamGreeter?interface=org.apache.dubbo.StreamGreeter" +
+		"&bean.name=streamGreeterImpl&timeout=3s&service.filter=streamevent&reference.filter=streamevent"
+)
+
+type StreamProvider struct {
+	*StreamGreeterProviderBase
+	canceled chan error
+}
+
+func (p *StreamProvider) SayHelloStream(stream StreamGreeter_SayHelloStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(&StreamReply{Message: "Hello " + req.Name}); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *StreamProvider) SayHelloServerStream(req *StreamRequest, stream StreamGreeter_SayHelloServerStreamServer) error {
+	switch req.Name {
+	case "wait":
+		<-stream.Context().Done()
+		p.canceled <- stream.Context().Err()
+		return stream.Context().Err()
+	case "error":
+		return status.Error(codes.InvalidArgument, "invalid name")
+	case "metadata":
+		if err := stream.SetHeader(metadata.Pairs("header", "1")); err != nil {
+			return err
+		}
+		if err := stream.SendHeader(metadata.Pairs("header", "2")); err != nil {
+			return err
+		}
+		if err := stream.SetHeader(metadata.Pairs("header", "3")); err == nil {
+			return status.Error(codes.Internal, "the header is set after it's sent")
+		}
+		stream.SetTrailer(metadata.Pairs("trailer", "1"))
+		return stream.Send(&StreamReply{Message: "Hello metadata"})
+	}
+	for i := 0; i < 3; i++ {
+		if err := stream.Send(&StreamReply{Message: fmt.Sprintf("Hello %s %d", req.Name, i)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *StreamProvider) SayHelloClientStream(stream StreamGreeter_SayHelloClientStreamServer) error {
+	var names []string
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&StreamReply{Message: "Hello " + strings.Join(names, ",")})
+		}
+		if err != nil {
+			return err
+		}
+		names = append(names, req.Name)
+	}
+}
+
+// streamEventFilter records the stream events of provider and consumer
+type streamEventFilter struct{}
+
+var (
+	streamEventsLock sync.Mutex
+	streamEvents     []string
+)
+
+func (f *streamEventFilter) Invoke(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	return invoker.Invoke(ctx, invocation)
+}
+
+func (f *streamEventFilter) OnResponse(ctx context.Context, result protocol.Result, invoker protocol.Invoker,
+	invocation protocol.Invocation) protocol.Result {
+	return result
+}
+
+func (f *streamEventFilter) OnStreamOpen(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation) {
+	recordStreamEvent("open " + invocation.MethodName())
+}
+
+func (f *streamEventFilter) OnStreamClose(ctx context.Context, invoker protocol.Invoker, invocation protocol.Invocation, err error) {
+	recordStreamEvent(fmt.Sprintf("close %s: %v", invocation.MethodName(), status.Code(err)))
+}
+
+func recordStreamEvent(event string) {
+	streamEventsLock.Lock()
+	defer streamEventsLock.Unlock()
+	streamEvents = append(streamEvents, event)
+}
+
+func countStreamEvents() int {
+	streamEventsLock.Lock()
+	defer streamEventsLock.Unlock()
+	return len(streamEvents)
+}
+
+func takeStreamEvents() []string {
+	streamEventsLock.Lock()
+	defer streamEventsLock.Unlock()
+	events := streamEvents
+	streamEvents = nil
+	return events
+}
+
+func init() {
+	extension.SetFilter("streamevent", func() filter.Filter {
+		return &streamEventFilter{}
+	})
+}
+
+func TestStreamingCalls(t *testing.T) {
+	url, err := common.NewURL(streamProviderURL)
+	assert.NoError(t, err)
+
+	// export
+	provider := &StreamProvider{StreamGreeterProviderBase: &StreamGreeterProviderBase{}, canceled: make(chan error, 1)}
+	config.SetProviderService(provider)
+	_, err = common.ServiceMap.Register(url.GetParam(constant.INTERFACE_KEY, ""), url.Protocol, "", "", provider)
+	assert.NoError(t, err)
+	invoker := protocolwrapper.BuildInvokerChain(proxy_factory.NewDefaultProxyFactory().GetInvoker(url), constant.SERVICE_FILTER_KEY)
+	proto := dubbo3.GetProtocol()
+	defer proto.Destroy()
+	proto.Export(invoker)
+	time.Sleep(time.Second)
+
+	// refer
+	client := &StreamGreeterClientImpl{}
+	config.SetConsumerService(client)
+	consumerInvoker, err := dubbo3.NewDubboInvoker(url)
+	assert.NoError(t, err)
+	proxy.NewProxy(protocolwrapper.BuildInvokerChain(consumerInvoker, constant.REFERENCE_FILTER_KEY), nil, nil).Implement(client)
+
+	// bidi streaming
+	bidi, err := client.SayHelloStream(context.Background())
+	assert.NoError(t, err)
+	for _, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, bidi.Send(&StreamRequest{Name: name}))
+		reply, err := bidi.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello "+name, reply.Message)
+	}
+	assert.NoError(t, bidi.CloseSend())
+	_, err = bidi.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Eventually(t, func() bool {
+		return countStreamEvents() == 4
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"open SayHelloStream", "open SayHelloStream", "close SayHelloStream: OK",
+		"close SayHelloStream: OK"}, takeStreamEvents())
+
+	// server streaming
+	server, err := client.SayHelloServerStream(context.Background(), &StreamRequest{Name: "d"})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		reply, err := server.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Hello d %d", i), reply.Message)
+	}
+	_, err = server.Recv()
+	assert.Equal(t, io.EOF, err)
+	header, err := server.Header()
+	assert.NoError(t, err)
+	assert.Empty(t, header)
+	assert.Empty(t, server.Trailer())
+
+	// the header and trailer of provider
+	server, err = client.SayHelloServerStream(context.Background(), &StreamRequest{Name: "metadata"})
+	assert.NoError(t, err)
+	header, err = server.Header()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, header.Get("header"))
+	reply, err := server.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello metadata", reply.Message)
+	_, err = server.Recv()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"1"}, server.Trailer().Get("trailer"))
+
+	// the status of provider is returned to consumer
+	server, err = client.SayHelloServerStream(context.Background(), &StreamRequest{Name: "error"})
+	assert.NoError(t, err)
+	_, err = server.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "invalid name", status.Convert(err).Message())
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, codes.InvalidArgument, rpcErr.Code)
+
+	// client streaming
+	clientStream, err := client.SayHelloClientStream(context.Background())
+	assert.NoError(t, err)
+	for _, name := range []string{"e", "f"} {
+		assert.NoError(t, clientStream.Send(&StreamRequest{Name: name}))
+	}
+	reply, err = clientStream.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello e,f", reply.Message)
+	takeStreamEvents()
+
+	// cancellation is propagated to the context of provider
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err = client.SayHelloServerStream(ctx, &StreamRequest{Name: "wait"})
+	assert.NoError(t, err)
+	cancel()
+	_, err = server.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	select {
+	case err = <-provider.canceled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("the context of provider is not canceled")
+	}
+	assert.Eventually(t, func() bool {
+		return countStreamEvents() == 4
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"open SayHelloServerStream", "open SayHelloServerStream",
+		"close SayHelloServerStream: Canceled", "close SayHelloServerStream: Canceled"}, takeStreamEvents())
+}
@@ -144,15 +144,29 @@ func (g *dubboGrpc) generateService(file *generator.FileDescriptor, service *pb.
 		outputTypeNames := strings.Split(method.GetOutputType(), ".")
 		outputTypeName := outputTypeNames[len(outputTypeNames)-1]
 		if method.GetServerStreaming() || method.GetClientStreaming() {
-			//now we only support two way streaming
-			g.P(fmt.Sprintf("func (c *%sDubbo3Client) %s(ctx %s.Context,opt ...grpc.CallOption) (%s, error) {",
-				lowerServName, method.GetName(), contextPkg, servName+"_"+method.GetName()+"Client"))
+			// streaming rpc method client, the request is sent at once if the client doesn't stream
+			reqArg := ""
+			if !method.GetClientStreaming() {
+				reqArg = fmt.Sprintf(" in *%s,", inputTypeName)
+			}
+			g.P(fmt.Sprintf("func (c *%sDubbo3Client) %s(ctx %s.Context,%s opt ...grpc.CallOption) (%s, error) {",
+				lowerServName, method.GetName(), contextPkg, reqArg, servName+"_"+method.GetName()+"Client"))
 			g.P(fmt.Sprintf("interfaceKey := ctx.Value(tripleConstant.InterfaceKey).(string)"))
-			g.P(fmt.Sprintf("stream, err := c.cc.NewStream(ctx, \"/\" + interfaceKey + \"/%s\", opt...)", method.GetName()))
+			g.P(fmt.Sprintf("desc := &grpc.StreamDesc{StreamName: %q, ServerStreams: %t, ClientStreams: %t}",
+				method.GetName(), method.GetServerStreaming(), method.GetClientStreaming()))
+			g.P(fmt.Sprintf("stream, err := dgrpc.NewClientStream(ctx, desc, c.cc, \"/\" + interfaceKey + \"/%s\", opt...)", method.GetName()))
 			g.P("if err != nil {")
 			g.P("return nil, err")
 			g.P("}")
 			g.P(fmt.Sprintf("x := &%s%sClient{stream}", lowerFrontServeName, method.GetName()))
+			if !method.GetClientStreaming() {
+				g.P("if err := x.ClientStream.SendMsg(in); err != nil {")
+				g.P("return nil, err")
+				g.P("}")
+				g.P("if err := x.ClientStream.CloseSend(); err != nil {")
+				g.P("return nil, err")
+				g.P("}")
+			}
 			g.P("return x, nil")
 			g.P("}")
 			continue
@@ -350,21 +364,20 @@ func (g *dubboGrpc) generateServerMethod(servName, fullServName string, method *
 		g.P()
 		return hname
 	}
-	// streaming rpc
+	// streaming rpc, the stream is passed to the proxy invoker as the last argument, so that the filters observe it
 	streamType := unexport(servName) + methName + "Server"
 	g.P("func ", hname, "(srv interface{}, stream ", grpcPkg, ".ServerStream) error {")
-	g.P("_, ok := srv.(dgrpc.Dubbo3GrpcService)")
-	g.P(`invo := invocation.NewRPCInvocation("`, methName, `", nil, nil)`)
-	g.P("if !ok {")
-	g.P("fmt.Println(invo)")
-	g.P("}")
+	g.P("base := srv.(dgrpc.Dubbo3GrpcService)")
+	g.P("ss := dgrpc.NewServerStream(stream)")
+	g.P("args := []interface{}{}")
 	if !method.GetClientStreaming() {
-		g.P("m := new(", inType, ")")
-		g.P("if err := stream.RecvMsg(m); err != nil { return err }")
-		g.P("return srv.(", servName, "Server).", methName, "(m, &", streamType, "{stream})")
-	} else {
-		g.P("return srv.(", servName, "Server).", methName, "(&", streamType, "{stream})")
+		g.P("in := new(", inType, ")")
+		g.P("if err := ss.RecvMsg(in); err != nil { return ss.Close(err) }")
+		g.P("args = append(args, in)")
 	}
+	g.P("args = append(args, &", streamType, "{ss})")
+	g.P(`invo := invocation.NewRPCInvocation("`, methName, `", args, nil)`)
+	g.P("return ss.Invoke(base.GetProxyImpl(), invo)")
 	g.P("}")
 	g.P()
 
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+import (
+	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
+	"github.com/golang/protobuf/proto"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+const (
+	// trailerKeyStatusDetailsBin is the standard grpc trailer of the status with details
+	trailerKeyStatusDetailsBin = "grpc-status-details-bin"
+	// statusMarker separates the message of statusError and the status, it is only used inside the provider
+	// to hand over the status to headerHandler, and it is never replied.
+	statusMarker = "\x00status:"
+)
+
+// the error of unary call returned by the triple client, which only exposes the code and message of trailers
+var unaryErrorPattern = regexp.MustCompile(`grpc status not success, msg = (?s:(.*)), code = (\d+)$`)
+
+// WriteTripleFinalRspHeaderField writes the status handed over by @grpcMessage, or the code and message
+// as triple does if there isn't one
+func (h *headerHandler) WriteTripleFinalRspHeaderField(w http.ResponseWriter, grpcStatusCode int,
+	grpcMessage string, traceProtoBin int) {
+	i := strings.LastIndex(grpcMessage, statusMarker)
+	if i < 0 {
+		h.ProtocolHeaderHandler.WriteTripleFinalRspHeaderField(w, grpcStatusCode, grpcMessage, traceProtoBin)
+		return
+	}
+	st := &spb.Status{}
+	value, err := base64.RawStdEncoding.DecodeString(grpcMessage[i+len(statusMarker):])
+	if err == nil {
+		err = proto.Unmarshal(value, st)
+	}
+	if err != nil {
+		logger.Warnf("Could not decode the status of %s, error: %v", grpcMessage[:i], err)
+		h.ProtocolHeaderHandler.WriteTripleFinalRspHeaderField(w, grpcStatusCode, grpcMessage[:i], traceProtoBin)
+		return
+	}
+	w.Header().Set(tripleConstant.TrailerKeyGrpcStatus, strconv.Itoa(int(st.Code)))
+	w.Header().Set(tripleConstant.TrailerKeyGrpcMessage, st.Message)
+	if len(st.Details) > 0 {
+		w.Header().Set(trailerKeyStatusDetailsBin, base64.RawStdEncoding.EncodeToString(value))
+	}
+}
+
+// statusError is the error replied by provider, the status is handed over to headerHandler by its message
+type statusError struct {
+	s     *status.Status
+	cause error
+}
+
+// Error returns the message of status followed by the encoded status
+func (e *statusError) Error() string {
+	value, err := proto.Marshal(e.s.Proto())
+	if err != nil {
+		logger.Warnf("Could not encode the status of %v, error: %v", e.cause, err)
+		return e.s.Message()
+	}
+	return e.s.Message() + statusMarker + base64.RawStdEncoding.EncodeToString(value)
+}
+
+// GRPCStatus returns the status of error
+func (e *statusError) GRPCStatus() *status.Status {
+	return e.s
+}
+
+// Unwrap returns the error returned by service
+func (e *statusError) Unwrap() error {
+	return e.cause
+}
+
+// fromUnaryError maps the error of unary call to protocol.RPCError by the code and message of trailers.
+// The triple client only returns them as the text of error, so the details of status are not available.
+func fromUnaryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if m := unaryErrorPattern.FindStringSubmatch(err.Error()); m != nil {
+		if code, e := strconv.Atoi(m[2]); e == nil && codes.Code(code) != codes.OK {
+			return protocol.WrapRPCError(err, codes.Code(code), m[1])
+		}
+	}
+	return protocol.ToRPCError(err)
+}
+
+// statusInvoker replies the error of service with statusError, so that the consumer gets the protocol.RPCError
+type statusInvoker struct {
+	protocol.Invoker
+}
+
+// Invoke invokes the service and maps its error to statusError
+func (si *statusInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	result := si.Invoker.Invoke(ctx, invocation)
+	if err := result.Error(); err != nil {
+		// the status of stream is sent by its end frame
+		if protocol.GetStreamState(invocation) == nil {
+			result.SetError(&statusError{s: protocol.ToRPCError(err).GRPCStatus(), cause: err})
+		}
+	}
+	return result
+}
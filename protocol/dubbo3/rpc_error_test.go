@@ -0,0 +1,103 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+import (
+	tripleConstant "github.com/dubbogo/triple/pkg/common/constant"
+	triConfig "github.com/dubbogo/triple/pkg/config"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type errorInvoker struct {
+	protocol.Invoker
+	err error
+}
+
+func (ei *errorInvoker) Invoke(context.Context, protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Err: ei.err}
+}
+
+func TestStatusInvoker(t *testing.T) {
+	detail := &wrappers.StringValue{Value: "detail"}
+	invoker := &statusInvoker{Invoker: &errorInvoker{err: protocol.NewRPCError(codes.NotFound, "no such name", detail)}}
+	err := invoker.Invoke(context.Background(), invocation.NewRPCInvocation("SayHello", nil, nil)).Error()
+	assert.True(t, strings.HasPrefix(err.Error(), "no such name"+statusMarker))
+
+	// the error is handed over to the header handler in the message of codes.Internal by triple,
+	// and the status is replied by the standard trailers
+	handler := newHeaderHandler(triConfig.NewTripleOption(triConfig.WithProtocol(headerProtocol)), context.Background())
+	w := httptest.NewRecorder()
+	handler.WriteTripleFinalRspHeaderField(w, int(codes.Internal), "Unary rpc handle error: "+err.Error(), 0)
+	assert.Equal(t, strconv.Itoa(int(codes.NotFound)), w.Header().Get(tripleConstant.TrailerKeyGrpcStatus))
+	assert.Equal(t, "no such name", w.Header().Get(tripleConstant.TrailerKeyGrpcMessage))
+	value, e := base64.RawStdEncoding.DecodeString(w.Header().Get(trailerKeyStatusDetailsBin))
+	assert.NoError(t, e)
+	st := &spb.Status{}
+	assert.NoError(t, proto.Unmarshal(value, st))
+	rpcErr := protocol.FromGRPCStatus(status.FromProto(st), nil)
+	assert.Equal(t, codes.NotFound, rpcErr.Code)
+	assert.Len(t, rpcErr.Details, 1)
+	assert.True(t, proto.Equal(detail, rpcErr.Details[0].(proto.Message)))
+
+	// the other errors are replied as triple does
+	w = httptest.NewRecorder()
+	handler.WriteTripleFinalRspHeaderField(w, int(codes.Unimplemented), "not found target service key", 0)
+	assert.Equal(t, strconv.Itoa(int(codes.Unimplemented)), w.Header().Get(tripleConstant.TrailerKeyGrpcStatus))
+	assert.Equal(t, "not found target service key", w.Header().Get(tripleConstant.TrailerKeyGrpcMessage))
+	assert.Empty(t, w.Header().Get(trailerKeyStatusDetailsBin))
+
+	// the stream replies its status by the end frame
+	inv := invocation.NewRPCInvocation("SayHelloStream", nil, nil)
+	streamErr := errors.New("stream error")
+	inv.SetAttribute(constant.STREAM_STATE_KEY, protocol.NewStreamState())
+	invoker = &statusInvoker{Invoker: &errorInvoker{err: streamErr}}
+	assert.Equal(t, streamErr, invoker.Invoke(context.Background(), inv).Error())
+}
+
+func TestFromUnaryError(t *testing.T) {
+	assert.Nil(t, fromUnaryError(nil))
+
+	err := fromUnaryError(errors.New("grpc status not success, msg = no such name, code = 5"))
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, codes.NotFound, rpcErr.Code)
+	assert.Equal(t, "no such name", rpcErr.Message)
+
+	assert.Equal(t, codes.Unknown, protocol.ErrorCode(fromUnaryError(errors.New("unary call timeout"))))
+}
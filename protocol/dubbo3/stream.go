@@ -0,0 +1,528 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+import (
+	"github.com/dubbogo/triple/pkg/triple"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	perrors "github.com/pkg/errors"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// the transport of triple neither carries the half close and cancellation of streams, nor the metadata and status
+// which end them, so each message of a stream is sent as a frame whose type url tells data from the signals.
+// The frames are specific to dubbo-go, so the streams of dubbo3 protocol can not be consumed or provided by
+// dubbo java or grpc, whose streams carry the messages directly.
+const (
+	streamDataTypeURL    = "dubbo.apache.org/triple.StreamData"
+	streamHeaderTypeURL  = "dubbo.apache.org/triple.StreamHeader"
+	streamTrailerTypeURL = "dubbo.apache.org/triple.StreamTrailer"
+	streamEndTypeURL     = "dubbo.apache.org/triple.StreamEnd"
+	streamCancelTypeURL  = "dubbo.apache.org/triple.StreamCancel"
+)
+
+var (
+	// the streaming methods of stub return the typed grpc.ClientStream
+	clientStreamType = reflect.TypeOf((*grpc.ClientStream)(nil)).Elem()
+
+	errStreamSendClosed = perrors.New("send on closed stream")
+	errHeaderSent       = perrors.New("the header of stream has been sent")
+)
+
+// streamStateCtxKey is the key of the protocol.StreamState in the ctx passed to the streaming method of stub
+type streamStateCtxKey struct{}
+
+// streamTransport is the stream of triple, both grpc.ClientStream and grpc.ServerStream are streamTransport
+type streamTransport interface {
+	SendMsg(m interface{}) error
+	RecvMsg(m interface{}) error
+}
+
+// frameStream sends and receives the frames of a stream on the transport of triple.
+// The frames are received by a pump goroutine without buffering, so a receiver that doesn't keep up blocks the pump
+// and then the http2 flow control of the transport throttles the sender.
+type frameStream struct {
+	transport streamTransport
+	ctx       context.Context
+	cancel    context.CancelFunc
+	state     *protocol.StreamState
+
+	frames chan *any.Any
+	// ended is closed once the end frame of peer is received after all data frames, and endErr is its status
+	ended  chan struct{}
+	endErr error
+	// done is closed once the pump stops because of the error of transport
+	done    chan struct{}
+	doneErr error
+	// recvErr is the error returned by all later receives, io.EOF if the peer has ended the stream normally
+	recvErr error
+
+	// headerReady is closed once the header of peer is received, or it won't be received
+	headerReady chan struct{}
+	headerOnce  sync.Once
+	mdLock      sync.Mutex
+	header      metadata.MD
+	trailer     metadata.MD
+
+	sendLock   sync.Mutex
+	sendClosed bool
+}
+
+func newFrameStream(ctx context.Context, transport streamTransport, state *protocol.StreamState) *frameStream {
+	ctx, cancel := context.WithCancel(ctx)
+	return &frameStream{
+		transport: transport,
+		ctx:       ctx,
+		cancel:    cancel,
+		state:     state,
+		frames:    make(chan *any.Any),
+		ended:     make(chan struct{}),
+		done:      make(chan struct{}),
+
+		headerReady: make(chan struct{}),
+	}
+}
+
+// headerReceived marks the header of peer is received, the header is sent before any data, end or trailer frames
+func (fs *frameStream) headerReceived() {
+	fs.headerOnce.Do(func() {
+		close(fs.headerReady)
+	})
+}
+
+// pump receives the frames from transport until it fails, @onCancel is called when the peer cancels the stream.
+// The frames are discarded once the stream is finished, so that the transport isn't blocked.
+func (fs *frameStream) pump(onCancel func()) {
+	defer close(fs.done)
+	defer fs.headerReceived()
+	ended := false
+	for {
+		frame := &any.Any{}
+		if err := fs.transport.RecvMsg(frame); err != nil {
+			fs.doneErr = err
+			return
+		}
+		if frame.TypeUrl == streamCancelTypeURL {
+			onCancel()
+			continue
+		}
+		if frame.TypeUrl == streamHeaderTypeURL || frame.TypeUrl == streamTrailerTypeURL {
+			fs.receiveMetadata(frame)
+		}
+		// the header is sent before the other frames
+		fs.headerReceived()
+		switch {
+		case frame.TypeUrl == streamHeaderTypeURL || frame.TypeUrl == streamTrailerTypeURL:
+			continue
+		case frame.TypeUrl == streamEndTypeURL && !ended:
+			ended = true
+			fs.endErr = decodeStreamStatus(frame.Value)
+			close(fs.ended)
+			continue
+		}
+		select {
+		case fs.frames <- frame:
+		case <-fs.ctx.Done():
+		}
+	}
+}
+
+// receiveMetadata merges the metadata of header or trailer @frame
+func (fs *frameStream) receiveMetadata(frame *any.Any) {
+	md, err := decodeMetadata(frame.Value)
+	if err != nil {
+		logger.Warnf("Could not decode the metadata of stream, error: %v", err)
+		return
+	}
+	fs.mdLock.Lock()
+	defer fs.mdLock.Unlock()
+	if frame.TypeUrl == streamHeaderTypeURL {
+		fs.header = metadata.Join(fs.header, md)
+	} else {
+		fs.trailer = metadata.Join(fs.trailer, md)
+	}
+}
+
+// recv receives the next data frame into @m, the end frame of peer is returned as io.EOF or the protocol.RPCError
+// it carries
+func (fs *frameStream) recv(m interface{}) error {
+	if fs.recvErr != nil {
+		return fs.recvErr
+	}
+	select {
+	case frame := <-fs.frames:
+		switch frame.TypeUrl {
+		case streamDataTypeURL:
+			msg, ok := m.(proto.Message)
+			if !ok {
+				return status.Errorf(codes.Internal, "unexpected message %T, which is not proto.Message", m)
+			}
+			return proto.Unmarshal(frame.Value, msg)
+		default:
+			fs.recvErr = status.Errorf(codes.Internal, "unknown stream frame %s", frame.TypeUrl)
+		}
+	case <-fs.ended:
+		fs.recvErr = fs.endErr
+	case <-fs.done:
+		fs.recvErr = status.Errorf(codes.Unavailable, "stream transport is closed: %v", fs.doneErr)
+	case <-fs.ctx.Done():
+		fs.recvErr = contextStatusError(fs.ctx.Err())
+	}
+	return fs.recvErr
+}
+
+// sendData sends @m as a data frame
+func (fs *frameStream) sendData(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message %T, which is not proto.Message", m)
+	}
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return fs.send(streamDataTypeURL, value)
+}
+
+// send sends a frame, only the cancel frame can be sent after the send direction is closed by an end frame
+func (fs *frameStream) send(typeURL string, value []byte) (err error) {
+	fs.sendLock.Lock()
+	defer fs.sendLock.Unlock()
+	if fs.sendClosed && typeURL != streamCancelTypeURL {
+		return errStreamSendClosed
+	}
+	fs.sendClosed = fs.sendClosed || typeURL == streamEndTypeURL || typeURL == streamCancelTypeURL
+	// the send queue of triple is closed once the transport is done, and sending on it panics
+	defer func() {
+		if e := recover(); e != nil {
+			err = status.Errorf(codes.Unavailable, "stream transport is closed: %v", e)
+		}
+	}()
+	return fs.transport.SendMsg(&any.Any{TypeUrl: typeURL, Value: value})
+}
+
+// peerEnded returns whether the peer has ended the stream or the transport is done
+func (fs *frameStream) peerEnded() bool {
+	select {
+	case <-fs.ended:
+		return true
+	case <-fs.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish closes the state of stream with @err and releases the stream
+func (fs *frameStream) finish(err error) {
+	fs.state.Close(err)
+	fs.cancel()
+}
+
+// clientStream is the grpc.ClientStream used by the streaming methods of stub
+type clientStream struct {
+	*frameStream
+	desc *grpc.StreamDesc
+}
+
+// NewClientStream opens a stream of @method on @cc, it is called by the streaming methods of stub generated by
+// protoc-gen-dubbo3. Canceling @ctx cancels the stream at both sides.
+func NewClientStream(ctx context.Context, desc *grpc.StreamDesc, cc *triple.TripleConn, method string,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	state, ok := ctx.Value(streamStateCtxKey{}).(*protocol.StreamState)
+	if !ok {
+		state = protocol.NewStreamState()
+	}
+	transport, err := cc.NewStream(ctx, method, opts...)
+	if err != nil {
+		state.Close(err)
+		return nil, err
+	}
+
+	cs := &clientStream{
+		frameStream: newFrameStream(ctx, transport, state),
+		desc:        desc,
+	}
+	go cs.pump(func() {})
+	go func() {
+		<-cs.ctx.Done()
+		// the provider is notified if the stream is canceled by the consumer before the provider ends it
+		if err := ctx.Err(); err != nil {
+			cs.finish(contextStatusError(err))
+			if !cs.peerEnded() {
+				_ = cs.send(streamCancelTypeURL, nil)
+			}
+		}
+	}()
+	return cs, nil
+}
+
+// Header returns the header sent by provider, it blocks until the header is received or the stream is finished
+func (cs *clientStream) Header() (metadata.MD, error) {
+	select {
+	case <-cs.headerReady:
+	case <-cs.ctx.Done():
+		// the header may be received just before the stream is finished
+		select {
+		case <-cs.headerReady:
+		default:
+			return nil, contextStatusError(cs.ctx.Err())
+		}
+	}
+	cs.mdLock.Lock()
+	defer cs.mdLock.Unlock()
+	return cs.header.Copy(), nil
+}
+
+// Trailer returns the trailer sent by provider, it's only available after RecvMsg returns an error or io.EOF
+func (cs *clientStream) Trailer() metadata.MD {
+	cs.mdLock.Lock()
+	defer cs.mdLock.Unlock()
+	return cs.trailer.Copy()
+}
+
+// CloseSend closes the send direction of stream
+func (cs *clientStream) CloseSend() error {
+	if err := cs.send(streamEndTypeURL, nil); err != nil && err != errStreamSendClosed {
+		return err
+	}
+	return nil
+}
+
+// Context returns the context of stream, which is canceled once the stream is finished
+func (cs *clientStream) Context() context.Context {
+	return cs.ctx
+}
+
+// SendMsg sends @m, it returns io.EOF if the stream is finished, and the status can be got by RecvMsg
+func (cs *clientStream) SendMsg(m interface{}) error {
+	if cs.state.Closed() {
+		return io.EOF
+	}
+	return cs.sendData(m)
+}
+
+// RecvMsg receives a message into @m, it returns io.EOF if the provider finishes the stream normally
+func (cs *clientStream) RecvMsg(m interface{}) error {
+	err := cs.recv(m)
+	if err == nil && !cs.desc.ServerStreams {
+		// the stream is finished after the only response
+		if err = cs.recv(nil); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = status.Errorf(codes.Internal, "more than one response of %s", cs.desc.StreamName)
+		}
+		cs.finish(err)
+		return err
+	}
+	if err == io.EOF {
+		cs.finish(nil)
+	} else if err != nil {
+		cs.finish(err)
+	}
+	return err
+}
+
+// ServerStream is the grpc.ServerStream passed to the streaming methods of provider, its context is canceled
+// once the consumer cancels the stream or the method returns.
+type ServerStream struct {
+	*frameStream
+
+	// sendHeader and sendTrailer are sent to consumer before the first message and the end of stream
+	sendMDLock  sync.Mutex
+	sendHeader  metadata.MD
+	headerSent  bool
+	sendTrailer metadata.MD
+}
+
+// NewServerStream wraps the stream of triple, it is called by the stream handlers generated by protoc-gen-dubbo3.
+func NewServerStream(transport grpc.ServerStream) *ServerStream {
+	ss := &ServerStream{
+		frameStream: newFrameStream(context.Background(), transport, protocol.NewStreamState()),
+	}
+	go ss.pump(ss.cancel)
+	return ss
+}
+
+// SetHeader sets the header which is sent before the first message, it can be called multiple times
+// before the header is sent
+func (ss *ServerStream) SetHeader(md metadata.MD) error {
+	ss.sendMDLock.Lock()
+	defer ss.sendMDLock.Unlock()
+	if ss.headerSent {
+		return errHeaderSent
+	}
+	ss.sendHeader = metadata.Join(ss.sendHeader, md)
+	return nil
+}
+
+// SendHeader sends the header set by SetHeader and @md, it can be called at most once
+func (ss *ServerStream) SendHeader(md metadata.MD) error {
+	if err := ss.SetHeader(md); err != nil {
+		return err
+	}
+	return ss.flushHeader()
+}
+
+// SetTrailer sets the trailer which is sent with the status of stream, it can be called multiple times
+func (ss *ServerStream) SetTrailer(md metadata.MD) {
+	ss.sendMDLock.Lock()
+	defer ss.sendMDLock.Unlock()
+	ss.sendTrailer = metadata.Join(ss.sendTrailer, md)
+}
+
+// flushHeader sends the header if it's not sent yet, the empty header is not sent
+func (ss *ServerStream) flushHeader() error {
+	ss.sendMDLock.Lock()
+	defer ss.sendMDLock.Unlock()
+	if ss.headerSent {
+		return nil
+	}
+	ss.headerSent = true
+	if len(ss.sendHeader) == 0 {
+		return nil
+	}
+	return ss.send(streamHeaderTypeURL, encodeMetadata(ss.sendHeader))
+}
+
+// Context returns the context of stream
+func (ss *ServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+// SendMsg sends @m to consumer
+func (ss *ServerStream) SendMsg(m interface{}) error {
+	if err := ss.ctx.Err(); err != nil {
+		return contextStatusError(err)
+	}
+	if err := ss.flushHeader(); err != nil {
+		return err
+	}
+	return ss.sendData(m)
+}
+
+// RecvMsg receives a message into @m, it returns io.EOF once the consumer closes the send direction
+func (ss *ServerStream) RecvMsg(m interface{}) error {
+	return ss.recv(m)
+}
+
+// Invoke invokes the streaming method by @invoker with @invocation and finishes the stream with its error.
+// The StreamState of stream is put into the attributes of @invocation, so the filters can observe the stream.
+func (ss *ServerStream) Invoke(invoker protocol.Invoker, invocation *invocation_impl.RPCInvocation) error {
+	invocation.SetAttribute(constant.STREAM_STATE_KEY, ss.state)
+	return ss.Close(invoker.Invoke(ss.ctx, invocation).Error())
+}
+
+// Close finishes the stream with @err, and sends the status of @err to consumer. It returns @err, which
+// is returned by the stream handler.
+func (ss *ServerStream) Close(err error) error {
+	if cause := perrors.Cause(err); cause == context.Canceled || cause == context.DeadlineExceeded {
+		err = contextStatusError(cause)
+	}
+	if ss.ctx.Err() == nil {
+		if sendErr := ss.sendEnd(err); sendErr != nil {
+			logger.Warnf("Could not send the end of stream, error: %v", sendErr)
+		}
+	}
+	ss.finish(err)
+	return err
+}
+
+// sendEnd sends the header and trailer which are not sent yet, and then the status of @err
+func (ss *ServerStream) sendEnd(err error) error {
+	if sendErr := ss.flushHeader(); sendErr != nil {
+		return sendErr
+	}
+	ss.sendMDLock.Lock()
+	trailer := ss.sendTrailer
+	ss.sendMDLock.Unlock()
+	if len(trailer) > 0 {
+		if sendErr := ss.send(streamTrailerTypeURL, encodeMetadata(trailer)); sendErr != nil {
+			return sendErr
+		}
+	}
+	return ss.send(streamEndTypeURL, encodeStreamStatus(err))
+}
+
+// encodeMetadata encodes @md as the query string
+func encodeMetadata(md metadata.MD) []byte {
+	return []byte(url.Values(md).Encode())
+}
+
+// decodeMetadata decodes the metadata encoded by encodeMetadata
+func decodeMetadata(value []byte) (metadata.MD, error) {
+	values, err := url.ParseQuery(string(value))
+	return metadata.MD(values), err
+}
+
+// encodeStreamStatus encodes the status of @err, it's empty if @err is nil
+func encodeStreamStatus(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	value, e := proto.Marshal(protocol.ToRPCError(err).GRPCStatus().Proto())
+	if e != nil {
+		logger.Warnf("Could not encode the status of %v, error: %v", err, e)
+		return nil
+	}
+	return value
+}
+
+// decodeStreamStatus decodes the status encoded by encodeStreamStatus to protocol.RPCError, it returns io.EOF for
+// the ok status
+func decodeStreamStatus(value []byte) error {
+	if len(value) == 0 {
+		return io.EOF
+	}
+	st := &spb.Status{}
+	if err := proto.Unmarshal(value, st); err != nil {
+		return status.Errorf(codes.Internal, "invalid status of stream: %v", err)
+	}
+	if st.Code == int32(codes.OK) {
+		return io.EOF
+	}
+	return protocol.FromGRPCStatus(status.FromProto(st), nil)
+}
+
+// contextStatusError converts the error of context into status error
+func contextStatusError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Canceled, err.Error())
+}
@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo3
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+import (
+	"github.com/apache/dubbo-getty"
+	h2 "github.com/dubbogo/net/http2"
+	triConfig "github.com/dubbogo/triple/pkg/config"
+	"github.com/dubbogo/triple/pkg/triple"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+// tlsHandshakeTimeout is the timeout of tls handshake of the accepted connections
+const tlsHandshakeTimeout = 10 * time.Second
+
+// tripleServer is the server exported by dubbo3 protocol
+type tripleServer interface {
+	Start()
+	Stop()
+}
+
+// tlsTripleServer serves the triple services over tls, as triple.TripleServer only listens on plain tcp
+type tlsTripleServer struct {
+	serviceMap *sync.Map
+	opt        *triConfig.Option
+	tlsConfig  *tls.Config
+	lst        net.Listener
+}
+
+// newTlsTripleServer creates a triple server which serves the connections with @tlsConfig
+func newTlsTripleServer(serviceMap *sync.Map, opt *triConfig.Option, tlsConfig *tls.Config) *tlsTripleServer {
+	opt.Validate()
+	return &tlsTripleServer{
+		serviceMap: serviceMap,
+		opt:        opt,
+		tlsConfig:  h2TlsConfig(tlsConfig),
+	}
+}
+
+// Start listens on the location of option and serves the accepted connections
+func (s *tlsTripleServer) Start() {
+	lst, err := net.Listen("tcp", s.opt.Location)
+	if err != nil {
+		panic(err)
+	}
+	s.lst = lst
+	go s.run()
+}
+
+// Stop closes the listener, the served connections are closed by the clients
+func (s *tlsTripleServer) Stop() {
+	if s.lst != nil {
+		s.lst.Close()
+	}
+}
+
+func (s *tlsTripleServer) run() {
+	var delay time.Duration
+	for {
+		c, err := s.lst.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if delay == 0 {
+					delay = 5 * time.Millisecond
+				} else if delay <<= 1; delay > triple.DefaultMaxSleepTime {
+					delay = triple.DefaultMaxSleepTime
+				}
+				time.Sleep(delay)
+				continue
+			}
+			return
+		}
+		delay = 0
+		go func() {
+			if err := s.serve(tls.Server(c, s.tlsConfig)); err != nil && err != io.EOF {
+				logger.Errorf("serve tls connection from %s error: %v", c.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// serve finishes the tls handshake before http2 checks the negotiated tls version
+func (s *tlsTripleServer) serve(conn *tls.Conn) error {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Time{})
+
+	h2Controller, err := triple.NewH2Controller(true, s.serviceMap, s.opt)
+	if err != nil {
+		return err
+	}
+	defer h2Controller.Destroy()
+	(&h2.Server{}).ServeConn(conn, &h2.ServeConnOpts{Handler: http.HandlerFunc(h2Controller.GetHandler())})
+	return nil
+}
+
+// h2TlsConfig returns a copy of @cfg which negotiates http2 by ALPN
+func h2TlsConfig(cfg *tls.Config) *tls.Config {
+	cfg = cfg.Clone()
+	cfg.NextProtos = []string{h2.NextProtoTLS}
+	if getConfigForClient := cfg.GetConfigForClient; getConfigForClient != nil {
+		cfg.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			c, err := getConfigForClient(hello)
+			if err != nil || c == nil {
+				return c, err
+			}
+			c = c.Clone()
+			c.NextProtos = []string{h2.NextProtoTLS}
+			return c, nil
+		}
+	}
+	return cfg
+}
+
+// setClientTlsConfig makes @client dial the server with @tlsConfig,
+// the transport of triple client is not configurable and always dials plain tcp.
+func setClientTlsConfig(client *triple.TripleClient, tlsConfig *tls.Config) error {
+	controller := reflect.ValueOf(client).Elem().FieldByName("h2Controller")
+	if !controller.IsValid() || controller.IsNil() {
+		return perrors.New("the http2 controller of triple client is not found")
+	}
+	field := controller.Elem().FieldByName("client")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(http.Client{}) {
+		return perrors.New("the http client of triple client is not found")
+	}
+	httpClient := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Interface().(*http.Client)
+	httpClient.Transport = &h2.Transport{TLSClientConfig: tlsConfig}
+	return nil
+}
+
+// buildTlsConfig builds the tls config by @builder
+func buildTlsConfig(builder getty.TlsConfigBuilder) (*tls.Config, error) {
+	if builder == nil {
+		return nil, perrors.New("ssl is enabled but there is no tls config")
+	}
+	return builder.BuildTlsConfig()
+}
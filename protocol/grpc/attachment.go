@@ -0,0 +1,65 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+)
+
+import (
+	"google.golang.org/grpc/metadata"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// injectPropagatedAttachments appends the propagated attachments of @invocation to the outgoing metadata
+func injectPropagatedAttachments(ctx context.Context, invocation protocol.Invocation) context.Context {
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := invocation.AttachmentsByKey(k, ""); len(v) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+		}
+	}
+	return ctx
+}
+
+// extractPropagatedAttachments puts the propagated attachments in the incoming metadata into @invocation
+func extractPropagatedAttachments(ctx context.Context, invocation protocol.Invocation) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return
+	}
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := md.Get(k); len(v) > 0 {
+			invocation.SetAttachments(k, v[0])
+		}
+	}
+}
+
+// attachmentInvoker fills the attachments of invocation with the propagated attachments in the incoming metadata
+type attachmentInvoker struct {
+	protocol.Invoker
+}
+
+// Invoke extracts the propagated attachments and invokes the service
+func (ti *attachmentInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	extractPropagatedAttachments(ctx, invocation)
+	return ti.Invoker.Invoke(ctx, invocation)
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+func TestTraceAttachments(t *testing.T) {
+	inv := invocation.NewRPCInvocation("SayHello", nil, map[string]interface{}{
+		constant.TRACEPARENT_KEY: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		constant.TRACESTATE_KEY:  "congo=t61rcWkgMzE",
+		"other":                  "value",
+	})
+	ctx := injectPropagatedAttachments(context.Background(), inv)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	assert.True(t, ok)
+	assert.Len(t, md, 2)
+
+	// the outgoing metadata is received as the incoming metadata by the server
+	serverInv := invocation.NewRPCInvocation("SayHello", nil, nil)
+	extractPropagatedAttachments(metadata.NewIncomingContext(context.Background(), md), serverInv)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		serverInv.AttachmentsByKey(constant.TRACEPARENT_KEY, ""))
+	assert.Equal(t, "congo=t61rcWkgMzE", serverInv.AttachmentsByKey(constant.TRACESTATE_KEY, ""))
+	assert.Nil(t, serverInv.Attachment("other"))
+}
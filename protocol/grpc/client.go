@@ -26,6 +26,7 @@ import (
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"gopkg.in/yaml.v2"
 )
 
@@ -97,8 +98,18 @@ func NewClient(url *common.URL) (*Client, error) {
 	// consumer config client connectTimeout
 	connectTimeout := config.GetConsumerConfig().ConnectTimeout
 
+	transportOpt := grpc.WithInsecure()
+	if url.GetParamBool(constant.SSL_ENABLED_KEY, false) {
+		tlsConfig, err := buildTlsConfig(config.GetClientTlsConfigBuilderByURL(url))
+		if err != nil {
+			logger.Errorf("grpc build tls config error: %v", err)
+			return nil, err
+		}
+		transportOpt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
 	dialOpts = append(dialOpts,
-		grpc.WithInsecure(),
+		transportOpt,
 		grpc.WithBlock(),
 		grpc.WithTimeout(connectTimeout),
 		grpc.WithUnaryInterceptor(otgrpc.OpenTracingClientInterceptor(tracer, otgrpc.LogPayloads())),
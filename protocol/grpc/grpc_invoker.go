@@ -100,8 +100,9 @@ func (gi *GrpcInvoker) Invoke(ctx context.Context, invocation protocol.Invocatio
 		result.Err = errNoReply
 	}
 
+	protocol.AttachRemainingTimeout(ctx, invocation, protocol.GetMethodTimeout(gi.GetURL(), invocation.MethodName()))
 	var in []reflect.Value
-	in = append(in, reflect.ValueOf(ctx))
+	in = append(in, reflect.ValueOf(injectPropagatedAttachments(ctx, invocation)))
 	in = append(in, invocation.ParameterValues()...)
 
 	methodName := invocation.MethodName()
@@ -111,7 +112,7 @@ func (gi *GrpcInvoker) Invoke(ctx context.Context, invocation protocol.Invocatio
 	result.Rest = res[0]
 	// check err
 	if !res[1].IsNil() {
-		result.Err = res[1].Interface().(error)
+		result.Err = protocol.ToRPCError(res[1].Interface().(error))
 	} else {
 		_ = hessian2.ReflectResponse(res[0], invocation.Reply())
 	}
@@ -0,0 +1,52 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+)
+
+import (
+	"google.golang.org/grpc"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// toStatusError maps @err returned by service to the status error of grpc, grpc itself only recognizes the
+// status error which isn't wrapped, and replies the other errors with codes.Unknown
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return protocol.ToRPCError(err).GRPCStatus().Err()
+}
+
+// rpcErrorUnaryServerInterceptor replies the error of unary call with the status mapped from protocol.RPCError
+func rpcErrorUnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatusError(err)
+}
+
+// rpcErrorStreamServerInterceptor replies the error of streaming call with the status mapped from protocol.RPCError
+func rpcErrorStreamServerInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return toStatusError(handler(srv, ss))
+}
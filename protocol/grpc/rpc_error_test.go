@@ -0,0 +1,69 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/grpc/internal"
+)
+
+func TestRPCErrorInterceptors(t *testing.T) {
+	detail := &internal.HelloReply{Message: "detail"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, perrors.WithStack(protocol.NewRPCError(codes.NotFound, "no such name", detail))
+	}
+	_, err := rpcErrorUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	s := status.Convert(err)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "no such name", s.Message())
+
+	// the consumer gets the RPCError with the typed details
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(protocol.ToRPCError(err), &rpcErr))
+	assert.Equal(t, codes.NotFound, rpcErr.Code)
+	assert.Len(t, rpcErr.Details, 1)
+	assert.True(t, proto.Equal(detail, rpcErr.Details[0].(proto.Message)))
+
+	resp, err := rpcErrorUnaryServerInterceptor(context.Background(), nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	err = rpcErrorStreamServerInterceptor(nil, nil, &grpc.StreamServerInfo{},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			return perrors.WithStack(context.DeadlineExceeded)
+		})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
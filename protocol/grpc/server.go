@@ -18,6 +18,8 @@
 package grpc
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sync"
@@ -25,19 +27,41 @@ import (
 )
 
 import (
+	"github.com/apache/dubbo-getty"
 	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/opentracing/opentracing-go"
+	perrors "github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
+// buildTlsConfig builds the tls config by @builder
+func buildTlsConfig(builder getty.TlsConfigBuilder) (*tls.Config, error) {
+	if builder == nil {
+		return nil, perrors.New("ssl is enabled but there is no tls config")
+	}
+	return builder.BuildTlsConfig()
+}
+
+// remoteAddrUnaryServerInterceptor passes the peer address of the connection to the invoker by context
+func remoteAddrUnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ctx = context.WithValue(ctx, constant.RemoteAddrKey, p.Addr.String())
+	}
+	return handler(ctx, req)
+}
+
 // DubboGrpcService is gRPC service
 type DubboGrpcService interface {
 	// SetProxyImpl sets proxy.
@@ -78,12 +102,21 @@ func (s *Server) Start(url *common.URL) {
 	// If global trace instance was set, then server tracer instance
 	// can be get. If not, will return NoopTracer.
 	tracer := opentracing.GlobalTracer()
-	server := grpc.NewServer(
-		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
-		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
-		grpc.MaxRecvMsgSize(1024*1024*s.bufferSize),
-		grpc.MaxSendMsgSize(1024*1024*s.bufferSize),
-	)
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer), rpcErrorUnaryServerInterceptor,
+			remoteAddrUnaryServerInterceptor),
+		grpc.ChainStreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer), rpcErrorStreamServerInterceptor),
+		grpc.MaxRecvMsgSize(1024 * 1024 * s.bufferSize),
+		grpc.MaxSendMsgSize(1024 * 1024 * s.bufferSize),
+	}
+	if url.GetParamBool(constant.SSL_ENABLED_KEY, false) {
+		tlsConfig, err := buildTlsConfig(config.GetServerTlsConfigBuilderByURL(url))
+		if err != nil {
+			panic(err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(serverOpts...)
 	s.grpcServer = server
 
 	go func() {
@@ -153,7 +186,7 @@ func registerService(providerServices map[string]*config.ServiceConfig, server *
 			panic(fmt.Sprintf("no invoker found for servicekey: %v", serviceKey))
 		}
 
-		ds.SetProxyImpl(invoker)
+		ds.SetProxyImpl(&attachmentInvoker{Invoker: invoker})
 		server.RegisterService(ds.ServiceDesc(), service)
 	}
 }
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"reflect"
+)
+
+// deepCopy returns a deep copy of @src. The exported fields of structs are copied recursively, the unexported fields
+// are copied by value.
+func deepCopy(src interface{}) interface{} {
+	if src == nil {
+		return nil
+	}
+	dst := copyValue(reflect.ValueOf(src), make(map[uintptr]reflect.Value))
+	return dst.Interface()
+}
+
+// copyValue copies @src recursively, @visited keeps the copied pointers to copy the cyclic references only once.
+func copyValue(src reflect.Value, visited map[uintptr]reflect.Value) reflect.Value {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return src
+		}
+		if dst, ok := visited[src.Pointer()]; ok {
+			return dst
+		}
+		dst := reflect.New(src.Type().Elem())
+		visited[src.Pointer()] = dst
+		dst.Elem().Set(copyValue(src.Elem(), visited))
+		return dst
+	case reflect.Interface:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(copyValue(src.Elem(), visited))
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if field := dst.Field(i); field.CanSet() {
+				field.Set(copyValue(src.Field(i), visited))
+			}
+		}
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(copyValue(src.Index(i), visited))
+		}
+		return dst
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(copyValue(src.Index(i), visited))
+		}
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(copyValue(iter.Key(), visited), copyValue(iter.Value(), visited))
+		}
+		return dst
+	default:
+		return src
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// InjvmExporter is injvm service exporter.
+type InjvmExporter struct {
+	protocol.BaseExporter
+}
+
+// NewInjvmExporter creates injvm exporter with @key, @invoker and @exporterMap
+func NewInjvmExporter(key string, invoker protocol.Invoker, exporterMap *sync.Map) *InjvmExporter {
+	return &InjvmExporter{
+		BaseExporter: *protocol.NewBaseExporter(key, invoker, exporterMap),
+	}
+}
+
+// Unexport unexport injvm service exporter.
+func (ie *InjvmExporter) Unexport() {
+	interfaceName := ie.GetInvoker().GetURL().GetParam(constant.INTERFACE_KEY, "")
+	ie.BaseExporter.Unexport()
+	err := common.ServiceMap.UnRegister(interfaceName, INJVM, ie.GetInvoker().GetURL().ServiceKey())
+	if err != nil {
+		logger.Errorf("[InjvmExporter.Unexport] error: %v", err)
+	}
+}
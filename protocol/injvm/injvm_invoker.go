@@ -0,0 +1,118 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	hessian2 "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+// InjvmInvoker calls the locally exported invoker, the service filters of the exporter are still applied.
+type InjvmInvoker struct {
+	protocol.BaseInvoker
+	serviceKey  string
+	exporterMap *sync.Map
+	copy        bool
+}
+
+// NewInjvmInvoker creates injvm invoker with @url and the @exporterMap of injvm protocol
+func NewInjvmInvoker(url *common.URL, exporterMap *sync.Map) *InjvmInvoker {
+	return &InjvmInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		serviceKey:  url.ServiceKey(),
+		exporterMap: exporterMap,
+		copy:        url.GetParamBool(constant.INJVM_COPY_KEY, false),
+	}
+}
+
+// IsAvailable checks whether the service has been exported locally
+func (ii *InjvmInvoker) IsAvailable() bool {
+	if !ii.BaseInvoker.IsAvailable() {
+		return false
+	}
+	_, ok := ii.exporterMap.Load(ii.serviceKey)
+	return ok
+}
+
+// Invoke calls the locally exported invoker. With "injvm.copy=true", the arguments and the result are deep copied, so
+// neither side can mutate the state of the other one.
+func (ii *InjvmInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	var result protocol.RPCResult
+
+	if !ii.BaseInvoker.IsAvailable() {
+		logger.Warnf("this injvmInvoker is destroyed")
+		result.Err = protocol.ErrDestroyedInvoker
+		return &result
+	}
+
+	exporter, ok := ii.exporterMap.Load(ii.serviceKey)
+	if !ok {
+		result.Err = perrors.Errorf("no local exporter of service %s, make sure it has been exported by injvm protocol",
+			ii.serviceKey)
+		return &result
+	}
+
+	arguments := invocation.Arguments()
+	if ii.copy {
+		arguments = deepCopy(arguments).([]interface{})
+	}
+	attachments := make(map[string]interface{}, len(invocation.Attachments()))
+	for k, v := range invocation.Attachments() {
+		attachments[k] = v
+	}
+	providerInvocation := invocation_impl.NewRPCInvocationWithOptions(
+		invocation_impl.WithMethodName(invocation.MethodName()),
+		invocation_impl.WithParameterTypes(invocation.ParameterTypes()),
+		invocation_impl.WithArguments(arguments),
+		invocation_impl.WithAttachments(attachments),
+	)
+
+	res := exporter.(protocol.Exporter).GetInvoker().Invoke(ctx, providerInvocation)
+	result.Err = res.Error()
+	result.Attrs = res.Attachments()
+	if res.Result() == nil || result.Err != nil {
+		return &result
+	}
+
+	rest := res.Result()
+	if ii.copy {
+		rest = deepCopy(rest)
+	}
+	if reply := invocation.Reply(); reply != nil {
+		if err := hessian2.ReflectResponse(rest, reply); err != nil {
+			result.Err = perrors.WithMessagef(err, "failed to set the result of service %s", ii.serviceKey)
+			return &result
+		}
+		rest = reply
+	}
+	result.Rest = rest
+	return &result
+}
@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+)
+
+// INJVM is the protocol name of the in-process protocol
+const INJVM = constant.INJVM_PROTOCOL
+
+var (
+	injvmProtocol *InjvmProtocol
+	protocolOnce  sync.Once
+)
+
+func init() {
+	extension.SetProtocol(INJVM, GetProtocol)
+}
+
+// InjvmProtocol calls the service exported in the same process directly, without serialization and network.
+type InjvmProtocol struct {
+	protocol.BaseProtocol
+}
+
+// NewInjvmProtocol creates a new injvm protocol
+func NewInjvmProtocol() *InjvmProtocol {
+	return &InjvmProtocol{
+		BaseProtocol: protocol.NewBaseProtocol(),
+	}
+}
+
+// Export keeps the invoker, which has been wrapped by the service filters, by its service key.
+func (ip *InjvmProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	url := invoker.GetURL()
+	serviceKey := url.ServiceKey()
+	exporter := NewInjvmExporter(serviceKey, invoker, ip.ExporterMap())
+	ip.SetExporterMap(serviceKey, exporter)
+	logger.Infof("Export service: %s", url.String())
+	return exporter
+}
+
+// Refer returns an invoker which calls the locally exported invoker of the same service key.
+func (ip *InjvmProtocol) Refer(url *common.URL) protocol.Invoker {
+	invoker := NewInjvmInvoker(url, ip.ExporterMap())
+	ip.SetInvokers(invoker)
+	logger.Infof("Refer service: %s", url.String())
+	return invoker
+}
+
+// Destroy will destroy all invoker and exporter, so it only is called once.
+func (ip *InjvmProtocol) Destroy() {
+	logger.Infof("injvmProtocol destroy.")
+	ip.BaseProtocol.Destroy()
+}
+
+// IsInjvmRefer checks whether the service of @url has been exported locally.
+func (ip *InjvmProtocol) IsInjvmRefer(url *common.URL) bool {
+	_, ok := ip.ExporterMap().Load(url.ServiceKey())
+	return ok
+}
+
+// GetProtocol gets the single instance of injvm protocol
+func GetProtocol() protocol.Protocol {
+	protocolOnce.Do(func() {
+		injvmProtocol = NewInjvmProtocol()
+	})
+	return injvmProtocol
+}
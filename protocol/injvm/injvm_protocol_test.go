@@ -0,0 +1,150 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+)
+
+type User struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]string
+}
+
+type UserProvider struct {
+	user *User
+}
+
+func (u *UserProvider) GetUser(_ context.Context, name string) (*User, error) {
+	u.user.Name = name
+	return u.user, nil
+}
+
+func (u *UserProvider) UpdateUser(_ context.Context, user *User) (bool, error) {
+	user.Name = "updated"
+	return true, nil
+}
+
+func (u *UserProvider) Reference() string {
+	return "UserProvider"
+}
+
+func exportUserProvider(t *testing.T, proto *InjvmProtocol, provider *UserProvider) (protocol.Exporter, *common.URL) {
+	url, err := common.NewURL("injvm://127.0.0.1:0/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=group&version=1.0.0")
+	assert.Nil(t, err)
+	_, err = common.ServiceMap.Register(url.GetParam(constant.INTERFACE_KEY, ""), INJVM, "group", "1.0.0", provider)
+	assert.Nil(t, err)
+	return proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url)), url
+}
+
+func TestInjvmProtocolExportAndRefer(t *testing.T) {
+	proto := NewInjvmProtocol()
+	exporter, url := exportUserProvider(t, proto, &UserProvider{user: &User{}})
+	assert.True(t, proto.IsInjvmRefer(url))
+
+	invoker := proto.Refer(url)
+	assert.True(t, invoker.IsAvailable())
+	assert.Equal(t, 1, len(proto.Invokers()))
+
+	exporter.Unexport()
+	assert.False(t, proto.IsInjvmRefer(url))
+	assert.False(t, invoker.IsAvailable())
+	assert.Nil(t, common.ServiceMap.GetServiceByServiceKey(INJVM, url.ServiceKey()))
+
+	res := invoker.Invoke(context.Background(), invocation.NewRPCInvocation("GetUser", []interface{}{"tom"}, nil))
+	assert.NotNil(t, res.Error())
+
+	proto.Destroy()
+	assert.Equal(t, 0, len(proto.Invokers()))
+}
+
+func TestInjvmInvokerInvoke(t *testing.T) {
+	proto := NewInjvmProtocol()
+	provider := &UserProvider{user: &User{Tags: []string{"a"}}}
+	exporter, url := exportUserProvider(t, proto, provider)
+	defer exporter.Unexport()
+
+	// without copy, the consumer shares the provider state
+	invoker := proto.Refer(url)
+	reply := &User{}
+	inv := invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"tom"}), invocation.WithReply(reply))
+	res := invoker.Invoke(context.Background(), inv)
+	assert.Nil(t, res.Error())
+	assert.Equal(t, "tom", reply.Name)
+
+	user := &User{Name: "jerry"}
+	res = invoker.Invoke(context.Background(), invocation.NewRPCInvocation("UpdateUser", []interface{}{user}, nil))
+	assert.Nil(t, res.Error())
+	assert.Equal(t, "updated", user.Name)
+
+	// with copy, neither side can mutate the other one
+	copyURL := url.Clone()
+	copyURL.SetParam(constant.INJVM_COPY_KEY, "true")
+	invoker = proto.Refer(copyURL)
+	reply = &User{}
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUser"),
+		invocation.WithArguments([]interface{}{"tom"}), invocation.WithReply(reply))
+	res = invoker.Invoke(context.Background(), inv)
+	assert.Nil(t, res.Error())
+	assert.Equal(t, "tom", reply.Name)
+	reply.Tags[0] = "b"
+	assert.Equal(t, "a", provider.user.Tags[0])
+
+	user = &User{Name: "jerry"}
+	res = invoker.Invoke(context.Background(), invocation.NewRPCInvocation("UpdateUser", []interface{}{user}, nil))
+	assert.Nil(t, res.Error())
+	assert.Equal(t, "jerry", user.Name)
+}
+
+func TestDeepCopy(t *testing.T) {
+	type node struct {
+		Value    int
+		Next     *node
+		Children []*node
+		Any      interface{}
+		Array    [2]string
+		private  string
+	}
+	n := &node{Value: 1, Children: []*node{{Value: 2}}, Any: map[string]int{"a": 1}, Array: [2]string{"x", "y"}, private: "p"}
+	n.Next = n
+
+	c := deepCopy(n).(*node)
+	assert.Equal(t, 1, c.Value)
+	assert.Equal(t, "p", c.private)
+	assert.True(t, c.Next == c)
+	assert.False(t, c.Children[0] == n.Children[0])
+	c.Any.(map[string]int)["a"] = 2
+	assert.Equal(t, 1, n.Any.(map[string]int)["a"])
+	assert.Equal(t, n.Array, c.Array)
+	assert.Nil(t, deepCopy(nil))
+}
@@ -96,4 +96,8 @@ func (bi *BaseInvoker) Destroy() {
 	logger.Infof("Destroy invoker: %s", bi.GetURL())
 	bi.destroyed.Store(true)
 	bi.available.Store(false)
+	// the circuit breakers of the invoker gone away are never used again
+	if bi.url != nil {
+		RemoveCircuitBreakers(bi.url)
+	}
 }
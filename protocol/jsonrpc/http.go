@@ -21,6 +21,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -150,17 +151,27 @@ func (c *HTTPClient) Call(ctx context.Context, service *common.URL, req *Request
 		return perrors.WithStack(err)
 	}
 
-	rspBody, err := c.Do(service.Location, service.Path, httpHeader, reqBody)
-	if err != nil {
-		return perrors.WithStack(err)
+	rspBody, httpErr := c.Do(service.Location, service.Path, httpHeader, reqBody)
+	if httpErr != nil && len(rspBody) == 0 {
+		return perrors.WithStack(httpErr)
 	}
 
-	return perrors.WithStack(codec.Read(rspBody, rsp))
+	// the error object is replied with http status 500, and it's mapped to RPCError
+	err = codec.Read(rspBody, rsp)
+	var rspErr *Error
+	if errors.As(err, &rspErr) {
+		return rspErr.toRPCError()
+	}
+	if httpErr != nil {
+		return perrors.WithStack(httpErr)
+	}
+	return perrors.WithStack(err)
 }
 
 // Do
 // !!The high level of complexity and the likelihood that the fasthttp client has not been extensively used
 // in production means that you would need to expect a very large benefit to justify the adoption of fasthttp today.
+// The response body is returned along with the error if the http status isn't 200.
 func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte) ([]byte, error) {
 	u := url.URL{Host: strings.TrimSuffix(addr, ":"), Path: path}
 	httpReq, err := http.NewRequest("POST", u.String(), bytes.NewBuffer(body))
@@ -208,7 +219,7 @@ func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte)
 	}
 
 	if httpRsp.StatusCode != http.StatusOK {
-		return nil, perrors.New(fmt.Sprintf("http status:%q, error string:%q", httpRsp.Status, string(b)))
+		return b, perrors.New(fmt.Sprintf("http status:%q, error string:%q", httpRsp.Status, string(b)))
 	}
 
 	return b, nil
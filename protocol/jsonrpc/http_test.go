@@ -19,7 +19,7 @@ package jsonrpc
 
 import (
 	"context"
-	"strings"
+	"errors"
 	"testing"
 	"time"
 )
@@ -28,6 +28,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	perrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 )
 
 import (
@@ -107,8 +108,14 @@ func TestHTTPClientCall(t *testing.T) {
 	req = client.NewRequest(url, "GetUser1", []interface{}{})
 	reply = &User{}
 	err = client.Call(ctx, url, req, reply)
-	assert.True(t, strings.Contains(err.Error(), "500 Internal Server Error"))
-	assert.True(t, strings.Contains(err.Error(), "\\\"result\\\":{},\\\"error\\\":{\\\"code\\\":-32000,\\\"message\\\":\\\"error\\\"}"))
+	// the error object is mapped to RPCError
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(err, &rpcErr))
+	assert.Equal(t, codes.Unknown, rpcErr.Code)
+	assert.Equal(t, "error", rpcErr.Message)
+	var rspErr *Error
+	assert.True(t, errors.As(err, &rspErr))
+	assert.Equal(t, CodeServerError, rspErr.Code)
 
 	// call GetUser2
 	ctx = context.WithValue(context.Background(), constant.DUBBOGO_CTX_KEY, map[string]string{
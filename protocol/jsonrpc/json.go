@@ -28,6 +28,11 @@ import (
 
 import (
 	perrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 const (
@@ -53,6 +58,9 @@ const (
 	CodeMethodNotFound = -32601
 	CodeInvalidParams  = -32602
 	CodeInternalError  = -32603
+	// CodeServerError is the code of the error replied by service, which is reserved for implementation-defined
+	// server-errors by the spec.
+	CodeServerError = -32000
 )
 
 // Error response Error
@@ -75,6 +83,48 @@ func (e *Error) Error() string {
 	return string(buf)
 }
 
+// rpcErrorData is the data of error object, which carries the code and details of protocol.RPCError
+type rpcErrorData struct {
+	Status  codes.Code    `json:"status"`
+	Details []interface{} `json:"details,omitempty"`
+}
+
+// newErrorFromRPCError maps @rpcErr to the error object, the code of @rpcErr is kept in the data of error object
+func newErrorFromRPCError(rpcErr *protocol.RPCError) *Error {
+	code := CodeServerError
+	switch rpcErr.Code {
+	case codes.InvalidArgument:
+		code = CodeInvalidParams
+	case codes.Unimplemented:
+		code = CodeMethodNotFound
+	case codes.Internal:
+		code = CodeInternalError
+	}
+	return &Error{Code: code, Message: rpcErr.Message, Data: &rpcErrorData{Status: rpcErr.Code, Details: rpcErr.Details}}
+}
+
+// toRPCError maps the error object to protocol.RPCError, the code is taken from the data if it's replied by
+// dubbo-go provider, otherwise it's mapped from the code of error object
+func (e *Error) toRPCError() *protocol.RPCError {
+	code := codes.Unknown
+	switch e.Code {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		code = codes.InvalidArgument
+	case CodeMethodNotFound:
+		code = codes.Unimplemented
+	case CodeInternalError:
+		code = codes.Internal
+	}
+	var details []interface{}
+	if data, ok := e.Data.(map[string]interface{}); ok {
+		if status, ok := data["status"].(float64); ok {
+			code = codes.Code(status)
+		}
+		details, _ = data["details"].([]interface{})
+	}
+	return protocol.WrapRPCError(e, code, e.Message, details...)
+}
+
 //////////////////////////////////////////
 // json client codec
 //////////////////////////////////////////
@@ -189,7 +239,7 @@ func (c *jsonClientCodec) Read(streamBytes []byte, x interface{}) error {
 
 	// c.rsp.ID
 	if c.rsp.Error != nil {
-		return perrors.New(c.rsp.Error.Error())
+		return perrors.WithStack(c.rsp.Error)
 	}
 
 	if c.rsp.Result == nil {
@@ -19,11 +19,17 @@ package jsonrpc
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
 import (
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
 
 type TestData struct {
@@ -60,6 +66,31 @@ func TestJsonClientCodecRead(t *testing.T) {
 	assert.EqualError(t, err, "{\"code\":-32000,\"message\":\"error\"}")
 }
 
+func TestRPCErrorObject(t *testing.T) {
+	rpcErr := protocol.NewRPCError(codes.InvalidArgument, "invalid name", map[string]interface{}{"field": "name"})
+	rspErr := newErrorFromRPCError(rpcErr)
+	assert.Equal(t, CodeInvalidParams, rspErr.Code)
+
+	codec := newServerCodec()
+	id := json.RawMessage([]byte("1"))
+	codec.req = serverRequest{Version: "2.0", Method: "GetUser", ID: &id}
+	data, err := codec.Write(rspErr.Error(), nil)
+	assert.NoError(t, err)
+
+	clientCodec := newJsonClientCodec()
+	clientCodec.pending[1] = "GetUser"
+	err = clientCodec.Read(data, &TestData{})
+	assert.True(t, errors.As(err, &rspErr))
+	decoded := rspErr.toRPCError()
+	assert.Equal(t, codes.InvalidArgument, decoded.Code)
+	assert.Equal(t, "invalid name", decoded.Message)
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "name"}}, decoded.Details)
+
+	// the error object of other providers is mapped by its code
+	assert.Equal(t, codes.Unimplemented, (&Error{Code: CodeMethodNotFound, Message: "Method not found"}).toRPCError().Code)
+	assert.Equal(t, codes.Unknown, (&Error{Code: CodeServerError, Message: "error"}).toRPCError().Code)
+}
+
 func TestServerCodecWrite(t *testing.T) {
 	codec := newServerCodec()
 	a := json.RawMessage([]byte("1"))
@@ -50,11 +50,19 @@ func (ji *JsonrpcInvoker) Invoke(ctx context.Context, invocation protocol.Invoca
 	inv := invocation.(*invocation_impl.RPCInvocation)
 	url := ji.GetURL()
 	req := ji.client.NewRequest(url, inv.MethodName(), inv.Arguments())
-	ctxNew := context.WithValue(ctx, constant.DUBBOGO_CTX_KEY, map[string]string{
+	header := map[string]string{
 		"X-Proxy-ID": "dubbogo",
 		"X-Services": url.Path,
 		"X-Method":   inv.MethodName(),
-	})
+	}
+	// the trace context and the remaining timeout are carried by the http headers
+	protocol.AttachRemainingTimeout(ctx, inv, protocol.GetMethodTimeout(url, inv.MethodName()))
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := inv.AttachmentsByKey(k, ""); len(v) > 0 {
+			header[k] = v
+		}
+	}
+	ctxNew := context.WithValue(ctx, constant.DUBBOGO_CTX_KEY, header)
 	result.Err = ji.client.Call(ctxNew, url, req, inv.Reply())
 	if result.Err == nil {
 		result.Rest = inv.Reply()
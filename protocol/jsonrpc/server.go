@@ -21,10 +21,12 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/textproto"
 	"runtime"
 	"runtime/debug"
 	"sync"
@@ -40,6 +42,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
@@ -154,7 +157,7 @@ func (s *Server) handlePkg(conn net.Conn) {
 			return
 		}
 
-		ctx := context.Background()
+		ctx := context.WithValue(context.Background(), constant.RemoteAddrKey, conn.RemoteAddr().String())
 
 		spanCtx, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders,
 			opentracing.HTTPHeadersCarrier(r.Header))
@@ -345,12 +348,24 @@ func serveRequest(ctx context.Context, header map[string]string, body []byte, co
 	exporter, _ := jsonrpcProtocol.ExporterMap().Load(path)
 	invoker := exporter.(*JsonrpcExporter).GetInvoker()
 	if invoker != nil {
-		result := invoker.Invoke(ctx, invocation.NewRPCInvocation(methodName, args, map[string]interface{}{
+		attachments := map[string]interface{}{
 			constant.PATH_KEY:    path,
 			constant.VERSION_KEY: codec.req.Version,
-		}))
+		}
+		for _, k := range constant.PropagatedAttachmentKeys {
+			if v := header[textproto.CanonicalMIMEHeaderKey(k)]; len(v) > 0 {
+				attachments[k] = v
+			}
+		}
+		result := invoker.Invoke(ctx, invocation.NewRPCInvocation(methodName, args, attachments))
 		if err := result.Error(); err != nil {
-			rspStream, codecErr := codec.Write(err.Error(), invalidRequest)
+			errMsg := err.Error()
+			// the code and details of RPCError are kept in the error object
+			var rpcErr *protocol.RPCError
+			if errors.As(err, &rpcErr) {
+				errMsg = newErrorFromRPCError(rpcErr).Error()
+			}
+			rspStream, codecErr := codec.Write(errMsg, invalidRequest)
 			if codecErr != nil {
 				return perrors.WithStack(codecErr)
 			}
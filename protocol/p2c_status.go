@@ -0,0 +1,132 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+// p2cDecayTime is the time constant of the EWMA of P2CStatus,
+// the weight of a sample decays to 1/e after the time
+const p2cDecayTime = 10 * time.Second
+
+var p2cStatistics sync.Map // url key + method name -> P2CStatus
+
+// P2CStatus is the statistics of an invoker method for p2c load balance.
+// The latency and error rate are EWMA values which decay by time, so the recent requests are more important.
+type P2CStatus struct {
+	inflight   int32
+	serverLoad int64
+	lastPicked int64
+
+	mutex      sync.Mutex
+	latency    float64 // EWMA latency in milliseconds
+	errorRate  float64 // EWMA error rate between 0 and 1
+	lastUpdate time.Time
+}
+
+// GetP2CStatus gets the p2c status of @methodName of @url.
+func GetP2CStatus(url *common.URL, methodName string) *P2CStatus {
+	key := url.Key() + "#" + methodName
+	status, found := p2cStatistics.Load(key)
+	if !found {
+		status, _ = p2cStatistics.LoadOrStore(key, &P2CStatus{})
+	}
+	return status.(*P2CStatus)
+}
+
+// GetInflight gets the number of the requests which have not got response.
+func (s *P2CStatus) GetInflight() int32 {
+	return atomic.LoadInt32(&s.inflight)
+}
+
+// GetServerLoad gets the server load reported by provider at the last response.
+func (s *P2CStatus) GetServerLoad() int64 {
+	return atomic.LoadInt64(&s.serverLoad)
+}
+
+// GetLatency gets the EWMA latency in milliseconds.
+func (s *P2CStatus) GetLatency() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.latency
+}
+
+// GetErrorRate gets the EWMA error rate.
+func (s *P2CStatus) GetErrorRate() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.errorRate
+}
+
+// GetLastPicked gets the unix nano time when the invoker was picked last time.
+func (s *P2CStatus) GetLastPicked() int64 {
+	return atomic.LoadInt64(&s.lastPicked)
+}
+
+// Pick records the time when the invoker is picked.
+func (s *P2CStatus) Pick() {
+	atomic.StoreInt64(&s.lastPicked, time.Now().UnixNano())
+}
+
+// Begin records the beginning of a request.
+func (s *P2CStatus) Begin() {
+	atomic.AddInt32(&s.inflight, 1)
+}
+
+// End records the end of a request, @serverLoad is ignored if it is negative.
+func (s *P2CStatus) End(elapsed time.Duration, succeeded bool, serverLoad int64) {
+	atomic.AddInt32(&s.inflight, -1)
+	if serverLoad >= 0 {
+		atomic.StoreInt64(&s.serverLoad, serverLoad)
+	}
+
+	latency := float64(elapsed) / float64(time.Millisecond)
+	var failed float64
+	if !succeeded {
+		failed = 1
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now()
+	if s.lastUpdate.IsZero() {
+		s.latency = latency
+		s.errorRate = failed
+	} else {
+		w := math.Exp(-float64(now.Sub(s.lastUpdate)) / float64(p2cDecayTime))
+		s.latency = s.latency*w + latency*(1-w)
+		s.errorRate = s.errorRate*w + failed*(1-w)
+	}
+	s.lastUpdate = now
+}
+
+// CleanAllP2CStatus cleans all p2c status
+func CleanAllP2CStatus() {
+	p2cStatistics.Range(func(key, _ interface{}) bool {
+		p2cStatistics.Delete(key)
+		return true
+	})
+}
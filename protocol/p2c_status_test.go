@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+)
+
+func TestP2CStatus(t *testing.T) {
+	defer CleanAllP2CStatus()
+	url, _ := common.NewURL("dubbo://192.168.10.10:20000/com.ikurento.user.UserProvider")
+	status := GetP2CStatus(url, "test")
+	assert.Same(t, status, GetP2CStatus(url, "test"))
+	assert.NotSame(t, status, GetP2CStatus(url, "test1"))
+
+	status.Begin()
+	assert.Equal(t, int32(1), status.GetInflight())
+	status.End(100*time.Millisecond, true, 3)
+	assert.Equal(t, int32(0), status.GetInflight())
+	assert.Equal(t, int64(3), status.GetServerLoad())
+	// the first sample is the initial value
+	assert.Equal(t, float64(100), status.GetLatency())
+	assert.Equal(t, float64(0), status.GetErrorRate())
+
+	// the new samples affect the EWMA values by the elapsed time
+	time.Sleep(10 * time.Millisecond)
+	status.Begin()
+	status.End(200*time.Millisecond, false, -1)
+	assert.Equal(t, int64(3), status.GetServerLoad())
+	assert.True(t, status.GetLatency() > 100 && status.GetLatency() < 150)
+	assert.True(t, status.GetErrorRate() > 0 && status.GetErrorRate() < 0.5)
+
+	assert.Equal(t, int64(0), status.GetLastPicked())
+	status.Pick()
+	assert.True(t, status.GetLastPicked() > 0)
+
+	CleanAllP2CStatus()
+	assert.NotSame(t, status, GetP2CStatus(url, "test"))
+}
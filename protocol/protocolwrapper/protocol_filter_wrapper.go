@@ -116,10 +116,32 @@ func (fi *FilterInvoker) IsAvailable() bool {
 
 // Invoke is used to call service method by invocation
 func (fi *FilterInvoker) Invoke(ctx context.Context, invocation protocol.Invocation) protocol.Result {
+	sf, ok := fi.filter.(filter.StreamFilter)
+	// the stream is opened before the invocation arrives at provider side
+	if ok && fi.observeStream(ctx, sf, invocation) {
+		ok = false
+	}
 	result := fi.filter.Invoke(ctx, fi.next, invocation)
+	// the stream is opened by the protocol at consumer side
+	if ok && result.Error() == nil {
+		fi.observeStream(ctx, sf, invocation)
+	}
 	return fi.filter.OnResponse(ctx, result, fi.invoker, invocation)
 }
 
+// observeStream notifies @sf of the stream opened by @invocation, it returns false if @invocation has no stream.
+func (fi *FilterInvoker) observeStream(ctx context.Context, sf filter.StreamFilter, invocation protocol.Invocation) bool {
+	state := protocol.GetStreamState(invocation)
+	if state == nil {
+		return false
+	}
+	sf.OnStreamOpen(ctx, fi.invoker, invocation)
+	state.OnClose(func(err error) {
+		sf.OnStreamClose(ctx, fi.invoker, invocation, err)
+	})
+	return true
+}
+
 // Destroy will destroy invoker
 func (fi *FilterInvoker) Destroy() {
 	fi.invoker.Destroy()
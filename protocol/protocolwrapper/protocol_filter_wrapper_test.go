@@ -19,6 +19,7 @@ package protocolwrapper
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 	"testing"
 )
@@ -34,6 +35,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/filter"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 )
 
 func TestProtocolFilterWrapperExport(t *testing.T) {
@@ -60,9 +62,68 @@ func TestProtocolFilterWrapperRefer(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestFilterInvokerStream(t *testing.T) {
+	u := common.NewURLWithOptions(
+		common.WithParams(url.Values{}),
+		common.WithParamsValue(constant.REFERENCE_FILTER_KEY, "stream"))
+
+	// consumer side, the stream is opened by the protocol
+	opener := &streamOpenInvoker{BaseInvoker: *protocol.NewBaseInvoker(u)}
+	invoker := BuildInvokerChain(opener, constant.REFERENCE_FILTER_KEY)
+	inv := invocation.NewRPCInvocation("SayHelloStream", nil, nil)
+	res := invoker.Invoke(context.Background(), inv)
+	assert.Nil(t, res.Error())
+	sf := inv.AttributeByKey("events", nil).(*[]string)
+	assert.Equal(t, []string{"open"}, *sf)
+	protocol.GetStreamState(inv).Close(nil)
+	assert.Equal(t, []string{"open", "close: <nil>"}, *sf)
+
+	// provider side, the stream is opened before invoking
+	inv = invocation.NewRPCInvocation("SayHelloStream", nil, nil)
+	state := protocol.NewStreamState()
+	inv.SetAttribute(constant.STREAM_STATE_KEY, state)
+	invoker = BuildInvokerChain(protocol.NewBaseInvoker(u), constant.REFERENCE_FILTER_KEY)
+	invoker.Invoke(context.Background(), inv)
+	sf = inv.AttributeByKey("events", nil).(*[]string)
+	assert.Equal(t, []string{"open"}, *sf)
+	state.Close(protocol.ErrClientClosed)
+	assert.Equal(t, []string{"open", "close: " + protocol.ErrClientClosed.Error()}, *sf)
+
+	// no events for unary calls
+	inv = invocation.NewRPCInvocation("SayHello", nil, nil)
+	invoker.Invoke(context.Background(), inv)
+	assert.Nil(t, inv.AttributeByKey("events", nil))
+}
+
+type streamOpenInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (si *streamOpenInvoker) Invoke(ctx context.Context, inv protocol.Invocation) protocol.Result {
+	inv.(*invocation.RPCInvocation).SetAttribute(constant.STREAM_STATE_KEY, protocol.NewStreamState())
+	return &protocol.RPCResult{}
+}
+
+type streamFilterForTest struct {
+	EchoFilterForTest
+}
+
+func (sf *streamFilterForTest) OnStreamOpen(ctx context.Context, invoker protocol.Invoker, inv protocol.Invocation) {
+	events := &[]string{"open"}
+	inv.(*invocation.RPCInvocation).SetAttribute("events", events)
+}
+
+func (sf *streamFilterForTest) OnStreamClose(ctx context.Context, invoker protocol.Invoker, inv protocol.Invocation, err error) {
+	events := inv.AttributeByKey("events", nil).(*[]string)
+	*events = append(*events, fmt.Sprintf("close: %v", err))
+}
+
 // the same as echo filter, for test
 func init() {
 	extension.SetFilter("echo", GetFilter)
+	extension.SetFilter("stream", func() filter.Filter {
+		return &streamFilterForTest{}
+	})
 }
 
 type EchoFilterForTest struct{}
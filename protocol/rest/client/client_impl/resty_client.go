@@ -33,6 +33,7 @@ import (
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
 )
 
@@ -81,7 +82,9 @@ func (rc *RestyClient) Do(restRequest *client.RestClientRequest, res interface{}
 		return perrors.WithStack(err)
 	}
 	if resp.IsError() {
-		return perrors.New(resp.String())
+		// the http status is mapped to the code of RPCError
+		return protocol.WrapRPCError(perrors.New(resp.String()), protocol.CodeFromHTTPStatus(resp.StatusCode()),
+			resp.String())
 	}
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	invocation_impl "dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
@@ -79,6 +80,13 @@ func (ri *RestInvoker) Invoke(ctx context.Context, invocation protocol.Invocatio
 		result.Err = err
 		return &result
 	}
+	// the trace context and the remaining timeout are carried by the http headers
+	protocol.AttachRemainingTimeout(ctx, inv, protocol.GetMethodTimeout(ri.GetURL(), inv.MethodName()))
+	for _, k := range constant.PropagatedAttachmentKeys {
+		if v := inv.AttachmentsByKey(k, ""); len(v) > 0 {
+			header.Set(k, v)
+		}
+	}
 	if len(inv.Arguments()) > methodConfig.Body && methodConfig.Body >= 0 {
 		body = inv.Arguments()[methodConfig.Body]
 	}
@@ -19,6 +19,7 @@ package rest
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -26,12 +27,14 @@ import (
 import (
 	"github.com/emicklei/go-restful/v3"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client"
 	"dubbo.apache.org/dubbo-go/v3/protocol/rest/client/client_impl"
@@ -137,6 +140,16 @@ func TestRestInvokerInvoke(t *testing.T) {
 		Produces:      "*/*",
 		Consumes:      "*/*",
 		MethodType:    "GET",
+		Body:          -1,
+	}
+	methodConfigMap["GetUserSix"] = &rest_config.RestMethodConfig{
+		InterfaceName: "",
+		MethodName:    "GetUserSix",
+		Path:          "/GetUserSix",
+		Produces:      "*/*",
+		Consumes:      "*/*",
+		MethodType:    "GET",
+		Body:          -1,
 	}
 	methodConfigMap["GetUser"] = &rest_config.RestMethodConfig{
 		InterfaceName:  "",
@@ -210,6 +223,17 @@ func TestRestInvokerInvoke(t *testing.T) {
 	assert.Error(t, res.Error(), "test error")
 
 	assert.Equal(t, filterNum, 12)
+
+	// the http status is mapped to the code of RPCError
+	var rpcErr *protocol.RPCError
+	assert.True(t, errors.As(res.Error(), &rpcErr))
+	assert.Equal(t, codes.Unknown, rpcErr.Code)
+	assert.Equal(t, "test error", rpcErr.Message)
+	inv = invocation.NewRPCInvocationWithOptions(invocation.WithMethodName("GetUserSix"), invocation.WithReply(user))
+	res = invoker.Invoke(context.Background(), inv)
+	assert.True(t, errors.As(res.Error(), &rpcErr))
+	assert.Equal(t, codes.NotFound, rpcErr.Code)
+	assert.Equal(t, "no such user", rpcErr.Message)
 	err = common.ServiceMap.UnRegister(url.Service(), url.Protocol, url.ServiceKey())
 	assert.NoError(t, err)
 }
@@ -28,6 +28,7 @@ import (
 
 import (
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
 )
 
 import (
@@ -35,6 +36,7 @@ import (
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
 	_ "dubbo.apache.org/dubbo-go/v3/common/proxy/proxy_factory"
 	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
 	rest_config "dubbo.apache.org/dubbo-go/v3/protocol/rest/config"
 )
 
@@ -167,6 +169,10 @@ func (p *UserProvider) GetUserFive(ctx context.Context, user []interface{}) (*Us
 	return nil, errors.New("test error")
 }
 
+func (p *UserProvider) GetUserSix(ctx context.Context, user []interface{}) (*User, error) {
+	return nil, protocol.NewRPCError(codes.NotFound, "no such user")
+}
+
 type User struct {
 	ID   int
 	Time *time.Time
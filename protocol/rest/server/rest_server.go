@@ -27,10 +27,12 @@ import (
 
 import (
 	perrors "github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
@@ -105,14 +107,28 @@ func GetRouteFunc(invoker protocol.Invoker, methodConfig *rest_config.RestMethod
 		}
 		if err != nil {
 			logger.Errorf("[Go Restful] parsing http parameters error:%v", err)
-			err = resp.WriteError(http.StatusInternalServerError, errors.New(parseParameterErrorStr))
+			err = resp.WriteError(protocol.HTTPStatusFromCode(codes.InvalidArgument), errors.New(parseParameterErrorStr))
 			if err != nil {
 				logger.Errorf("[Go Restful] WriteErrorString error:%v", err)
 			}
+			return
+		}
+		attachments := make(map[string]interface{})
+		for _, k := range constant.PropagatedAttachmentKeys {
+			if v := req.HeaderParameter(k); len(v) > 0 {
+				attachments[k] = v
+			}
 		}
-		result := invoker.Invoke(context.Background(), invocation.NewRPCInvocation(methodConfig.MethodName, args, make(map[string]interface{})))
+		ctx := context.WithValue(context.Background(), constant.RemoteAddrKey, req.RawRequest().RemoteAddr)
+		result := invoker.Invoke(ctx, invocation.NewRPCInvocation(methodConfig.MethodName, args, attachments))
 		if result.Error() != nil {
-			err = resp.WriteError(http.StatusInternalServerError, result.Error())
+			// the code of RPCError is mapped to the http status, and its message is written as the body
+			httpStatus, rspErr := http.StatusInternalServerError, result.Error()
+			var rpcErr *protocol.RPCError
+			if errors.As(rspErr, &rpcErr) {
+				httpStatus, rspErr = protocol.HTTPStatusFromCode(rpcErr.Code), errors.New(rpcErr.Message)
+			}
+			err = resp.WriteError(httpStatus, rspErr)
 			if err != nil {
 				logger.Errorf("[Go Restful] WriteError error:%v", err)
 			}
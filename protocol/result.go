@@ -81,6 +81,9 @@ func (r *RPCResult) Attachments() map[string]interface{} {
 
 // AddAttachment adds the specified map to existing attachments in this instance.
 func (r *RPCResult) AddAttachment(key string, value interface{}) {
+	if r.Attrs == nil {
+		r.Attrs = make(map[string]interface{})
+	}
 	r.Attrs[key] = value
 }
 
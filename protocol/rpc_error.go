@@ -0,0 +1,199 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCError is an error of invocation with a status code, a message and typed details. Every protocol maps its own
+// errors from and to RPCError, so that the consumer could check the error by errors.As regardless of the transport.
+type RPCError struct {
+	Code    codes.Code
+	Message string
+	Details []interface{}
+	cause   error
+}
+
+// NewRPCError creates a RPCError with @code, @message and @details
+func NewRPCError(code codes.Code, message string, details ...interface{}) *RPCError {
+	return &RPCError{Code: code, Message: message, Details: details}
+}
+
+// WrapRPCError creates a RPCError with @code and @message, which is mapped from @cause by protocol
+func WrapRPCError(cause error, code codes.Code, message string, details ...interface{}) *RPCError {
+	return &RPCError{Code: code, Message: message, Details: details, cause: cause}
+}
+
+// Error returns the message of the original error if RPCError is mapped from it, so that the message of protocol
+// exception is kept, otherwise the description in the same format as the status error of grpc
+func (e *RPCError) Error() string {
+	if e.cause != nil {
+		return e.cause.Error()
+	}
+	return fmt.Sprintf("rpc error: code = %s desc = %s", e.Code, e.Message)
+}
+
+// Unwrap returns the original error which is mapped to RPCError, it's nil if RPCError is created by provider
+func (e *RPCError) Unwrap() error {
+	return e.cause
+}
+
+// Cause returns the original error which is mapped to RPCError. The RPCError created by provider has no original
+// error, the status error of grpc with its code and message is returned, so that perrors.Cause never returns nil.
+func (e *RPCError) Cause() error {
+	if e.cause != nil {
+		return e.cause
+	}
+	if e.Code == codes.OK {
+		return errors.New(e.Message)
+	}
+	return status.Error(e.Code, e.Message)
+}
+
+// GRPCStatus converts RPCError to the status of grpc, only the details which are proto.Message are kept
+func (e *RPCError) GRPCStatus() *status.Status {
+	s := status.New(e.Code, e.Message)
+	details := make([]proto.Message, 0, len(e.Details))
+	for _, detail := range e.Details {
+		if m, ok := detail.(proto.Message); ok {
+			details = append(details, m)
+		}
+	}
+	if len(details) > 0 {
+		if ds, err := s.WithDetails(details...); err == nil {
+			s = ds
+		}
+	}
+	return s
+}
+
+// FromGRPCStatus converts the status of grpc to RPCError, it's nil if @s is ok
+func FromGRPCStatus(s *status.Status, cause error) *RPCError {
+	if s == nil || s.Code() == codes.OK {
+		return nil
+	}
+	return WrapRPCError(cause, s.Code(), s.Message(), s.Details()...)
+}
+
+// ToRPCError returns the RPCError in the chain of @err, or maps @err to RPCError if there isn't one.
+// The status error of grpc keeps its code, and the unrecognized error is mapped to codes.Unknown.
+func ToRPCError(err error) *RPCError {
+	if err == nil {
+		return nil
+	}
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	var statusErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &statusErr) {
+		if rpcErr = FromGRPCStatus(statusErr.GRPCStatus(), err); rpcErr != nil {
+			return rpcErr
+		}
+	}
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, ErrClientClosed), errors.Is(err, ErrDestroyedInvoker), errors.Is(err, ErrCircuitBreakerOpen):
+		code = codes.Unavailable
+	}
+	return WrapRPCError(err, code, err.Error())
+}
+
+// ErrorCode returns the code of @err, it's codes.OK if @err is nil
+func ErrorCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	return ToRPCError(err).Code
+}
+
+// HTTPStatusFromCode returns the http status which @code is mapped to
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		// the status of client closed request, which isn't defined by net/http
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeFromHTTPStatus returns the code which @httpStatus is mapped to, the codes sharing a http status are
+// mapped to the most general one of them
+func CodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK:
+		return codes.OK
+	case 499:
+		return codes.Canceled
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCErrorGRPCStatus(t *testing.T) {
+	detail := &wrappers.StringValue{Value: "user id"}
+	err := NewRPCError(codes.NotFound, "no such user", detail, "not a proto message")
+	assert.EqualError(t, err, "rpc error: code = NotFound desc = no such user")
+
+	s := status.Convert(err)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "no such user", s.Message())
+	assert.Len(t, s.Details(), 1)
+
+	statusErr := s.Err()
+	rpcErr := ToRPCError(statusErr)
+	assert.Equal(t, codes.NotFound, rpcErr.Code)
+	assert.Equal(t, "no such user", rpcErr.Message)
+	assert.Len(t, rpcErr.Details, 1)
+	assert.True(t, proto.Equal(detail, rpcErr.Details[0].(proto.Message)))
+	assert.Same(t, statusErr, errors.Unwrap(rpcErr))
+
+	assert.Nil(t, FromGRPCStatus(status.New(codes.OK, ""), nil))
+}
+
+func TestToRPCError(t *testing.T) {
+	assert.Nil(t, ToRPCError(nil))
+	assert.Equal(t, codes.OK, ErrorCode(nil))
+
+	err := NewRPCError(codes.InvalidArgument, "bad name")
+	wrapped := perrors.Wrap(err, "invoke")
+	assert.Same(t, err, ToRPCError(wrapped))
+	var rpcErr *RPCError
+	assert.True(t, errors.As(wrapped, &rpcErr))
+	assert.Equal(t, codes.InvalidArgument, ErrorCode(wrapped))
+
+	assert.Equal(t, codes.DeadlineExceeded, ErrorCode(perrors.WithStack(context.DeadlineExceeded)))
+	assert.Equal(t, codes.Canceled, ErrorCode(context.Canceled))
+	assert.Equal(t, codes.Unavailable, ErrorCode(ErrClientClosed))
+
+	plain := errors.New("plain")
+	rpcErr = ToRPCError(plain)
+	assert.Equal(t, codes.Unknown, rpcErr.Code)
+	assert.Equal(t, "plain", rpcErr.Message)
+	assert.True(t, errors.Is(rpcErr, plain))
+}
+
+func TestRPCErrorCause(t *testing.T) {
+	// the message of the original error is kept
+	exception := errors.New("java exception:user not found")
+	err := WrapRPCError(exception, codes.Unknown, exception.Error())
+	assert.EqualError(t, err, "java exception:user not found")
+	assert.Same(t, exception, err.Cause())
+	assert.Same(t, exception, perrors.Cause(perrors.WithStack(err)))
+
+	// the cause of RPCError created by provider is its status
+	err = NewRPCError(codes.NotFound, "no such user")
+	cause := perrors.Cause(err)
+	assert.NotNil(t, cause)
+	assert.Equal(t, codes.NotFound, status.Code(cause))
+	assert.EqualError(t, NewRPCError(codes.OK, "ok").Cause(), "ok")
+}
+
+func TestHTTPStatusMapping(t *testing.T) {
+	for _, code := range []codes.Code{codes.OK, codes.Canceled, codes.InvalidArgument, codes.DeadlineExceeded,
+		codes.NotFound, codes.AlreadyExists, codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.Unimplemented, codes.Unavailable, codes.Unknown} {
+		assert.Equal(t, code, CodeFromHTTPStatus(HTTPStatusFromCode(code)))
+	}
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatusFromCode(codes.Internal))
+	assert.Equal(t, http.StatusBadRequest, HTTPStatusFromCode(codes.FailedPrecondition))
+	assert.Equal(t, codes.Unknown, CodeFromHTTPStatus(http.StatusTeapot))
+}
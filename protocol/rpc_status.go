@@ -74,6 +74,16 @@ func (rpc *RPCStatus) GetTotal() int32 {
 	return atomic.LoadInt32(&rpc.total)
 }
 
+// GetSucceeded gets succeeded.
+func (rpc *RPCStatus) GetSucceeded() int32 {
+	return rpc.GetTotal() - rpc.GetFailed()
+}
+
+// GetSucceededElapsed gets succeeded elapsed.
+func (rpc *RPCStatus) GetSucceededElapsed() int64 {
+	return rpc.GetTotalElapsed() - rpc.GetFailedElapsed()
+}
+
 // GetTotalElapsed gets total elapsed.
 func (rpc *RPCStatus) GetTotalElapsed() int64 {
 	return atomic.LoadInt64(&rpc.totalElapsed)
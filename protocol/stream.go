@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// StreamState tracks the lifecycle of a streaming call. It is carried by the attributes of the invocation which
+// opens the stream, so that filters can observe when the stream is closed.
+type StreamState struct {
+	lock   sync.Mutex
+	closed bool
+	err    error
+	hooks  []func(error)
+}
+
+// NewStreamState creates an open StreamState.
+func NewStreamState() *StreamState {
+	return &StreamState{}
+}
+
+// GetStreamState returns the StreamState of @invocation, it's nil if @invocation doesn't open a stream.
+func GetStreamState(invocation Invocation) *StreamState {
+	state, _ := invocation.AttributeByKey(constant.STREAM_STATE_KEY, nil).(*StreamState)
+	return state
+}
+
+// OnClose registers @hook which is called with the error that closes the stream. The hooks are called in the
+// reverse order of registration like deferred calls, and @hook is called at once if the stream is already closed.
+func (s *StreamState) OnClose(hook func(err error)) {
+	s.lock.Lock()
+	if !s.closed {
+		s.hooks = append(s.hooks, hook)
+		s.lock.Unlock()
+		return
+	}
+	err := s.err
+	s.lock.Unlock()
+	hook(err)
+}
+
+// Close closes the stream with @err, which is nil if the stream finishes normally. Only the first call takes effect,
+// and it returns whether the stream is closed by this call.
+func (s *StreamState) Close(err error) bool {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return false
+	}
+	s.closed = true
+	s.err = err
+	hooks := s.hooks
+	s.hooks = nil
+	s.lock.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i](err)
+	}
+	return true
+}
+
+// Closed returns whether the stream is closed.
+func (s *StreamState) Closed() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.closed
+}
+
+// Err returns the error which closes the stream.
+func (s *StreamState) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.err
+}
@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"errors"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamState(t *testing.T) {
+	state := NewStreamState()
+	var order []int
+	state.OnClose(func(err error) {
+		order = append(order, 1)
+	})
+	state.OnClose(func(err error) {
+		order = append(order, 2)
+	})
+	assert.False(t, state.Closed())
+
+	closeErr := errors.New("stream is reset")
+	assert.True(t, state.Close(closeErr))
+	assert.False(t, state.Close(nil))
+	assert.True(t, state.Closed())
+	assert.Equal(t, closeErr, state.Err())
+	assert.Equal(t, []int{2, 1}, order)
+
+	// hooks registered after close are called at once
+	var lateErr error
+	state.OnClose(func(err error) {
+		lateErr = err
+	})
+	assert.Equal(t, closeErr, lateErr)
+}
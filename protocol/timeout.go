@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+)
+
+// GetMethodTimeout returns the timeout of @methodName configured in @url, it's 0 if the timeout is not configured
+func GetMethodTimeout(url *common.URL, methodName string) time.Duration {
+	timeout := url.GetMethodParam(methodName, constant.TIMEOUT_KEY, url.GetParam(constant.TIMEOUT_KEY, ""))
+	if len(timeout) == 0 {
+		return 0
+	}
+	if t, err := time.ParseDuration(timeout); err == nil {
+		return t
+	}
+	// the timeout of java provider is in milliseconds
+	if ms, err := strconv.ParseInt(timeout, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return 0
+}
+
+// AttachRemainingTimeout puts the remaining timeout of @invocation into the attachments, it's the smaller one of
+// @timeout and the time until the deadline of @ctx, and @timeout is ignored if it's not positive.
+func AttachRemainingTimeout(ctx context.Context, invocation Invocation, timeout time.Duration) {
+	remaining := timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		if untilDeadline := time.Until(deadline); remaining <= 0 || untilDeadline < remaining {
+			remaining = untilDeadline
+			if remaining < 0 {
+				remaining = 0
+			}
+		}
+	} else if remaining <= 0 {
+		return
+	}
+	// round up, or the remaining timeout under 1ms would be taken as expired
+	ms := (remaining + time.Millisecond - 1) / time.Millisecond
+	invocation.SetAttachments(constant.TIMEOUT_ATTACHMENT_KEY, strconv.FormatInt(int64(ms), 10))
+}
+
+// GetRemainingTimeout returns the remaining timeout carried by the attachments of @invocation
+func GetRemainingTimeout(invocation Invocation) (time.Duration, bool) {
+	var ms int64
+	switch v := invocation.Attachment(constant.TIMEOUT_ATTACHMENT_KEY).(type) {
+	case string:
+		var err error
+		if ms, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return 0, false
+		}
+	case []string:
+		if len(v) == 0 {
+			return 0, false
+		}
+		var err error
+		if ms, err = strconv.ParseInt(v[0], 10, 64); err != nil {
+			return 0, false
+		}
+	case int64:
+		ms = v
+	case int32:
+		ms = int64(v)
+	case int:
+		ms = int64(v)
+	default:
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
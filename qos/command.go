@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qos
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	registryProtocol "dubbo.apache.org/dubbo-go/v3/registry/protocol"
+)
+
+// command is the QoS command which could be executed by telnet and http
+type command struct {
+	name        string
+	description string
+	usage       string
+	// execute returns the output of the command and whether the command is executed successfully
+	execute func(args []string) (string, bool)
+}
+
+// providerRegistrations is implemented by the registry protocol
+type providerRegistrations interface {
+	GetProviderRegistrations() []*registryProtocol.ProviderRegistration
+}
+
+var commands = make(map[string]*command)
+
+func init() {
+	for _, cmd := range []*command{
+		{name: "help", description: "Show the help of commands", usage: "help [command]", execute: help},
+		{name: "ls", description: "List the providers and consumers", usage: "ls", execute: ls},
+		{name: "online", description: "Register the services to the registries again",
+			usage: "online [service pattern, e.g. com.foo.*, all services by default]", execute: online},
+		{name: "offline", description: "Unregister the services from the registries without unexporting them",
+			usage: "offline [service pattern, e.g. com.foo.*, all services by default]", execute: offline},
+		{name: "ready", description: "Check whether the application is ready to serve", usage: "ready", execute: ready},
+		{name: "startup", description: "Check whether the application has been started", usage: "startup", execute: startup},
+	} {
+		commands[cmd.name] = cmd
+	}
+}
+
+// execute executes the command @name with @args
+func execute(name string, args []string) (string, bool) {
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Sprintf("Unsupported command: %s, use help to list the commands.", name), false
+	}
+	return cmd.execute(args)
+}
+
+func help(args []string) (string, bool) {
+	if len(args) > 0 {
+		cmd, ok := commands[args[0]]
+		if !ok {
+			return fmt.Sprintf("no such command %s", args[0]), false
+		}
+		return fmt.Sprintf("Command: %s\nUsage: %s\n%s", cmd.name, cmd.usage, cmd.description), true
+	}
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, commands[name].description)
+	}
+	w.Flush()
+	return strings.TrimRight(buf.String(), "\n"), true
+}
+
+func ls(_ []string) (string, bool) {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "As Provider side:")
+	fmt.Fprintln(w, "Provider Service Name\tPUB")
+	registrations := getProviderRegistrations()
+	for _, svc := range getServices() {
+		pub := "N"
+		key := serviceKey(svc)
+		for _, registration := range registrations {
+			if registration.GetURL().ServiceKey() == key && registration.IsOnline() {
+				pub = "Y"
+				break
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\n", key, pub)
+	}
+
+	fmt.Fprintln(w, "As Consumer side:")
+	fmt.Fprintln(w, "Consumer Service Name\tAVAILABLE")
+	references := config.GetConsumerConfig().References
+	ids := make([]string, 0, len(references))
+	for id := range references {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		ref := references[id]
+		available := "N"
+		if invoker := ref.GetInvoker(); invoker != nil && invoker.IsAvailable() {
+			available = "Y"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", common.ServiceKey(ref.InterfaceName, ref.Group, ref.Version), available)
+	}
+	w.Flush()
+	return strings.TrimRight(buf.String(), "\n"), true
+}
+
+func online(args []string) (string, bool) {
+	return changeServiceStatus(args, true)
+}
+
+func offline(args []string) (string, bool) {
+	return changeServiceStatus(args, false)
+}
+
+// changeServiceStatus registers or unregisters the services matched by the pattern in @args
+func changeServiceStatus(args []string, isOnline bool) (string, bool) {
+	pattern := "*"
+	if len(args) > 0 && len(args[0]) > 0 {
+		pattern = args[0]
+	}
+	matcher, err := compilePattern(pattern)
+	if err != nil {
+		return fmt.Sprintf("invalid service pattern %s: %v", pattern, err), false
+	}
+
+	registrations := getProviderRegistrations()
+	var (
+		matched bool
+		errs    []string
+	)
+	for _, svc := range getServices() {
+		key := serviceKey(svc)
+		if !matcher.MatchString(svc.InterfaceName) && !matcher.MatchString(key) {
+			continue
+		}
+		matched = true
+		for _, registration := range registrations {
+			if registration.GetURL().ServiceKey() != key {
+				continue
+			}
+			if isOnline {
+				err = registration.Online()
+			} else {
+				err = registration.Offline()
+			}
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", registration.GetURL().Key(), err))
+			}
+		}
+	}
+	if !matched {
+		return fmt.Sprintf("no service matches %s", pattern), false
+	}
+	if len(errs) > 0 {
+		return strings.Join(errs, "\n"), false
+	}
+	return "OK", true
+}
+
+// ready checks whether the application has been started and all the providers are online
+func ready(args []string) (string, bool) {
+	if _, ok := startup(args); !ok {
+		return "false", false
+	}
+	for _, registration := range getProviderRegistrations() {
+		if !registration.IsOnline() {
+			return "false", false
+		}
+	}
+	return "true", true
+}
+
+// startup checks whether all the services have been exported and all the references have been referred
+func startup(_ []string) (string, bool) {
+	for _, svc := range getServices() {
+		if !svc.IsExport() {
+			return "false", false
+		}
+	}
+	for _, ref := range config.GetConsumerConfig().References {
+		if ref.GetInvoker() == nil {
+			return "false", false
+		}
+	}
+	return "true", true
+}
+
+// getServices returns the provider services sorted by their ids
+func getServices() []*config.ServiceConfig {
+	services := config.GetProviderConfig().Services
+	ids := make([]string, 0, len(services))
+	for id := range services {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	result := make([]*config.ServiceConfig, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, services[id])
+	}
+	return result
+}
+
+func getProviderRegistrations() []*registryProtocol.ProviderRegistration {
+	if p, ok := extension.GetProtocol(constant.REGISTRY_KEY).(providerRegistrations); ok {
+		return p.GetProviderRegistrations()
+	}
+	return nil
+}
+
+func serviceKey(svc *config.ServiceConfig) string {
+	return common.ServiceKey(svc.InterfaceName, svc.Group, svc.Version)
+}
+
+// compilePattern compiles the service pattern which only supports the * wildcard
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
@@ -0,0 +1,274 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qos
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+)
+
+const (
+	// DefaultPort is the default port of QoS server
+	DefaultPort = 22222
+
+	prompt          = "dubbo>"
+	welcome         = "Welcome to dubbo-go QoS, use help to list the commands."
+	foreignIPDenied = "Foreign Ip Not Permitted."
+	// telnet clients wait for the prompt before sending anything, http clients send the request at once
+	detectTimeout = 500 * time.Millisecond
+)
+
+var httpMethods = []string{"GET ", "POST", "PUT ", "DELE", "HEAD", "OPTI", "PATC"}
+
+// Option is used to configure the QoS server
+type Option func(*Server)
+
+// WithPort sets the port QoS server listens on, 0 means a random port
+func WithPort(port int) Option {
+	return func(s *Server) {
+		s.port = port
+	}
+}
+
+// WithAcceptForeignIP sets whether the connections from other hosts are accepted
+func WithAcceptForeignIP(accept bool) Option {
+	return func(s *Server) {
+		s.acceptForeignIP = accept
+	}
+}
+
+// Server serves the QoS commands by telnet and http on the same port.
+type Server struct {
+	port            int
+	acceptForeignIP bool
+
+	mutex      sync.Mutex
+	listener   net.Listener
+	httpConns  *connListener
+	httpServer *http.Server
+}
+
+// NewServer returns a QoS server which listens on DefaultPort and only accepts local connections by default
+func NewServer(opts ...Option) *Server {
+	s := &Server{port: DefaultPort}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Start listens on the port and serves the commands in background
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.listener != nil {
+		return perrors.New("qos server has been started")
+	}
+
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(s.port))
+	if err != nil {
+		return perrors.WithMessagef(err, "qos server failed to listen on port %d", s.port)
+	}
+	s.listener = listener
+	s.httpConns = newConnListener(listener.Addr())
+	s.httpServer = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+	go func() {
+		if err := s.httpServer.Serve(s.httpConns); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("qos http server stopped with error: %v", err)
+		}
+	}()
+	go s.serve(listener)
+	logger.Infof("qos server is listening on %s", listener.Addr())
+	return nil
+}
+
+// Addr returns the address QoS server listens on, it is nil before starting
+func (s *Server) Addr() net.Addr {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop stops listening and closes the http connections
+func (s *Server) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.listener == nil {
+		return
+	}
+	if err := s.listener.Close(); err != nil {
+		logger.Warnf("qos server failed to close the listener: %v", err)
+	}
+	if err := s.httpServer.Close(); err != nil {
+		logger.Warnf("qos server failed to close the http server: %v", err)
+	}
+	s.listener = nil
+}
+
+func (s *Server) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	if !s.acceptForeignIP && !isLocalAddr(conn.RemoteAddr()) {
+		_, _ = conn.Write([]byte(foreignIPDenied + "\r\n"))
+		_ = conn.Close()
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(detectTimeout))
+	head, _ := reader.Peek(4)
+	_ = conn.SetReadDeadline(time.Time{})
+	for _, method := range httpMethods {
+		if string(head) == method {
+			s.httpConns.put(&bufferedConn{Conn: conn, reader: reader})
+			return
+		}
+	}
+	s.serveTelnet(conn, reader)
+}
+
+func (s *Server) serveTelnet(conn net.Conn, reader *bufio.Reader) {
+	defer conn.Close()
+	if _, err := fmt.Fprintf(conn, "%s\r\n%s", welcome, prompt); err != nil {
+		return
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if _, err = conn.Write([]byte(prompt)); err != nil {
+				return
+			}
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			_, _ = conn.Write([]byte("BYE!\r\n"))
+			return
+		}
+		output, _ := execute(fields[0], fields[1:])
+		if _, err = fmt.Fprintf(conn, "%s\r\n%s", strings.ReplaceAll(output, "\n", "\r\n"), prompt); err != nil {
+			return
+		}
+	}
+}
+
+// serveHTTP executes the command of the first path segment, the other segments are the arguments,
+// e.g. GET /offline/com.foo.BarService
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.FieldsFunc(r.URL.Path, func(c rune) bool { return c == '/' })
+	if len(segments) == 0 {
+		segments = []string{"help"}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	output, ok := execute(segments[0], segments[1:])
+	if _, exist := commands[segments[0]]; !exist {
+		w.WriteHeader(http.StatusNotFound)
+	} else if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = w.Write([]byte(output + "\n"))
+}
+
+func isLocalAddr(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
+
+// bufferedConn reads the bytes peeked for protocol detection first
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connListener passes the detected http connections to the http server
+type connListener struct {
+	addr      net.Addr
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newConnListener(addr net.Addr) *connListener {
+	return &connListener{addr: addr, conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *connListener) put(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+// Accept waits for the next http connection
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, perrors.New("qos listener closed")
+	}
+}
+
+// Close closes the listener
+func (l *connListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+// Addr returns the address of QoS server
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
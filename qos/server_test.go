@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qos
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/config"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	registryProtocol "dubbo.apache.org/dubbo-go/v3/registry/protocol"
+)
+
+type mockRegistryProtocol struct {
+	protocol.BaseProtocol
+	registrations []*registryProtocol.ProviderRegistration
+}
+
+func (p *mockRegistryProtocol) GetProviderRegistrations() []*registryProtocol.ProviderRegistration {
+	return p.registrations
+}
+
+func initMockProviders(t *testing.T) *registryProtocol.ProviderRegistration {
+	svc := config.NewServiceConfig("UserProvider")
+	svc.InterfaceName = "com.ikurento.user.UserProvider"
+	svc.Version = "1.0.0"
+	config.SetProviderConfig(config.ProviderConfig{Services: map[string]*config.ServiceConfig{"UserProvider": svc}})
+
+	regURL, _ := common.NewURL("mock://127.0.0.1:1111")
+	reg, err := registry.NewMockRegistry(regURL)
+	assert.NoError(t, err)
+	providerURL, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?version=1.0.0")
+	registration := registryProtocol.NewProviderRegistration(reg, providerURL)
+	p := &mockRegistryProtocol{registrations: []*registryProtocol.ProviderRegistration{registration}}
+	extension.SetProtocol(constant.REGISTRY_KEY, func() protocol.Protocol { return p })
+	return registration
+}
+
+func TestCommands(t *testing.T) {
+	registration := initMockProviders(t)
+
+	output, ok := execute("ls", nil)
+	assert.True(t, ok)
+	assert.Regexp(t, `com.ikurento.user.UserProvider:1.0.0\s+Y`, output)
+
+	output, ok = execute("offline", []string{"com.ikurento.*"})
+	assert.True(t, ok)
+	assert.Equal(t, "OK", output)
+	assert.False(t, registration.IsOnline())
+	output, _ = execute("ls", nil)
+	assert.Regexp(t, `com.ikurento.user.UserProvider:1.0.0\s+N`, output)
+
+	_, ok = execute("online", []string{"com.foo.*"})
+	assert.False(t, ok)
+	assert.False(t, registration.IsOnline())
+	_, ok = execute("online", nil)
+	assert.True(t, ok)
+	assert.True(t, registration.IsOnline())
+
+	// the service has not been exported
+	output, ok = execute("startup", nil)
+	assert.False(t, ok)
+	assert.Equal(t, "false", output)
+	_, ok = execute("ready", nil)
+	assert.False(t, ok)
+
+	output, ok = execute("help", nil)
+	assert.True(t, ok)
+	for name := range commands {
+		assert.Contains(t, output, name)
+	}
+	_, ok = execute("unknown", nil)
+	assert.False(t, ok)
+}
+
+func TestServer(t *testing.T) {
+	registration := initMockProviders(t)
+	server := NewServer(WithPort(0))
+	assert.NoError(t, server.Start())
+	defer server.Stop()
+	addr := fmt.Sprintf("127.0.0.1:%d", server.Addr().(*net.TCPAddr).Port)
+
+	// telnet
+	conn, err := net.Dial("tcp", addr)
+	assert.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	readUntilPrompt := func() string {
+		var buf strings.Builder
+		for !strings.HasSuffix(buf.String(), prompt) {
+			b, err := reader.ReadByte()
+			if !assert.NoError(t, err) {
+				break
+			}
+			buf.WriteByte(b)
+		}
+		return buf.String()
+	}
+	assert.Contains(t, readUntilPrompt(), welcome)
+	_, err = conn.Write([]byte("offline com.ikurento.user.UserProvider\r\n"))
+	assert.NoError(t, err)
+	assert.Contains(t, readUntilPrompt(), "OK")
+	assert.False(t, registration.IsOnline())
+
+	// http
+	resp, err := http.Get("http://" + addr + "/online/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "OK\n", string(body))
+	assert.True(t, registration.IsOnline())
+
+	resp, err = http.Get("http://" + addr + "/ready")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = http.Get("http://" + addr + "/unknown")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestServerRejectForeignIP(t *testing.T) {
+	assert.True(t, isLocalAddr(&net.TCPAddr{IP: net.ParseIP("127.0.0.1")}))
+	assert.False(t, isLocalAddr(&net.TCPAddr{IP: net.ParseIP("10.0.0.1")}))
+}
@@ -32,6 +32,7 @@ import (
 
 // nolint
 type BaseConfigurationListener struct {
+	key                     string
 	configurators           []config_center.Configurator
 	dynamicConfiguration    config_center.DynamicConfiguration
 	defaultConfiguratorFunc func(url *common.URL) config_center.Configurator
@@ -52,6 +53,7 @@ func (bcl *BaseConfigurationListener) InitWith(key string, listener config_cente
 		bcl.configurators = []config_center.Configurator{}
 		return
 	}
+	bcl.key = key
 	bcl.defaultConfiguratorFunc = f
 	bcl.dynamicConfiguration.AddListener(key, listener)
 	if rawConfig, err := bcl.dynamicConfiguration.GetInternalProperty(key,
@@ -66,6 +68,14 @@ func (bcl *BaseConfigurationListener) InitWith(key string, listener config_cente
 	}
 }
 
+// RemoveListener stops @listener listening to the key which it is initialized with
+func (bcl *BaseConfigurationListener) RemoveListener(listener config_center.ConfigurationListener) {
+	if bcl.dynamicConfiguration == nil || len(bcl.key) == 0 {
+		return
+	}
+	bcl.dynamicConfiguration.RemoveListener(bcl.key, listener)
+}
+
 // Process the notification event once there's any change happens on the config.
 func (bcl *BaseConfigurationListener) Process(event *config_center.ConfigChangeEvent) {
 	logger.Debugf("Notification of overriding rule, change type is: %v , raw config content is:%v", event.ConfigType, event.Value)
@@ -300,9 +300,14 @@ func (dir *RegistryDirectory) toGroupInvokers() []protocol.Invoker {
 			groupInvokersList = invokers
 		}
 	} else {
+		clusterName := dir.GetURL().SubURL.GetParam(constant.CLUSTER_KEY, constant.DEFAULT_CLUSTER)
+		if clusterName == constant.MERGEABLE_CLUSTER_NAME {
+			// the groups are merged by the mergeable cluster, the invokers in one group use the default cluster
+			clusterName = constant.DEFAULT_CLUSTER
+		}
 		for _, invokers := range groupInvokersMap {
 			staticDir := directory.NewStaticDirectory(invokers)
-			cst := extension.GetCluster(dir.GetURL().SubURL.GetParam(constant.CLUSTER_KEY, constant.DEFAULT_CLUSTER))
+			cst := extension.GetCluster(clusterName)
 			err = staticDir.BuildRouterChain(invokers)
 			if err != nil {
 				logger.Error(err)
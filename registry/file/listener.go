@@ -17,7 +17,25 @@
 
 package file
 
-import "dubbo.apache.org/dubbo-go/v3/config_center"
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/fsnotify/fsnotify"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
 
 // RegistryConfigurationListener represent the processor of flie watcher
 type RegistryConfigurationListener struct{}
@@ -25,3 +43,148 @@ type RegistryConfigurationListener struct{}
 // Process submit the ConfigChangeEvent to the event chan to notify all observer
 func (l *RegistryConfigurationListener) Process(configType *config_center.ConfigChangeEvent) {
 }
+
+// fileListener watches the providers directory and converts the file changes to service events
+type fileListener struct {
+	registry     *fileRegistry
+	dir          string
+	subscribeURL *common.URL
+	watcher      *fsnotify.Watcher
+	events       chan *registry.ServiceEvent
+	// urls are the provider urls of the files in dir
+	urls      map[string]*common.URL
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newFileListener(reg *fileRegistry, dir string, subscribeURL *common.URL) (*fileListener, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	// watch before listing the directory, so that no change is missed
+	if err = watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, perrors.WithStack(err)
+	}
+	l := &fileListener{
+		registry:     reg,
+		dir:          dir,
+		subscribeURL: subscribeURL,
+		watcher:      watcher,
+		events:       make(chan *registry.ServiceEvent, 32),
+		urls:         make(map[string]*common.URL),
+		done:         make(chan struct{}),
+	}
+	go l.watch()
+	return l, nil
+}
+
+func (l *fileListener) watch() {
+	files, err := ioutil.ReadDir(l.dir)
+	if err != nil {
+		logger.Warnf("read registry dir %s error: %v", l.dir, err)
+	}
+	for _, f := range files {
+		if !f.IsDir() {
+			l.fileChanged(filepath.Join(l.dir, f.Name()))
+		}
+	}
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case event, ok := <-l.watcher.Events:
+			if !ok {
+				return
+			}
+			logger.Debugf("file registry watcher %s, event %v", l.dir, event)
+			if event.Op&(fsnotify.Create|fsnotify.Write) != 0 {
+				l.fileChanged(event.Name)
+			}
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				l.fileRemoved(event.Name)
+			}
+		case err, ok := <-l.watcher.Errors:
+			if !ok {
+				return
+			}
+			// err may be nil, ignore
+			if err != nil {
+				logger.Warnf("file registry watch %s fail: %v", l.dir, err)
+			}
+		}
+	}
+}
+
+func (l *fileListener) fileChanged(path string) {
+	name := filepath.Base(path)
+	// the hidden files are the temp files being written
+	if strings.HasPrefix(name, ".") {
+		return
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		// the file may be removed already
+		logger.Debugf("read registry file %s error: %v", path, err)
+		return
+	}
+	serviceURL, err := common.NewURL(strings.TrimSpace(string(content)))
+	if err != nil {
+		logger.Warnf("Listen NewURL(r{%s}) = error{%v}", path, err)
+		return
+	}
+	if !serviceURL.URLEqual(l.subscribeURL) {
+		return
+	}
+	var action remoting.EventType = remoting.EventTypeAdd
+	if old, ok := l.urls[name]; ok {
+		if old.String() == serviceURL.String() {
+			return
+		}
+		action = remoting.EventTypeUpdate
+	}
+	l.urls[name] = serviceURL
+	l.send(&registry.ServiceEvent{Action: action, Service: serviceURL})
+}
+
+func (l *fileListener) fileRemoved(path string) {
+	name := filepath.Base(path)
+	serviceURL, ok := l.urls[name]
+	if !ok {
+		return
+	}
+	delete(l.urls, name)
+	l.send(&registry.ServiceEvent{Action: remoting.EventTypeDel, Service: serviceURL})
+}
+
+func (l *fileListener) send(event *registry.ServiceEvent) {
+	select {
+	case <-l.done:
+	case l.events <- event:
+	}
+}
+
+// Next returns next service event once received
+func (l *fileListener) Next() (*registry.ServiceEvent, error) {
+	select {
+	case <-l.done:
+		return nil, perrors.New("listener stopped")
+	case <-l.registry.Done():
+		logger.Warnf("file registry is destroyed, so file event listener exit now.")
+		return nil, perrors.New("listener stopped")
+	case e := <-l.events:
+		return e, nil
+	}
+}
+
+// Close stops watching the directory
+func (l *fileListener) Close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		if err := l.watcher.Close(); err != nil {
+			logger.Warnf("close file registry watcher %s error: %v", l.dir, err)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/logger"
+	"dubbo.apache.org/dubbo-go/v3/config_center/file"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+func init() {
+	extension.SetRegistry(constant.FILE_KEY, newFileRegistry)
+}
+
+// fileRegistry is the registry based on the local file system, every registered url is a file
+// like {root}/dubbo/{service}/providers/{md5 of url}, and the subscribers watch the directory.
+// It is designed for local development and tests which run several processes on one host.
+type fileRegistry struct {
+	registry.BaseRegistry
+	rootPath     string
+	listenerLock sync.Mutex
+	listeners    []*fileListener
+	fileLock     sync.Mutex
+	files        map[string]struct{}
+}
+
+// newFileRegistry creates the file registry, the root directory is the address of registry,
+// and it is ~/.dubbo/registry by default
+func newFileRegistry(url *common.URL) (registry.Registry, error) {
+	rootPath := url.Location
+	if len(rootPath) == 0 {
+		home, err := file.Home()
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		rootPath = filepath.Join(home, ".dubbo", constant.REGISTRY_KEY)
+	}
+	if err := os.MkdirAll(rootPath, os.ModePerm); err != nil {
+		return nil, perrors.WithMessagef(err, "new file registry(root:%s)", rootPath)
+	}
+	logger.Infof("file registry root path is: %s", rootPath)
+
+	r := &fileRegistry{rootPath: rootPath, files: make(map[string]struct{})}
+	r.InitBaseRegistry(url, r)
+	return r, nil
+}
+
+// InitListeners does nothing, the listeners are created by DoSubscribe
+func (r *fileRegistry) InitListeners() {
+}
+
+// CreatePath creates the directory of @path
+func (r *fileRegistry) CreatePath(path string) error {
+	return perrors.WithStack(os.MkdirAll(r.realPath(path), os.ModePerm))
+}
+
+// DoRegister writes the registration @node to a file in @root
+func (r *fileRegistry) DoRegister(root string, node string) error {
+	rawURL, err := url.QueryUnescape(node)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	dir := r.realPath(root)
+	name := nodeFileName(node)
+	// write to a hidden temp file and rename it, so the watchers never read a partial file
+	tmp := filepath.Join(dir, "."+name+".tmp")
+	if err = ioutil.WriteFile(tmp, []byte(rawURL), 0o644); err != nil {
+		return perrors.WithStack(err)
+	}
+	if err = os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		return perrors.WithStack(err)
+	}
+	r.fileLock.Lock()
+	r.files[filepath.Join(dir, name)] = struct{}{}
+	r.fileLock.Unlock()
+	return nil
+}
+
+// DoUnregister removes the file of registration @node in @root
+func (r *fileRegistry) DoUnregister(root string, node string) error {
+	path := filepath.Join(r.realPath(root), nodeFileName(node))
+	r.fileLock.Lock()
+	delete(r.files, path)
+	r.fileLock.Unlock()
+	err := os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return perrors.WithStack(err)
+}
+
+// DoSubscribe watches the providers directory of @conf
+func (r *fileRegistry) DoSubscribe(conf *common.URL) (registry.Listener, error) {
+	dir := r.realPath("/dubbo/" + url.QueryEscape(conf.Service()) + "/" + constant.DEFAULT_CATEGORY)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	l, err := newFileListener(r, dir, conf)
+	if err != nil {
+		return nil, err
+	}
+	r.listenerLock.Lock()
+	r.listeners = append(r.listeners, l)
+	r.listenerLock.Unlock()
+	return l, nil
+}
+
+// nolint
+func (r *fileRegistry) DoUnsubscribe(conf *common.URL) (registry.Listener, error) {
+	return nil, perrors.New("DoUnsubscribe is not support in fileRegistry")
+}
+
+// CloseListener closes all listeners
+func (r *fileRegistry) CloseListener() {
+	r.listenerLock.Lock()
+	listeners := r.listeners
+	r.listeners = nil
+	r.listenerLock.Unlock()
+	for _, l := range listeners {
+		l.Close()
+	}
+}
+
+// CloseAndNilClient removes the files registered by this registry like the ephemeral nodes
+func (r *fileRegistry) CloseAndNilClient() {
+	r.fileLock.Lock()
+	defer r.fileLock.Unlock()
+	for f := range r.files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			logger.Warnf("remove registry file %s error: %v", f, err)
+		}
+	}
+	r.files = make(map[string]struct{})
+}
+
+// realPath returns the path of @path in the file system
+func (r *fileRegistry) realPath(path string) string {
+	return filepath.Join(r.rootPath, filepath.FromSlash(path))
+}
+
+// nodeFileName returns the file name of the registration @node, the url may be longer than the limit of file name
+func nodeFileName(node string) string {
+	sum := md5.Sum([]byte(node))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+type mockNotifyListener struct {
+	events chan *registry.ServiceEvent
+}
+
+func (l *mockNotifyListener) Notify(event *registry.ServiceEvent) {
+	l.events <- event
+}
+
+func (l *mockNotifyListener) NotifyAll([]*registry.ServiceEvent, func()) {
+}
+
+func (l *mockNotifyListener) next(t *testing.T) *registry.ServiceEvent {
+	select {
+	case e := <-l.events:
+		return e
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "wait for the service event timeout")
+		return nil
+	}
+}
+
+func newTestFileRegistry(t *testing.T, dir string, role int) registry.Registry {
+	regURL, err := common.NewURL("registry://"+dir,
+		common.WithParamsValue(constant.ROLE_KEY, strconv.Itoa(role)),
+		common.WithLocation(dir))
+	assert.NoError(t, err)
+	reg, err := newFileRegistry(regURL)
+	assert.NoError(t, err)
+	return reg
+}
+
+func TestFileRegistry(t *testing.T) {
+	dir := t.TempDir()
+	providerRegistry := newTestFileRegistry(t, dir, common.PROVIDER)
+	consumerRegistry := newTestFileRegistry(t, dir, common.CONSUMER)
+
+	url1, _ := common.NewURL("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider",
+		common.WithParamsValue(constant.INTERFACE_KEY, "com.ikurento.user.UserProvider"),
+		common.WithMethods([]string{"GetUser"}))
+	url2, _ := common.NewURL("dubbo://127.0.0.1:20001/com.ikurento.user.UserProvider",
+		common.WithParamsValue(constant.INTERFACE_KEY, "com.ikurento.user.UserProvider"),
+		common.WithMethods([]string{"GetUser"}))
+	assert.NoError(t, providerRegistry.Register(url1))
+
+	subscribeURL, _ := common.NewURL("dubbo://127.0.0.1/com.ikurento.user.UserProvider",
+		common.WithParamsValue(constant.INTERFACE_KEY, "com.ikurento.user.UserProvider"))
+	listener := &mockNotifyListener{events: make(chan *registry.ServiceEvent, 8)}
+	go consumerRegistry.Subscribe(subscribeURL, listener)
+
+	// the existing provider is notified first
+	e := listener.next(t)
+	assert.Equal(t, remoting.EventType(remoting.EventTypeAdd), e.Action)
+	assert.Equal(t, "20000", e.Service.Port)
+
+	assert.NoError(t, providerRegistry.Register(url2))
+	e = listener.next(t)
+	assert.Equal(t, remoting.EventType(remoting.EventTypeAdd), e.Action)
+	assert.Equal(t, "20001", e.Service.Port)
+
+	assert.NoError(t, providerRegistry.UnRegister(url1))
+	e = listener.next(t)
+	assert.Equal(t, remoting.EventType(remoting.EventTypeDel), e.Action)
+	assert.Equal(t, "20000", e.Service.Port)
+
+	// the registered files are removed when the provider registry is destroyed
+	providerRegistry.Destroy()
+	e = listener.next(t)
+	assert.Equal(t, remoting.EventType(remoting.EventTypeDel), e.Action)
+	assert.Equal(t, "20001", e.Service.Port)
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "dubbo", "com.ikurento.user.UserProvider", "providers"))
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+	consumerRegistry.Destroy()
+}
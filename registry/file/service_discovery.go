@@ -23,12 +23,15 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
+	"sync"
 )
 
 import (
 	gxset "github.com/dubbogo/gost/container/set"
 	gxpage "github.com/dubbogo/gost/hash/page"
+	"github.com/fsnotify/fsnotify"
 	perrors "github.com/pkg/errors"
 )
 
@@ -53,6 +56,11 @@ type fileSystemServiceDiscovery struct {
 	dynamicConfiguration file.FileSystemDynamicConfiguration
 	rootPath             string
 	fileMap              map[string]string
+
+	watchLock sync.Mutex
+	// watcher watches the directories of the services listened
+	watcher         *fsnotify.Watcher
+	watchedServices *gxset.HashSet
 }
 
 func newFileSystemServiceDiscovery(name string) (registry.ServiceDiscovery, error) {
@@ -79,6 +87,7 @@ func newFileSystemServiceDiscovery(name string) (registry.ServiceDiscovery, erro
 		dynamicConfiguration: *c.(*file.FileSystemDynamicConfiguration),
 		rootPath:             p,
 		fileMap:              make(map[string]string),
+		watchedServices:      gxset.NewSet(),
 	}
 
 	extension.AddCustomShutdownCallback(func() {
@@ -108,6 +117,13 @@ func (fssd *fileSystemServiceDiscovery) String() string {
 func (fssd *fileSystemServiceDiscovery) Destroy() error {
 	fssd.dynamicConfiguration.Close()
 
+	fssd.watchLock.Lock()
+	if fssd.watcher != nil {
+		fssd.watcher.Close()
+		fssd.watcher = nil
+	}
+	fssd.watchLock.Unlock()
+
 	for _, f := range fssd.fileMap {
 		fssd.releaseAndRemoveRegistrationFiles(f)
 	}
@@ -229,10 +245,11 @@ func (fssd *fileSystemServiceDiscovery) GetInstances(serviceName string) []regis
 		dsi := &registry.DefaultServiceInstance{}
 		err = json.Unmarshal([]byte(p), dsi)
 		if err != nil {
-			logger.Errorf("[FileServiceDiscovery] Could not unmarshal the properties for id{%s}, service{%s}, "+
+			// the file may be being written, it will be dispatched again when the writing is done
+			logger.Warnf("[FileServiceDiscovery] Could not unmarshal the properties for id{%s}, service{%s}, "+
 				"error = err{%v} ",
 				id, serviceName, err)
-			return make([]registry.ServiceInstance, 0)
+			continue
 		}
 
 		res = append(res, dsi)
@@ -263,12 +280,65 @@ func (fssd *fileSystemServiceDiscovery) GetRequestInstances(serviceNames []strin
 
 // ----------------- event ----------------------
 // AddListener adds a new ServiceInstancesChangedListenerImpl
-// client
+// the directories of the services are watched, and the ServiceInstancesChangedEvent is dispatched
+// when any instance file of the service changes
 func (fssd *fileSystemServiceDiscovery) AddListener(listener registry.ServiceInstancesChangedListener) error {
-	// fssd.dynamicConfiguration.AddListener(listener.ServiceName)
+	fssd.watchLock.Lock()
+	defer fssd.watchLock.Unlock()
+	if fssd.watcher == nil {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		fssd.watcher = watcher
+		go fssd.watch(watcher)
+	}
+
+	for _, v := range listener.GetServiceNames().Values() {
+		serviceName := v.(string)
+		if fssd.watchedServices.Contains(serviceName) {
+			continue
+		}
+		dir := fssd.dynamicConfiguration.GetPath("", serviceName)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return perrors.WithStack(err)
+		}
+		if err := fssd.watcher.Add(dir); err != nil {
+			return perrors.WithStack(err)
+		}
+		fssd.watchedServices.Add(serviceName)
+	}
 	return nil
 }
 
+// watch dispatches the ServiceInstancesChangedEvent of the service whose directory changes
+func (fssd *fileSystemServiceDiscovery) watch(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			logger.Debugf("[FileServiceDiscovery] watcher event %v", event)
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			serviceName := filepath.Base(filepath.Dir(event.Name))
+			if err := fssd.DispatchEventByServiceName(serviceName); err != nil {
+				logger.Warnf("[FileServiceDiscovery] dispatch event of service{%s} error: %v", serviceName, err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			// err may be nil, ignore
+			if err != nil {
+				logger.Warnf("[FileServiceDiscovery] watch fail: %v", err)
+			}
+		}
+	}
+}
+
 // DispatchEventByServiceName dispatches the ServiceInstancesChangedEvent to service instance whose name is serviceName
 func (fssd *fileSystemServiceDiscovery) DispatchEventByServiceName(serviceName string) error {
 	return fssd.DispatchEvent(registry.NewServiceInstancesChangedEvent(serviceName, fssd.GetInstances(serviceName)))
@@ -19,18 +19,22 @@ package file
 
 import (
 	"math/rand"
+	"reflect"
 	"strconv"
 	"testing"
 	"time"
 )
 
 import (
+	gxset "github.com/dubbogo/gost/container/set"
 	"github.com/stretchr/testify/assert"
 )
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/common/observer"
+	_ "dubbo.apache.org/dubbo-go/v3/common/observer/dispatcher"
 	"dubbo.apache.org/dubbo-go/v3/config"
 	"dubbo.apache.org/dubbo-go/v3/registry"
 )
@@ -86,6 +90,94 @@ func TestCURDFileSystemServiceDiscovery(t *testing.T) {
 	}()
 }
 
+func TestFileSystemServiceDiscoveryListener(t *testing.T) {
+	prepareData()
+	extension.SetAndInitGlobalDispatcher("direct")
+	serviceDiscovery, err := newFileSystemServiceDiscovery(testName)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, serviceDiscovery.Destroy())
+	}()
+
+	rand.Seed(time.Now().Unix())
+	serviceName := "service-name" + strconv.Itoa(rand.Intn(10000))
+	listener := &mockInstancesChangedListener{
+		serviceNames: gxset.NewSet(serviceName),
+		events:       make(chan *registry.ServiceInstancesChangedEvent, 8),
+	}
+	extension.GetGlobalDispatcher().AddEventListener(listener)
+	assert.NoError(t, serviceDiscovery.AddListener(listener))
+
+	instance := &registry.DefaultServiceInstance{
+		ID:          "123456789",
+		ServiceName: serviceName,
+		Host:        "127.0.0.1",
+		Port:        2233,
+		Enable:      true,
+		Healthy:     true,
+	}
+	assert.NoError(t, serviceDiscovery.Register(instance))
+	e := listener.wait(t, func(e *registry.ServiceInstancesChangedEvent) bool {
+		return len(e.Instances) == 1
+	})
+	assert.Equal(t, instance.ID, e.Instances[0].GetID())
+
+	assert.NoError(t, serviceDiscovery.Unregister(instance))
+	listener.wait(t, func(e *registry.ServiceInstancesChangedEvent) bool {
+		return len(e.Instances) == 0
+	})
+}
+
+type mockInstancesChangedListener struct {
+	serviceNames *gxset.HashSet
+	events       chan *registry.ServiceInstancesChangedEvent
+}
+
+// wait waits for the event which matches @f
+func (l *mockInstancesChangedListener) wait(t *testing.T,
+	f func(*registry.ServiceInstancesChangedEvent) bool) *registry.ServiceInstancesChangedEvent {
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case e := <-l.events:
+			if f(e) {
+				return e
+			}
+		case <-timeout:
+			assert.Fail(t, "wait for the service instances changed event timeout")
+			return nil
+		}
+	}
+}
+
+func (l *mockInstancesChangedListener) OnEvent(e observer.Event) error {
+	l.events <- e.(*registry.ServiceInstancesChangedEvent)
+	return nil
+}
+
+func (l *mockInstancesChangedListener) AddListenerAndNotify(string, registry.NotifyListener) {
+}
+
+func (l *mockInstancesChangedListener) RemoveListener(string) {
+}
+
+func (l *mockInstancesChangedListener) GetServiceNames() *gxset.HashSet {
+	return l.serviceNames
+}
+
+func (l *mockInstancesChangedListener) Accept(e observer.Event) bool {
+	ce, ok := e.(*registry.ServiceInstancesChangedEvent)
+	return ok && l.serviceNames.Contains(ce.ServiceName)
+}
+
+func (l *mockInstancesChangedListener) GetEventType() reflect.Type {
+	return reflect.TypeOf(registry.ServiceInstancesChangedEvent{})
+}
+
+func (l *mockInstancesChangedListener) GetPriority() int {
+	return -1
+}
+
 func prepareData() {
 	config.GetBaseConfig().ServiceDiscoveries[testName] = &config.ServiceDiscoveryConfig{
 		Protocol: "file",
@@ -29,6 +29,7 @@ import (
 )
 
 import (
+	"dubbo.apache.org/dubbo-go/v3/cluster/cluster_impl"
 	"dubbo.apache.org/dubbo-go/v3/common"
 	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/extension"
@@ -60,7 +61,9 @@ type registryProtocol struct {
 	// To solve the problem of RMI repeated exposure port conflicts,
 	// the services that have been exposed are no longer exposed.
 	// providerurl <--> exporter
-	bounds                        *sync.Map
+	bounds *sync.Map
+	// registryUrl#providerUrl <--> *ProviderRegistration
+	providers                     *sync.Map
 	overrideListeners             *sync.Map
 	serviceConfigurationListeners *sync.Map
 	providerConfigurationListener *providerConfigurationListener
@@ -81,6 +84,7 @@ func newRegistryProtocol() *registryProtocol {
 	return &registryProtocol{
 		registries: &sync.Map{},
 		bounds:     &sync.Map{},
+		providers:  &sync.Map{},
 	}
 }
 
@@ -131,6 +135,16 @@ func (proto *registryProtocol) GetRegistries() []registry.Registry {
 	return rs
 }
 
+// GetProviderRegistrations returns the provider urls registered to the registries
+func (proto *registryProtocol) GetProviderRegistrations() []*ProviderRegistration {
+	var registrations []*ProviderRegistration
+	proto.providers.Range(func(_, v interface{}) bool {
+		registrations = append(registrations, v.(*ProviderRegistration))
+		return true
+	})
+	return registrations
+}
+
 // Refer provider service from registry center
 func (proto *registryProtocol) Refer(url *common.URL) protocol.Invoker {
 	registryUrl := url
@@ -163,6 +177,9 @@ func (proto *registryProtocol) Refer(url *common.URL) protocol.Invoker {
 
 	// new cluster invoker
 	cluster := extension.GetCluster(serviceUrl.GetParam(constant.CLUSTER_KEY, constant.DEFAULT_CLUSTER))
+	if cluster_impl.HasMock(serviceUrl) {
+		cluster = cluster_impl.NewMockClusterWrapper(cluster)
+	}
 	invoker := cluster.Join(directory)
 	proto.invokers = append(proto.invokers, invoker)
 	return invoker
@@ -201,6 +218,9 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 			providerUrl.Key(), registryUrl.Key(), err.Error())
 		return nil
 	}
+	registration := NewProviderRegistration(reg, registeredProviderUrl)
+	registrationKey := getRegistrationKey(registryUrl, providerUrl)
+	proto.providers.Store(registrationKey, registration)
 
 	key := getCacheKey(invoker)
 	logger.Infof("The cached exporter keys is %v!", key)
@@ -209,7 +229,17 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 		logger.Infof("The exporter has been cached, and will return cached exporter!")
 	} else {
 		wrappedInvoker := newWrappedInvoker(invoker, providerUrl)
-		cachedExporter = extension.GetProtocol(protocolwrapper.FILTER).Export(wrappedInvoker)
+		exporter := extension.GetProtocol(protocolwrapper.FILTER).Export(wrappedInvoker)
+		if exporter == nil {
+			logger.Errorf("provider service %v export error, it is unregistered from registry %v",
+				providerUrl.Key(), registryUrl.Key())
+			proto.providers.Delete(registrationKey)
+			if err = reg.UnRegister(registeredProviderUrl); err != nil {
+				logger.Warnf("reg.UnRegister(url:%v) = error:%v", registeredProviderUrl, err)
+			}
+			return nil
+		}
+		cachedExporter = exporter
 		proto.bounds.Store(key, cachedExporter)
 		logger.Infof("The exporter has not been cached, and will return a new exporter!")
 	}
@@ -219,7 +249,7 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 			logger.Warnf("reg.subscribe(overriderUrl:%v) = error:%v", overriderUrl, err)
 		}
 	}()
-	return cachedExporter.(protocol.Exporter)
+	return &registryExporter{Exporter: cachedExporter.(protocol.Exporter), providers: proto.providers, registrationKey: registrationKey}
 }
 
 func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common.URL) {
@@ -228,6 +258,8 @@ func (proto *registryProtocol) reExport(invoker protocol.Invoker, newUrl *common
 		wrappedNewInvoker := newWrappedInvoker(invoker, newUrl)
 		oldExporter.(protocol.Exporter).Unexport()
 		proto.bounds.Delete(key)
+		// the registration of the old provider url is replaced by the one stored by Export
+		proto.providers.Delete(getRegistrationKey(getRegistryUrl(invoker), oldExporter.(protocol.Exporter).GetInvoker().GetURL()))
 		// oldExporter Unexport function unRegister rpcService from the serviceMap, so need register it again as far as possible
 		if err := registerServiceMap(invoker); err != nil {
 			logger.Error(err.Error())
@@ -392,6 +424,10 @@ func (proto *registryProtocol) Destroy() {
 		proto.bounds.Delete(key)
 		return true
 	})
+	proto.providers.Range(func(key, value interface{}) bool {
+		proto.providers.Delete(key)
+		return true
+	})
 	proto.registries.Range(func(key, value interface{}) bool {
 		reg := value.(registry.Registry)
 		if reg.IsAvailable() {
@@ -422,6 +458,24 @@ func setProviderUrl(regURL *common.URL, providerURL *common.URL) {
 	regURL.SubURL = providerURL
 }
 
+// getRegistrationKey returns the key of the ProviderRegistration of @providerUrl to the registry of @registryUrl
+func getRegistrationKey(registryUrl *common.URL, providerUrl *common.URL) string {
+	return registryUrl.Key() + "#" + getUrlToRegistry(providerUrl, registryUrl).Key()
+}
+
+// registryExporter removes the ProviderRegistration of the exported service when it is unexported
+type registryExporter struct {
+	protocol.Exporter
+	providers       *sync.Map
+	registrationKey string
+}
+
+// Unexport removes the ProviderRegistration and unexports the service
+func (e *registryExporter) Unexport() {
+	e.providers.Delete(e.registrationKey)
+	e.Exporter.Unexport()
+}
+
 // GetProtocol return the singleton registryProtocol
 func GetProtocol() protocol.Protocol {
 	once.Do(func() {
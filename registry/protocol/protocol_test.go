@@ -133,7 +133,8 @@ func exporterNormal(t *testing.T, regProtocol *registryProtocol) *common.URL {
 	invoker := protocol.NewBaseInvoker(url)
 	exporter := regProtocol.Export(invoker)
 
-	assert.IsType(t, &protocol.BaseExporter{}, exporter)
+	assert.IsType(t, &registryExporter{}, exporter)
+	assert.IsType(t, &protocol.BaseExporter{}, exporter.(*registryExporter).Exporter)
 	assert.Equal(t, exporter.GetInvoker().GetURL().String(), suburl.String())
 	return url
 }
@@ -143,6 +144,31 @@ func TestExporter(t *testing.T) {
 	exporterNormal(t, regProtocol)
 }
 
+func TestProviderRegistration(t *testing.T) {
+	regProtocol := newRegistryProtocol()
+	exporterNormal(t, regProtocol)
+
+	registrations := regProtocol.GetProviderRegistrations()
+	assert.Len(t, registrations, 1)
+	registration := registrations[0]
+	assert.Equal(t, "group/org.apache.dubbo-go.mockService:1.0.0", registration.GetURL().ServiceKey())
+	assert.True(t, registration.IsOnline())
+	assert.NoError(t, registration.Offline())
+	assert.False(t, registration.IsOnline())
+	assert.NoError(t, registration.Online())
+	assert.True(t, registration.IsOnline())
+
+	regProtocol.Destroy()
+	assert.Empty(t, regProtocol.GetProviderRegistrations())
+
+	// the registration is removed with the unexported service
+	url := exporterNormal(t, regProtocol)
+	exporter := regProtocol.Export(protocol.NewBaseInvoker(url))
+	assert.Len(t, regProtocol.GetProviderRegistrations(), 1)
+	exporter.Unexport()
+	assert.Empty(t, regProtocol.GetProviderRegistrations())
+}
+
 func TestMultiRegAndMultiProtoExporter(t *testing.T) {
 	regProtocol := newRegistryProtocol()
 	exporterNormal(t, regProtocol)
@@ -250,6 +276,21 @@ func TestExportWithOverrideListener(t *testing.T) {
 	key := newUrl.CloneExceptParams(delKeys).String()
 	v2, _ := regProtocol.bounds.Load(key)
 	assert.NotNil(t, v2)
+
+}
+
+func TestReExportProviderRegistration(t *testing.T) {
+	regProtocol := newRegistryProtocol()
+	url := exporterNormal(t, regProtocol)
+
+	// the registration of the old url is replaced by the one of the new url
+	newUrl := url.Clone()
+	newUrl.SubURL = url.SubURL.Clone()
+	newUrl.SubURL.SetParam(constant.VERSION_KEY, "2.0.0")
+	regProtocol.reExport(protocol.NewBaseInvoker(url), newUrl)
+	registrations := regProtocol.GetProviderRegistrations()
+	assert.Len(t, registrations, 1)
+	assert.Equal(t, "2.0.0", registrations[0].GetURL().GetParam(constant.VERSION_KEY, ""))
 }
 
 func TestExportWithServiceConfig(t *testing.T) {
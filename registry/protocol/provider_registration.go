@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"sync"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/registry"
+)
+
+// ProviderRegistration is a provider url registered to the registry when exporting,
+// it could be taken offline and online again without unexporting the service.
+type ProviderRegistration struct {
+	mutex    sync.Mutex
+	registry registry.Registry
+	url      *common.URL
+	online   bool
+}
+
+// NewProviderRegistration returns the registration of @url which has been registered to @reg
+func NewProviderRegistration(reg registry.Registry, url *common.URL) *ProviderRegistration {
+	return &ProviderRegistration{registry: reg, url: url, online: true}
+}
+
+// GetURL returns the provider url registered to the registry
+func (r *ProviderRegistration) GetURL() *common.URL {
+	return r.url
+}
+
+// GetRegistry returns the registry which the provider url is registered to
+func (r *ProviderRegistration) GetRegistry() registry.Registry {
+	return r.registry
+}
+
+// IsOnline checks whether the provider url is registered to the registry now
+func (r *ProviderRegistration) IsOnline() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.online
+}
+
+// Online registers the provider url to the registry again if it is offline
+func (r *ProviderRegistration) Online() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.online {
+		return nil
+	}
+	if err := r.registry.Register(r.url); err != nil {
+		return err
+	}
+	r.online = true
+	return nil
+}
+
+// Offline unregisters the provider url from the registry, the service is still exported
+func (r *ProviderRegistration) Offline() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if !r.online {
+		return nil
+	}
+	if err := r.registry.UnRegister(r.url); err != nil {
+		return err
+	}
+	r.online = false
+	return nil
+}
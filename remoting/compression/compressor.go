@@ -0,0 +1,34 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compression
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// ErrTooLarge is returned when the decompressed data exceeds the max size
+var ErrTooLarge = perrors.New("the decompressed data is too large")
+
+// Compressor compresses and decompresses message bodies. The implementations are registered by
+// extension.SetCompressor with their names, so they could be shared by all protocols.
+type Compressor interface {
+	// Compress compresses @data
+	Compress(data []byte) ([]byte, error)
+	// Decompress decompresses @data, ErrTooLarge is returned if the decompressed data exceeds @maxSize bytes
+	Decompress(data []byte, maxSize int) ([]byte, error)
+}
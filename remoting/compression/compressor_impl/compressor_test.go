@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compressor_impl
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+)
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/remoting/compression"
+)
+
+func TestCompressors(t *testing.T) {
+	data := bytes.Repeat([]byte("dubbo-go compression "), 100)
+	for _, name := range []string{constant.GZIP_COMPRESSION, constant.ZSTD_COMPRESSION, constant.SNAPPY_COMPRESSION} {
+		compressor, ok := extension.GetCompressor(name)
+		assert.True(t, ok, name)
+
+		compressed, err := compressor.Compress(data)
+		assert.NoError(t, err, name)
+		assert.Less(t, len(compressed), len(data), name)
+
+		decompressed, err := compressor.Decompress(compressed, len(data))
+		assert.NoError(t, err, name)
+		assert.Equal(t, data, decompressed, name)
+
+		_, err = compressor.Decompress(compressed, len(data)-1)
+		assert.Equal(t, compression.ErrTooLarge, err, name)
+
+		_, err = compressor.Decompress([]byte("not compressed"), len(data))
+		assert.Error(t, err, name)
+	}
+	assert.Equal(t, []string{"gzip", "snappy", "zstd"}, extension.GetCompressorNames())
+}
+
+func TestZstdDecompressBomb(t *testing.T) {
+	compressor, ok := extension.GetCompressor(constant.ZSTD_COMPRESSION)
+	assert.True(t, ok)
+	data := make([]byte, 10<<20)
+	maxSize := 1 << 20
+
+	// the frames written by a stream encoder have no content size
+	for _, windowSize := range []int{32 << 10, 8 << 20} {
+		buf := &bytes.Buffer{}
+		encoder, err := zstd.NewWriter(buf, zstd.WithWindowSize(windowSize))
+		assert.NoError(t, err)
+		_, err = encoder.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, encoder.Close())
+		var header zstd.Header
+		assert.NoError(t, header.Decode(buf.Bytes()))
+		assert.False(t, header.HasFCS)
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, err = compressor.Decompress(buf.Bytes(), maxSize)
+		runtime.ReadMemStats(&after)
+		assert.Equal(t, compression.ErrTooLarge, err, windowSize)
+		// the bomb is not decompressed entirely
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(len(data)), windowSize)
+	}
+
+	// every frame is smaller than the max size, but the concatenated frames are not
+	frame, err := compressor.Compress(data[:maxSize/2])
+	assert.NoError(t, err)
+	_, err = compressor.Decompress(bytes.Repeat(frame, 20), maxSize)
+	assert.Equal(t, compression.ErrTooLarge, err)
+	decompressed, err := compressor.Decompress(bytes.Repeat(frame, 2), maxSize)
+	assert.NoError(t, err)
+	assert.Equal(t, data[:maxSize], decompressed)
+}
@@ -0,0 +1,55 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compressor_impl
+
+import (
+	"github.com/golang/snappy"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/remoting/compression"
+)
+
+func init() {
+	c := &snappyCompressor{}
+	extension.SetCompressor(constant.SNAPPY_COMPRESSION, func() compression.Compressor {
+		return c
+	})
+}
+
+// snappyCompressor compresses by snappy block format
+type snappyCompressor struct{}
+
+// Compress compresses @data by snappy
+func (c *snappyCompressor) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+// Decompress decompresses @data by snappy
+func (c *snappyCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	n, err := snappy.DecodedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxSize {
+		return nil, compression.ErrTooLarge
+	}
+	return snappy.Decode(nil, data)
+}
@@ -0,0 +1,75 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compressor_impl
+
+import (
+	"bytes"
+)
+
+import (
+	"github.com/klauspost/compress/zstd"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/common/extension"
+	"dubbo.apache.org/dubbo-go/v3/remoting/compression"
+)
+
+func init() {
+	c := &zstdCompressor{}
+	// the encoder without writer is safe for concurrent EncodeAll
+	c.encoder, _ = zstd.NewWriter(nil)
+	extension.SetCompressor(constant.ZSTD_COMPRESSION, func() compression.Compressor {
+		return c
+	})
+}
+
+// zstdCompressor compresses by zstd
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+}
+
+// Compress compresses @data by zstd
+func (c *zstdCompressor) Compress(data []byte) ([]byte, error) {
+	return c.encoder.EncodeAll(data, nil), nil
+}
+
+// Decompress decompresses @data by zstd, the data is decoded as a stream so that neither the frames
+// without content size nor the concatenated frames can be decompressed to more than @maxSize bytes
+func (c *zstdCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	var header zstd.Header
+	if err := header.Decode(data); err != nil {
+		return nil, err
+	}
+	if header.HasFCS && header.FrameContentSize > uint64(maxSize) {
+		return nil, compression.ErrTooLarge
+	}
+	// the window of frame is limited by the max memory as well
+	decoder, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(uint64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+	out, err := readAtMost(decoder, maxSize)
+	if err == zstd.ErrWindowSizeExceeded || err == zstd.ErrDecoderSizeExceeded {
+		return nil, compression.ErrTooLarge
+	}
+	return out, err
+}
@@ -17,6 +17,7 @@
 package remoting
 
 import (
+	"io"
 	"sync"
 	"time"
 )
@@ -62,6 +63,8 @@ type Request struct {
 	Data   interface{}
 	TwoWay bool
 	Event  bool
+	// Stream is sent in chunks after the request, it is nil if the request has no stream
+	Stream io.Reader
 }
 
 // NewRequest aims to create Request.
@@ -18,6 +18,7 @@ package remoting
 
 import (
 	"errors"
+	"io"
 	"time"
 )
 
@@ -27,6 +28,7 @@ import (
 
 import (
 	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
 	"dubbo.apache.org/dubbo-go/v3/common/logger"
 	"dubbo.apache.org/dubbo-go/v3/protocol"
 )
@@ -115,6 +117,7 @@ func (client *ExchangeClient) Request(invocation *protocol.Invocation, url *comm
 	}
 	request := NewRequest("2.0.2")
 	request.Data = invocation
+	request.Stream = getStream(invocation)
 	request.Event = false
 	request.TwoWay = true
 
@@ -145,6 +148,7 @@ func (client *ExchangeClient) AsyncRequest(invocation *protocol.Invocation, url
 	}
 	request := NewRequest("2.0.2")
 	request.Data = invocation
+	request.Stream = getStream(invocation)
 	request.Event = false
 	request.TwoWay = true
 
@@ -170,6 +174,7 @@ func (client *ExchangeClient) Send(invocation *protocol.Invocation, url *common.
 	}
 	request := NewRequest("2.0.2")
 	request.Data = invocation
+	request.Stream = getStream(invocation)
 	request.Event = false
 	request.TwoWay = false
 
@@ -183,6 +188,12 @@ func (client *ExchangeClient) Send(invocation *protocol.Invocation, url *common.
 	return nil
 }
 
+// getStream returns the stream which is sent in chunks after the request
+func getStream(invocation *protocol.Invocation) io.Reader {
+	stream, _ := (*invocation).AttributeByKey(constant.STREAM_KEY, nil).(io.Reader)
+	return stream
+}
+
 // close client
 func (client *ExchangeClient) Close() {
 	client.client.Close()
@@ -0,0 +1,373 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+import (
+	getty "github.com/apache/dubbo-getty"
+	hessian "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+// chunk frame: magic(2 bytes) | flags(1 byte) | reserved(1 byte) | id(8 bytes) | data length(4 bytes) | data
+const (
+	chunkMagicHigh    byte = 0xda
+	chunkMagicLow     byte = 0xbc
+	chunkHeaderLength      = 16
+
+	// chunkFlagLast marks the last chunk of a package or stream
+	chunkFlagLast byte = 0x01
+	// chunkFlagStream marks the chunk of a stream rather than a package
+	chunkFlagStream byte = 0x02
+	// chunkFlagStreamStart announces the stream of the request following it
+	chunkFlagStreamStart byte = 0x04
+	// chunkFlagError marks the stream is broken at the sender, and the data is the error message
+	chunkFlagError byte = 0x08
+
+	// defaultStreamChunkSize is the size of stream chunks if ChunkSize is not set
+	defaultStreamChunkSize = 64 * 1024
+	// maxAssembledPkgLen is the max length of the packages assembled from chunks
+	maxAssembledPkgLen = 64 * 1024 * 1024
+	// maxPendingChunked is the max number of the packages being assembled and the streams being read of a session
+	maxPendingChunked = 1024
+	// maxPendingPkgLen is the max total length of the packages being assembled of a session
+	maxPendingPkgLen = 2 * maxAssembledPkgLen
+
+	chunkReaderAttrKey = "dubbo-go-chunk-reader"
+)
+
+var errStreamClosed = perrors.New("stream is closed")
+
+// chunk is a frame of a package or stream
+type chunk struct {
+	id    int64
+	flags byte
+	data  []byte
+}
+
+func (c *chunk) encode(dst []byte) []byte {
+	var header [chunkHeaderLength]byte
+	header[0], header[1], header[2] = chunkMagicHigh, chunkMagicLow, c.flags
+	binary.BigEndian.PutUint64(header[4:], uint64(c.id))
+	binary.BigEndian.PutUint32(header[12:], uint32(len(c.data)))
+	return append(append(dst, header[:]...), c.data...)
+}
+
+func isChunk(data []byte) bool {
+	return len(data) >= 2 && data[0] == chunkMagicHigh && data[1] == chunkMagicLow
+}
+
+// decodeChunk decodes the chunk at the head of @data, the data of chunk refers to @data
+func decodeChunk(data []byte) (*chunk, int, error) {
+	if len(data) < chunkHeaderLength {
+		return nil, 0, hessian.ErrHeaderNotEnough
+	}
+	length := int(binary.BigEndian.Uint32(data[12:]))
+	if len(data) < chunkHeaderLength+length {
+		return nil, 0, hessian.ErrBodyNotEnough
+	}
+	return &chunk{
+		id:    int64(binary.BigEndian.Uint64(data[4:])),
+		flags: data[2],
+		data:  data[chunkHeaderLength : chunkHeaderLength+length],
+	}, chunkHeaderLength + length, nil
+}
+
+// splitPackage splits the encoded package @pkg into chunks if it is larger than @size
+func splitPackage(id int64, pkg []byte, size int) []byte {
+	if size <= 0 || len(pkg) <= size {
+		return pkg
+	}
+	out := make([]byte, 0, len(pkg)+(len(pkg)/size+1)*chunkHeaderLength)
+	for len(pkg) > 0 {
+		c := &chunk{id: id, data: pkg}
+		if len(pkg) > size {
+			c.data = pkg[:size]
+		} else {
+			c.flags = chunkFlagLast
+		}
+		out = c.encode(out)
+		pkg = pkg[len(c.data):]
+	}
+	return out
+}
+
+// streamChunkSize returns the size of the chunks of request streams
+func streamChunkSize(param GettySessionParam) int {
+	size := param.ChunkSize
+	if size <= 0 {
+		size = defaultStreamChunkSize
+	}
+	if param.MaxMsgLen > 0 && size > param.MaxMsgLen-chunkHeaderLength {
+		size = param.MaxMsgLen - chunkHeaderLength
+	}
+	return size
+}
+
+// chunkReader assembles the packages and streams from the chunks read by a session.
+// The session is closed if the peer keeps too many packages or streams pending, or the pending
+// packages are too large in total. The chunks of streams are read by the session in order, so a
+// stream whose queue is full blocks the whole session, including the other requests and streams,
+// until the service reads or closes the stream.
+type chunkReader struct {
+	codec     remoting.Codec
+	queueSize int
+
+	lock sync.Mutex
+	pkgs map[int64][]byte
+	// pkgsLen is the total length of pkgs
+	pkgsLen int
+	streams map[int64]*stream
+	// announced are the streams whose requests are not read yet
+	announced map[int64]*stream
+	closed    bool
+}
+
+// getChunkReader returns the chunkReader of @session, which is created at the first time
+func getChunkReader(session getty.Session, codec remoting.Codec, queueSize int) *chunkReader {
+	if r, ok := session.GetAttribute(chunkReaderAttrKey).(*chunkReader); ok {
+		return r
+	}
+	r := &chunkReader{
+		codec:     codec,
+		queueSize: queueSize,
+		pkgs:      make(map[int64][]byte),
+		streams:   make(map[int64]*stream),
+		announced: make(map[int64]*stream),
+	}
+	session.SetAttribute(chunkReaderAttrKey, r)
+	return r
+}
+
+// closeChunkReader breaks the streams of @session which is closing
+func closeChunkReader(session getty.Session) {
+	if r, ok := session.GetAttribute(chunkReaderAttrKey).(*chunkReader); ok {
+		r.close()
+	}
+}
+
+// read reads a chunk from @data, it returns the package if it is assembled, or the chunk itself.
+// It blocks when the queue of stream is full, which stops the session reading more data.
+func (r *chunkReader) read(data []byte) (interface{}, int, error) {
+	c, length, err := decodeChunk(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.flags&chunkFlagStream != 0 {
+		return c, length, r.readStream(c)
+	}
+
+	r.lock.Lock()
+	partial, ok := r.pkgs[c.id]
+	if !ok && c.flags&chunkFlagLast == 0 && r.pending() >= maxPendingChunked {
+		r.lock.Unlock()
+		return nil, length, perrors.Errorf("the number of pending packages and streams is larger than %d", maxPendingChunked)
+	}
+	pkg := append(partial, c.data...)
+	if len(pkg) > maxAssembledPkgLen {
+		r.deletePkg(c.id)
+		r.lock.Unlock()
+		return nil, length, perrors.Errorf("the length of assembled package %d is larger than %d", len(pkg), maxAssembledPkgLen)
+	}
+	if c.flags&chunkFlagLast == 0 {
+		if r.pkgsLen+len(c.data) > maxPendingPkgLen {
+			r.lock.Unlock()
+			return nil, length, perrors.Errorf("the total length of pending packages is larger than %d", maxPendingPkgLen)
+		}
+		r.pkgs[c.id] = pkg
+		r.pkgsLen += len(c.data)
+		r.lock.Unlock()
+		return c, length, nil
+	}
+	r.deletePkg(c.id)
+	r.lock.Unlock()
+
+	result, _, err := r.codec.Decode(pkg)
+	if err != nil {
+		// the package is complete, so it is not a case of not enough data
+		return nil, length, perrors.Errorf("decode assembled package(len:%d) = error:%v", len(pkg), err)
+	}
+	return r.attachStream(result), length, nil
+}
+
+// pending returns the number of the packages being assembled and the streams being read, r.lock is held
+func (r *chunkReader) pending() int {
+	return len(r.pkgs) + len(r.streams)
+}
+
+// deletePkg deletes the package being assembled of @id, r.lock is held
+func (r *chunkReader) deletePkg(id int64) {
+	r.pkgsLen -= len(r.pkgs[id])
+	delete(r.pkgs, id)
+}
+
+func (r *chunkReader) readStream(c *chunk) error {
+	r.lock.Lock()
+	if r.closed {
+		r.lock.Unlock()
+		return errStreamClosed
+	}
+	if c.flags&chunkFlagStreamStart != 0 {
+		if _, ok := r.streams[c.id]; !ok && r.pending() >= maxPendingChunked {
+			r.lock.Unlock()
+			return perrors.Errorf("the number of pending packages and streams is larger than %d", maxPendingChunked)
+		}
+		s := newStream(r.queueSize)
+		r.streams[c.id] = s
+		r.announced[c.id] = s
+		r.lock.Unlock()
+		return nil
+	}
+	s, ok := r.streams[c.id]
+	if ok && c.flags&chunkFlagLast != 0 {
+		delete(r.streams, c.id)
+	}
+	r.lock.Unlock()
+	if !ok {
+		return perrors.Errorf("the stream %d is not announced", c.id)
+	}
+
+	// the data refers to the buffer of session
+	if len(c.data) > 0 && c.flags&chunkFlagError == 0 {
+		s.push(append([]byte(nil), c.data...))
+	}
+	if c.flags&chunkFlagLast != 0 {
+		var err error
+		if c.flags&chunkFlagError != 0 {
+			err = perrors.Errorf("stream is broken by sender: %s", c.data)
+		}
+		s.finish(err)
+	}
+	return nil
+}
+
+// attachStream sets the stream announced before the request of @result
+func (r *chunkReader) attachStream(result remoting.DecodeResult) remoting.DecodeResult {
+	req, ok := result.Result.(*remoting.Request)
+	if !ok || !result.IsRequest {
+		return result
+	}
+	r.lock.Lock()
+	if s, ok := r.announced[req.ID]; ok {
+		delete(r.announced, req.ID)
+		req.Stream = s
+	}
+	r.lock.Unlock()
+	return result
+}
+
+func (r *chunkReader) close() {
+	r.lock.Lock()
+	streams := r.streams
+	r.streams = make(map[int64]*stream)
+	r.announced = make(map[int64]*stream)
+	r.pkgs = make(map[int64][]byte)
+	r.pkgsLen = 0
+	r.closed = true
+	r.lock.Unlock()
+	for _, s := range streams {
+		s.finish(io.ErrUnexpectedEOF)
+	}
+}
+
+// stream is the io.Reader of the chunks following a request, at most queueSize chunks are queued,
+// and the session stops reading until the service reads the stream, so the service should read
+// or close the stream without waiting for the other requests of the same connection.
+type stream struct {
+	chunks chan []byte
+	// end is closed after the last chunk is queued
+	end chan struct{}
+	// done is closed if the receiver does not read the stream anymore
+	done       chan struct{}
+	finishOnce sync.Once
+	closeOnce  sync.Once
+	err        error
+	buf        []byte
+}
+
+func newStream(queueSize int) *stream {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	return &stream{
+		chunks: make(chan []byte, queueSize),
+		end:    make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// push queues the chunk, it blocks if the queue is full and the stream is not closed by receiver
+func (s *stream) push(data []byte) {
+	select {
+	case s.chunks <- data:
+	case <-s.done:
+	}
+}
+
+// finish marks the end of the stream, and @err is returned by Read after the queued chunks
+func (s *stream) finish(err error) {
+	s.finishOnce.Do(func() {
+		s.err = err
+		close(s.end)
+	})
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	select {
+	case <-s.done:
+		return 0, errStreamClosed
+	default:
+	}
+	for len(s.buf) == 0 {
+		select {
+		case s.buf = <-s.chunks:
+		case <-s.end:
+			// the chunks queued before the end are read at first
+			select {
+			case s.buf = <-s.chunks:
+			default:
+				if s.err != nil {
+					return 0, s.err
+				}
+				return 0, io.EOF
+			}
+		case <-s.done:
+			return 0, errStreamClosed
+		}
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Close discards the chunks which are not read
+func (s *stream) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package getty
+
+import (
+	"bytes"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	hessian "github.com/apache/dubbo-go-hessian2"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"dubbo.apache.org/dubbo-go/v3/common"
+	"dubbo.apache.org/dubbo-go/v3/common/constant"
+	"dubbo.apache.org/dubbo-go/v3/protocol"
+	"dubbo.apache.org/dubbo-go/v3/protocol/invocation"
+	"dubbo.apache.org/dubbo-go/v3/remoting"
+)
+
+func TestSplitPackage(t *testing.T) {
+	pkg := bytes.Repeat([]byte("0123456789"), 100)
+	assert.Equal(t, pkg, splitPackage(1, pkg, 0))
+	assert.Equal(t, pkg, splitPackage(1, pkg, len(pkg)))
+
+	data := splitPackage(1, pkg, 300)
+	assert.Equal(t, len(pkg)+4*chunkHeaderLength, len(data))
+	var assembled []byte
+	for i := 0; len(data) > 0; i++ {
+		assert.True(t, isChunk(data))
+		c, length, err := decodeChunk(data)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), c.id)
+		assert.Equal(t, i == 3, c.flags&chunkFlagLast != 0)
+		assembled = append(assembled, c.data...)
+		data = data[length:]
+	}
+	assert.Equal(t, pkg, assembled)
+
+	c := &chunk{id: 2, flags: chunkFlagStream, data: []byte("data")}
+	data = c.encode(nil)
+	_, _, err := decodeChunk(data[:chunkHeaderLength-1])
+	assert.Equal(t, hessian.ErrHeaderNotEnough, err)
+	_, _, err = decodeChunk(data[:len(data)-1])
+	assert.Equal(t, hessian.ErrBodyNotEnough, err)
+}
+
+func TestStreamChunkSize(t *testing.T) {
+	assert.Equal(t, defaultStreamChunkSize, streamChunkSize(GettySessionParam{}))
+	assert.Equal(t, 1024, streamChunkSize(GettySessionParam{ChunkSize: 1024}))
+	assert.Equal(t, 1024-chunkHeaderLength, streamChunkSize(GettySessionParam{MaxMsgLen: 1024}))
+}
+
+func TestStream(t *testing.T) {
+	s := newStream(2)
+	s.push([]byte("ab"))
+	s.push([]byte("cd"))
+
+	// the queue is full, so the third chunk is blocked until a chunk is read
+	pushed := make(chan struct{})
+	go func() {
+		s.push([]byte("ef"))
+		s.finish(perrors.New("broken"))
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		assert.Fail(t, "the chunk should not be queued")
+	case <-time.After(50 * time.Millisecond):
+	}
+	buf := make([]byte, 3)
+	n, err := s.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", string(buf[:n]))
+	<-pushed
+
+	data, err := io.ReadAll(s)
+	assert.EqualError(t, err, "broken")
+	assert.Equal(t, "cdef", string(data))
+
+	// the chunks are discarded after the stream is closed
+	s = newStream(1)
+	s.push([]byte("ab"))
+	assert.NoError(t, s.Close())
+	s.push([]byte("cd"))
+	_, err = s.Read(buf)
+	assert.Equal(t, errStreamClosed, err)
+}
+
+func TestChunkReaderLimits(t *testing.T) {
+	newReader := func() *chunkReader {
+		return &chunkReader{
+			queueSize: 1,
+			pkgs:      make(map[int64][]byte),
+			streams:   make(map[int64]*stream),
+			announced: make(map[int64]*stream),
+		}
+	}
+
+	// too many packages and streams pending
+	r := newReader()
+	for i := 0; i < maxPendingChunked-1; i++ {
+		_, _, err := r.read((&chunk{id: int64(i), data: []byte("a")}).encode(nil))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, r.readStream(&chunk{id: -1, flags: chunkFlagStream | chunkFlagStreamStart}))
+	_, _, err := r.read((&chunk{id: maxPendingChunked, data: []byte("a")}).encode(nil))
+	assert.Error(t, err)
+	assert.Error(t, r.readStream(&chunk{id: -2, flags: chunkFlagStream | chunkFlagStreamStart}))
+	// the pending packages are still assembled
+	_, _, err = r.read((&chunk{id: 0, data: []byte("b")}).encode(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, maxPendingChunked, r.pkgsLen)
+
+	// the pending packages are too large in total
+	r = newReader()
+	data := make([]byte, maxAssembledPkgLen/2)
+	for i := 0; i < 4; i++ {
+		_, _, err = r.read((&chunk{id: int64(i), data: data}).encode(nil))
+		assert.NoError(t, err)
+	}
+	_, _, err = r.read((&chunk{id: 4, data: data}).encode(nil))
+	assert.Error(t, err)
+	assert.Equal(t, maxPendingPkgLen, r.pkgsLen)
+	r.close()
+	assert.Equal(t, 0, r.pkgsLen)
+}
+
+func TestChunkedTransfer(t *testing.T) {
+	hessian.RegisterPOJO(&User{})
+	remoting.RegistryCodec("dubbo", &DubboTestCodec{})
+
+	sessionParam := GettySessionParam{
+		TcpNoDelay:      true,
+		TcpKeepAlive:    true,
+		KeepAlivePeriod: "120s",
+		TcpRBufSize:     262144,
+		TcpWBufSize:     65536,
+		PkgWQSize:       4,
+		TcpReadTimeout:  "1s",
+		TcpWriteTimeout: "5s",
+		WaitTimeout:     "1s",
+		MaxMsgLen:       128 * 1024,
+		ChunkSize:       64 * 1024,
+	}
+	clientParam, serverParam := sessionParam, sessionParam
+	clientParam.SessionName, serverParam.SessionName = "client", "server"
+	SetClientConf(ClientConfig{
+		ConnectionNum:     1,
+		HeartbeatPeriod:   "5s",
+		SessionTimeout:    "20s",
+		GettySessionParam: clientParam,
+	})
+	assert.NoError(t, clientConf.CheckValidity())
+	SetServerConfig(ServerConfig{
+		SessionNumber:     700,
+		SessionTimeout:    "20s",
+		GettySessionParam: serverParam,
+	})
+	assert.NoError(t, srvConf.CheckValidity())
+
+	url, err := common.NewURL("dubbo://127.0.0.1:20061/com.ikurento.user.ChunkProvider?" +
+		"interface=com.ikurento.user.ChunkProvider&side=provider&timeout=10000")
+	assert.NoError(t, err)
+	handler := func(inv *invocation.RPCInvocation) protocol.RPCResult {
+		switch inv.MethodName() {
+		case "Echo":
+			return protocol.RPCResult{Rest: &User{ID: "echo", Name: inv.Arguments()[0].(string)}}
+		case "Upload":
+			stream, _ := inv.AttributeByKey(constant.STREAM_KEY, nil).(io.Reader)
+			if stream == nil {
+				return protocol.RPCResult{Err: perrors.New("no stream")}
+			}
+			// read the stream slowly at first, the client is blocked by the flow control
+			time.Sleep(200 * time.Millisecond)
+			hash := crc32.NewIEEE()
+			n, err := io.Copy(hash, stream)
+			if err != nil {
+				return protocol.RPCResult{Err: err}
+			}
+			return protocol.RPCResult{Rest: &User{ID: strconv.FormatInt(n, 10), Name: strconv.FormatUint(uint64(hash.Sum32()), 10)}}
+		}
+		return protocol.RPCResult{Err: perrors.Errorf("unknown method %s", inv.MethodName())}
+	}
+	server := NewServer(url, handler)
+	server.Start()
+	defer server.Stop()
+	time.Sleep(time.Second)
+
+	client := getClient(url)
+	assert.NotNil(t, client)
+	defer client.Close()
+	call := func(method string, args []interface{}, stream io.Reader) (*User, error) {
+		user := &User{}
+		request := remoting.NewRequest("2.0.2")
+		inv := createInvocation(method, nil, nil, args, nil)
+		setAttachment(inv, map[string]string{constant.INTERFACE_KEY: "com.ikurento.user.ChunkProvider"})
+		request.Data = inv
+		request.TwoWay = true
+		request.Stream = stream
+		pendingResponse := remoting.NewPendingResponse(request.ID)
+		pendingResponse.Reply = user
+		remoting.AddPendingResponse(pendingResponse)
+		return user, client.Request(request, 10*time.Second, pendingResponse)
+	}
+
+	// the request and response larger than max message length are transferred in chunks
+	name := strings.Repeat("chunked ", 512*1024)
+	user, err := call("Echo", []interface{}{name}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, User{ID: "echo", Name: name}, *user)
+
+	// the stream is read by the service as an io.Reader
+	data := make([]byte, 16*1024*1024+100)
+	rand.New(rand.NewSource(1)).Read(data)
+	user, err = call("Upload", []interface{}{"file"}, bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(len(data)), user.ID)
+	assert.Equal(t, strconv.FormatUint(uint64(crc32.ChecksumIEEE(data)), 10), user.Name)
+
+	// the session is still available after the transfers
+	user, err = call("Echo", []interface{}{"small"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "small", user.Name)
+}
@@ -48,6 +48,9 @@ type (
 		waitTimeout      time.Duration
 		MaxMsgLen        int    `default:"1024" yaml:"max_msg_len" json:"max_msg_len,omitempty"`
 		SessionName      string `default:"rpc" yaml:"session_name" json:"session_name,omitempty"`
+		// the packages larger than ChunkSize are written in chunks, 0 means the packages are not chunked,
+		// and the peer must be dubbo-go which supports chunks.
+		ChunkSize int `default:"0" yaml:"chunk_size" json:"chunk_size,omitempty"`
 	}
 
 	// ServerConfig holds supported types by the multiconfig package
@@ -177,6 +180,11 @@ func (c *GettySessionParam) CheckValidity() error {
 		return perrors.WithMessagef(err, "time.ParseDuration(WaitTimeout{%#v})", c.WaitTimeout)
 	}
 
+	if c.ChunkSize < 0 || c.ChunkSize > 0 && c.MaxMsgLen > 0 && c.ChunkSize+chunkHeaderLength > c.MaxMsgLen {
+		return perrors.Errorf("chunk_size %d should be positive and less than max_msg_len %d by %d",
+			c.ChunkSize, c.MaxMsgLen, chunkHeaderLength)
+	}
+
 	return nil
 }
 
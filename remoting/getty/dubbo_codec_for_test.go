@@ -71,7 +71,7 @@ func (c *DubboTestCodec) EncodeRequest(request *remoting.Request) (*bytes.Buffer
 
 	header := impl.DubboHeader{}
 	serialization := tmpInvocation.AttachmentsByKey(constant.SERIALIZATION_KEY, constant.HESSIAN2_SERIALIZATION)
-	if serialization == constant.PROTOBUF_SERIALIZATION {
+	if serialization == constant.PROTOBUF_JSON_SERIALIZATION {
 		header.SerialID = constant.S_Proto
 	} else {
 		header.SerialID = constant.S_Hessian2
@@ -18,6 +18,7 @@
 package getty
 
 import (
+	"io"
 	"math/rand"
 	"sync"
 	"time"
@@ -120,6 +121,7 @@ type Client struct {
 	conf               ClientConfig
 	mux                sync.RWMutex
 	sslEnabled         bool
+	tlsBuilder         getty.TlsConfigBuilder
 	clientClosed       bool
 	gettyClient        *gettyRPCClient
 	gettyClientMux     sync.RWMutex
@@ -153,6 +155,9 @@ func (c *Client) Connect(url *common.URL) error {
 	initClient(url.Protocol)
 	c.conf = *clientConf
 	c.sslEnabled = url.GetParamBool(constant.SSL_ENABLED_KEY, false)
+	if c.sslEnabled {
+		c.tlsBuilder = config.GetClientTlsConfigBuilderByURL(url)
+	}
 	// codec
 	c.codec = remoting.GetCodec(url.Protocol)
 	c.addr = url.Location
@@ -251,7 +256,43 @@ func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, err
 
 func (c *Client) transfer(session getty.Session, request *remoting.Request, timeout time.Duration) (int, int, error) {
 	totalLen, sendLen, err := session.WritePkg(request, timeout)
-	return totalLen, sendLen, perrors.WithStack(err)
+	if err != nil || request.Stream == nil {
+		return totalLen, sendLen, perrors.WithStack(err)
+	}
+	streamTotalLen, streamSendLen, err := c.transferStream(session, request, timeout)
+	return totalLen + streamTotalLen, sendLen + streamSendLen, err
+}
+
+// transferStream sends the stream of @request in chunks, the writing is blocked by the tcp flow control
+// if the server does not read the stream in time.
+func (c *Client) transferStream(session getty.Session, request *remoting.Request, timeout time.Duration) (int, int, error) {
+	var totalLen, sendLen int
+	buf := make([]byte, streamChunkSize(c.conf.GettySessionParam))
+	for {
+		n, readErr := io.ReadFull(request.Stream, buf)
+		data := &chunk{id: request.ID, flags: chunkFlagStream, data: buf[:n]}
+		switch readErr {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			data.flags |= chunkFlagLast
+		default:
+			// tell the server that the stream is broken
+			data.flags |= chunkFlagLast | chunkFlagError
+			data.data = []byte(readErr.Error())
+		}
+		t, s, err := session.WritePkg(data, timeout)
+		totalLen += t
+		sendLen += s
+		if err != nil {
+			return totalLen, sendLen, perrors.WithStack(err)
+		}
+		if data.flags&chunkFlagError != 0 {
+			return totalLen, sendLen, perrors.Wrapf(readErr, "read stream of request %d", request.ID)
+		}
+		if data.flags&chunkFlagLast != 0 {
+			return totalLen, sendLen, nil
+		}
+	}
 }
 
 func (c *Client) resetRpcConn() {
@@ -98,6 +98,7 @@ type Server struct {
 	tcpServer      getty.Server
 	rpcHandler     *RpcServerHandler
 	requestHandler func(*invocation.RPCInvocation) protocol.RPCResult
+	tlsBuilder     getty.TlsConfigBuilder
 }
 
 // NewServer create a new Server
@@ -113,6 +114,9 @@ func NewServer(url *common.URL, handlers func(*invocation.RPCInvocation) protoco
 		codec:          remoting.GetCodec(url.Protocol),
 		requestHandler: handlers,
 	}
+	if s.conf.SSLEnabled {
+		s.tlsBuilder = config.GetServerTlsConfigBuilderByURL(url)
+	}
 
 	s.rpcHandler = NewRpcServerHandler(s.conf.SessionNumber, s.conf.sessionTimeout, s)
 
@@ -193,7 +197,7 @@ func (s *Server) Start() {
 	serverOpts := []getty.ServerOption{getty.WithLocalAddress(addr)}
 	if s.conf.SSLEnabled {
 		serverOpts = append(serverOpts, getty.WithServerSslEnabled(s.conf.SSLEnabled),
-			getty.WithServerTlsConfigBuilder(config.GetServerTlsConfigBuilder()))
+			getty.WithServerTlsConfigBuilder(s.tlsBuilder))
 	}
 
 	serverOpts = append(serverOpts, getty.WithServerTaskPool(gxsync.NewTaskPoolSimple(s.conf.GrPoolSize)))
@@ -19,6 +19,7 @@ package getty
 
 import (
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -92,10 +93,15 @@ func (h *RpcClientHandler) OnError(session getty.Session, err error) {
 func (h *RpcClientHandler) OnClose(session getty.Session) {
 	logger.Infof("session{%s} is closing......", session.Stat())
 	h.conn.removeSession(session)
+	closeChunkReader(session)
 }
 
 // OnMessage get response from getty server, and update the session to the getty client session list
 func (h *RpcClientHandler) OnMessage(session getty.Session, pkg interface{}) {
+	if _, ok := pkg.(*chunk); ok {
+		// the chunk is handled when it is read
+		return
+	}
 	result, ok := pkg.(remoting.DecodeResult)
 	if !ok {
 		logger.Errorf("illegal package")
@@ -227,10 +233,15 @@ func (h *RpcServerHandler) OnClose(session getty.Session) {
 	h.rwlock.Lock()
 	delete(h.sessionMap, session)
 	h.rwlock.Unlock()
+	closeChunkReader(session)
 }
 
 // OnMessage get request from getty client, update the session reqNum and reply response to client
 func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
+	if _, ok := pkg.(*chunk); ok {
+		// the chunk is handled when it is read
+		return
+	}
 	h.rwlock.Lock()
 	if _, ok := h.sessionMap[session]; ok {
 		h.sessionMap[session].reqNum++
@@ -270,6 +281,22 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 		return
 	}
 
+	if closer, ok := req.Stream.(io.Closer); ok {
+		// the chunks which are not read by service are discarded
+		defer closer.Close()
+	}
+
+	// the request can not be decoded, e.g. its serialization is unknown
+	if err, ok := req.Data.(error); ok {
+		logger.Errorf("bad request{%#v}: %v", req, err)
+		resp.Status = hessian.Response_BAD_REQUEST
+		resp.Error = err
+		if req.TwoWay {
+			reply(session, resp)
+		}
+		return
+	}
+
 	defer func() {
 		if e := recover(); e != nil {
 			resp.Status = hessian.Response_SERVER_ERROR
@@ -298,6 +325,9 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	attachments := invoc.Attachments()
 	attachments[constant.LOCAL_ADDR] = session.LocalAddr()
 	attachments[constant.REMOTE_ADDR] = session.RemoteAddr()
+	if req.Stream != nil {
+		invoc.SetAttribute(constant.STREAM_KEY, req.Stream)
+	}
 
 	result := h.server.requestHandler(invoc)
 	if !req.TwoWay {
@@ -50,6 +50,9 @@ func NewRpcClientPackageHandler(client *Client) *RpcClientPackageHandler {
 // Read data from server. if the package size from server is larger than 4096 byte, server will read 4096 byte
 // and send to client each time. the Read can assemble it.
 func (p *RpcClientPackageHandler) Read(ss getty.Session, data []byte) (interface{}, int, error) {
+	if isChunk(data) {
+		return readChunk(ss, p.client.codec, p.client.conf.GettySessionParam, data)
+	}
 	resp, length, err := (p.client.codec).Decode(data)
 	// err := pkg.Unmarshal(buf, p.client)
 	if err != nil {
@@ -74,7 +77,7 @@ func (p *RpcClientPackageHandler) Write(ss getty.Session, pkg interface{}) ([]by
 			logger.Warnf("binary.Write(req{%#v}) = err{%#v}", req, perrors.WithStack(err))
 			return nil, perrors.WithStack(err)
 		}
-		return buf.Bytes(), nil
+		return writeRequest(req, buf.Bytes(), p.client.conf.GettySessionParam.ChunkSize), nil
 	}
 
 	res, ok := pkg.(*remoting.Response)
@@ -84,7 +87,11 @@ func (p *RpcClientPackageHandler) Write(ss getty.Session, pkg interface{}) ([]by
 			logger.Warnf("binary.Write(res{%#v}) = err{%#v}", req, perrors.WithStack(err))
 			return nil, perrors.WithStack(err)
 		}
-		return buf.Bytes(), nil
+		return splitPackage(res.ID, buf.Bytes(), p.client.conf.GettySessionParam.ChunkSize), nil
+	}
+
+	if c, ok := pkg.(*chunk); ok {
+		return c.encode(nil), nil
 	}
 
 	logger.Errorf("illegal pkg:%+v\n", pkg)
@@ -111,6 +118,9 @@ func NewRpcServerPackageHandler(server *Server) *RpcServerPackageHandler {
 // Read data from client. if the package size from client is larger than 4096 byte, client will read 4096 byte
 // and send to client each time. the Read can assemble it.
 func (p *RpcServerPackageHandler) Read(ss getty.Session, data []byte) (interface{}, int, error) {
+	if isChunk(data) {
+		return readChunk(ss, p.server.codec, p.server.conf.GettySessionParam, data)
+	}
 	req, length, err := (p.server.codec).Decode(data)
 	// resp,len, err := (*p.).DecodeResponse(buf)
 	if err != nil {
@@ -122,6 +132,12 @@ func (p *RpcServerPackageHandler) Read(ss getty.Session, data []byte) (interface
 
 		return nil, 0, err
 	}
+	if ss != nil {
+		if r, ok := ss.GetAttribute(chunkReaderAttrKey).(*chunkReader); ok {
+			// the stream announced before the request
+			req = r.attachStream(req)
+		}
+	}
 
 	return req, length, err
 }
@@ -135,7 +151,7 @@ func (p *RpcServerPackageHandler) Write(ss getty.Session, pkg interface{}) ([]by
 			logger.Warnf("binary.Write(res{%#v}) = err{%#v}", res, perrors.WithStack(err))
 			return nil, perrors.WithStack(err)
 		}
-		return buf.Bytes(), nil
+		return splitPackage(res.ID, buf.Bytes(), p.server.conf.GettySessionParam.ChunkSize), nil
 	}
 
 	req, ok := pkg.(*remoting.Request)
@@ -145,9 +161,36 @@ func (p *RpcServerPackageHandler) Write(ss getty.Session, pkg interface{}) ([]by
 			logger.Warnf("binary.Write(req{%#v}) = err{%#v}", res, perrors.WithStack(err))
 			return nil, perrors.WithStack(err)
 		}
-		return buf.Bytes(), nil
+		return writeRequest(req, buf.Bytes(), p.server.conf.GettySessionParam.ChunkSize), nil
 	}
 
 	logger.Errorf("illegal pkg:%+v\n, it is %+v", pkg, reflect.TypeOf(pkg))
 	return nil, perrors.New("invalid rpc response")
 }
+
+// readChunk reads a chunk by the chunkReader of session
+func readChunk(ss getty.Session, codec remoting.Codec, param GettySessionParam, data []byte) (interface{}, int, error) {
+	pkg, length, err := getChunkReader(ss, codec, param.PkgWQSize).read(data)
+	if err != nil {
+		if errors.Is(err, hessian.ErrHeaderNotEnough) || errors.Is(err, hessian.ErrBodyNotEnough) {
+			return nil, 0, nil
+		}
+
+		logger.Errorf("readChunk(ss:%+v, len(@data):%d) = error:%+v", ss, len(data), err)
+
+		return nil, length, err
+	}
+
+	return pkg, length, nil
+}
+
+// writeRequest returns the bytes of encoded request @pkg, which is split into chunks if it is larger
+// than @chunkSize, and the stream of request is announced before it.
+func writeRequest(req *remoting.Request, pkg []byte, chunkSize int) []byte {
+	pkg = splitPackage(req.ID, pkg, chunkSize)
+	if req.Stream == nil {
+		return pkg
+	}
+	start := &chunk{id: req.ID, flags: chunkFlagStream | chunkFlagStreamStart}
+	return append(start.encode(make([]byte, 0, chunkHeaderLength+len(pkg))), pkg...)
+}